@@ -10,6 +10,11 @@ import (
 	"time"
 
 	"github.com/EduardoMark/gobid/internal/api"
+	"github.com/EduardoMark/gobid/internal/auth/oidc"
+	"github.com/EduardoMark/gobid/internal/auth/token"
+	"github.com/EduardoMark/gobid/internal/fees"
+	"github.com/EduardoMark/gobid/internal/mailer"
+	"github.com/EduardoMark/gobid/internal/storage"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/joho/godotenv"
 )
@@ -38,8 +43,44 @@ func main() {
 		log.Fatalf("Database connection test failed: %v", err)
 	}
 
+	feeSchedule, err := fees.LoadSchedule()
+	if err != nil {
+		log.Fatalf("Failed to load fee schedule: %v", err)
+	}
+
+	jwtConfig, err := token.LoadConfig()
+	if err != nil {
+		log.Fatalf("Failed to load JWT configuration: %v", err)
+	}
+
+	mail, err := mailer.Load()
+	if err != nil {
+		log.Fatalf("Failed to configure mailer: %v", err)
+	}
+
+	oidcConfig, err := oidc.LoadConfig()
+	if err != nil {
+		log.Fatalf("Failed to load OIDC configuration: %v", err)
+	}
+
+	blobs, err := storage.Load()
+	if err != nil {
+		log.Fatalf("Failed to configure blob storage: %v", err)
+	}
+
+	publicURL, err := api.LoadPublicURL()
+	if err != nil {
+		log.Fatalf("Failed to load public URL: %v", err)
+	}
+
 	apiConfig := api.Config{
 		DBPool: pool,
+		Fees:   feeSchedule,
+		Jwt:    jwtConfig,
+		Mailer: mail,
+		OIDC:   oidcConfig,
+		Blobs:  blobs,
+		URL:    publicURL,
 	}
 	r := api.BindRoutes(apiConfig)
 
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/EduardoMark/gobid/internal/audit"
+	"github.com/EduardoMark/gobid/internal/storage"
+	"github.com/EduardoMark/gobid/internal/users"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/joho/godotenv"
+	"github.com/sirupsen/logrus"
+)
+
+func main() {
+	if err := godotenv.Load(); err != nil {
+		log.Fatalf("Failed to load environment variables: %v", err)
+	}
+
+	ctx := context.TODO()
+
+	dsn := fmt.Sprintf("user=%s password=%s host=%s port=%s dbname=%s",
+		os.Getenv("GOBID_DATABASE_USER"),
+		os.Getenv("GOBID_DATABASE_PASSWORD"),
+		os.Getenv("GOBID_DATABASE_HOST"),
+		os.Getenv("GOBID_DATABASE_PORT"),
+		os.Getenv("GOBID_DATABASE_NAME"),
+	)
+
+	pool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		log.Fatalf("Failed to connect to the database: %v", err)
+	}
+	defer pool.Close()
+
+	blobs, err := storage.Load()
+	if err != nil {
+		log.Fatalf("Failed to configure blob storage: %v", err)
+	}
+
+	count, err := users.NewUserService(pool, blobs, audit.NewAuditService(pool)).PurgeDeletedAccounts(ctx)
+	if err != nil {
+		logrus.WithField("err", err.Error()).Error("Failed to purge deleted accounts")
+		return
+	}
+
+	logrus.WithField("users", count).Info("Deleted accounts anonymised successfully.")
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/EduardoMark/gobid/internal/api"
+	"github.com/EduardoMark/gobid/internal/exports"
+	"github.com/EduardoMark/gobid/internal/mailer"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/joho/godotenv"
+	"github.com/sirupsen/logrus"
+)
+
+func main() {
+	if err := godotenv.Load(); err != nil {
+		log.Fatalf("Failed to load environment variables: %v", err)
+	}
+
+	ctx := context.TODO()
+
+	dsn := fmt.Sprintf("user=%s password=%s host=%s port=%s dbname=%s",
+		os.Getenv("GOBID_DATABASE_USER"),
+		os.Getenv("GOBID_DATABASE_PASSWORD"),
+		os.Getenv("GOBID_DATABASE_HOST"),
+		os.Getenv("GOBID_DATABASE_PORT"),
+		os.Getenv("GOBID_DATABASE_NAME"),
+	)
+
+	pool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		log.Fatalf("Failed to connect to the database: %v", err)
+	}
+	defer pool.Close()
+
+	mail, err := mailer.Load()
+	if err != nil {
+		log.Fatalf("Failed to configure mailer: %v", err)
+	}
+
+	publicURL, err := api.LoadPublicURL()
+	if err != nil {
+		log.Fatalf("Failed to load public URL: %v", err)
+	}
+
+	count, err := exports.NewExportService(pool, mail, publicURL).Sweep(ctx)
+	if err != nil {
+		logrus.WithField("err", err.Error()).Error("Failed to sweep data exports")
+		return
+	}
+
+	logrus.WithField("failed", count).Info("Data exports swept successfully.")
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/EduardoMark/gobid/internal/payouts"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/joho/godotenv"
+	"github.com/sirupsen/logrus"
+)
+
+func main() {
+	outDir := flag.String("out", "./payouts", "directory where the payout CSV is written")
+	flag.Parse()
+
+	if err := godotenv.Load(); err != nil {
+		log.Fatalf("Failed to load environment variables: %v", err)
+	}
+
+	ctx := context.TODO()
+
+	dsn := fmt.Sprintf("user=%s password=%s host=%s port=%s dbname=%s",
+		os.Getenv("GOBID_DATABASE_USER"),
+		os.Getenv("GOBID_DATABASE_PASSWORD"),
+		os.Getenv("GOBID_DATABASE_HOST"),
+		os.Getenv("GOBID_DATABASE_PORT"),
+		os.Getenv("GOBID_DATABASE_NAME"),
+	)
+
+	pool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		log.Fatalf("Failed to connect to the database: %v", err)
+	}
+	defer pool.Close()
+
+	if err := os.MkdirAll(*outDir, 0o755); err != nil {
+		log.Fatalf("Failed to create output directory: %v", err)
+	}
+
+	path := filepath.Join(*outDir, fmt.Sprintf("payouts_%s.csv", time.Now().UTC().Format("20060102T150405Z")))
+
+	file, err := os.Create(path)
+	if err != nil {
+		log.Fatalf("Failed to create payout file: %v", err)
+	}
+
+	count, err := payouts.NewPayoutService(pool).RunBatch(ctx, file)
+	file.Close()
+	if errors.Is(err, payouts.ErrBatchRunning) {
+		os.Remove(path)
+		logrus.Warn("Another payout batch is running, nothing was exported")
+		return
+	}
+
+	if err != nil {
+		os.Remove(path)
+		pool.Close()
+		logrus.WithField("err", err.Error()).Fatal("Failed to run payout batch, unexported payouts will be retried on the next run")
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"file":    path,
+		"payouts": count,
+	}).Info("Payout batch completed successfully.")
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/EduardoMark/gobid/internal/audit"
+	"github.com/EduardoMark/gobid/internal/disputes"
+	"github.com/EduardoMark/gobid/internal/payments"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/joho/godotenv"
+	"github.com/sirupsen/logrus"
+)
+
+func main() {
+	if err := godotenv.Load(); err != nil {
+		log.Fatalf("Failed to load environment variables: %v", err)
+	}
+
+	ctx := context.TODO()
+
+	dsn := fmt.Sprintf("user=%s password=%s host=%s port=%s dbname=%s",
+		os.Getenv("GOBID_DATABASE_USER"),
+		os.Getenv("GOBID_DATABASE_PASSWORD"),
+		os.Getenv("GOBID_DATABASE_HOST"),
+		os.Getenv("GOBID_DATABASE_PORT"),
+		os.Getenv("GOBID_DATABASE_NAME"),
+	)
+
+	pool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		log.Fatalf("Failed to connect to the database: %v", err)
+	}
+	defer pool.Close()
+
+	svc := disputes.NewDisputeService(pool, payments.NewLogProvider(), audit.NewAuditService(pool))
+
+	count, err := svc.ReconcileRefunds(ctx)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"err":    err.Error(),
+			"issued": count,
+		}).Error("Failed to reconcile pending refunds")
+		return
+	}
+
+	logrus.WithField("refunds", count).Info("Pending refunds reconciled successfully.")
+}
@@ -0,0 +1,46 @@
+package addresses
+
+import (
+	"context"
+	"time"
+
+	"github.com/EduardoMark/gobid/internal/validator"
+	"github.com/google/uuid"
+)
+
+type AddressReq struct {
+	Name       string `json:"name"`
+	Line1      string `json:"line1"`
+	Line2      string `json:"line2"`
+	City       string `json:"city"`
+	PostalCode string `json:"postal_code"`
+	Region     string `json:"region"`
+}
+
+func (r *AddressReq) Valid(ctx context.Context) validator.Evaluator {
+	var eval validator.Evaluator
+
+	eval.CheckField(validator.NotBlank(r.Name), "name", "this field cannot be blank")
+	eval.CheckField(validator.MaxChars(r.Name, 255), "name", "this field must have at most 255 characters")
+	eval.CheckField(validator.NotBlank(r.Line1), "line1", "this field cannot be blank")
+	eval.CheckField(validator.MaxChars(r.Line1, 255), "line1", "this field must have at most 255 characters")
+	eval.CheckField(validator.MaxChars(r.Line2, 255), "line2", "this field must have at most 255 characters")
+	eval.CheckField(validator.NotBlank(r.City), "city", "this field cannot be blank")
+	eval.CheckField(validator.NotBlank(r.PostalCode), "postal_code", "this field cannot be blank")
+	eval.CheckField(validator.NotBlank(r.Region), "region", "this field cannot be blank")
+	eval.CheckField(validator.MaxChars(r.Region, 10), "region", "this field must have at most 10 characters")
+
+	return eval
+}
+
+type AddressResponse struct {
+	ID         uuid.UUID `json:"id"`
+	Name       string    `json:"name"`
+	Line1      string    `json:"line1"`
+	Line2      string    `json:"line2"`
+	City       string    `json:"city"`
+	PostalCode string    `json:"postal_code"`
+	Region     string    `json:"region"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
@@ -0,0 +1,236 @@
+package addresses
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/EduardoMark/gobid/internal/api/middlewares"
+	"github.com/EduardoMark/gobid/internal/auth/session"
+	"github.com/EduardoMark/gobid/internal/auth/token"
+	"github.com/EduardoMark/gobid/internal/jsonutils"
+	"github.com/EduardoMark/gobid/internal/store/pgstore"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+type AddressHandler struct {
+	svc        Service
+	jwtService token.JwtService
+	sessions   session.Checker
+}
+
+func NewAddressHandler(svc Service, jwtService token.JwtService, sessions session.Checker) AddressHandler {
+	return AddressHandler{
+		svc:        svc,
+		jwtService: jwtService,
+		sessions:   sessions,
+	}
+}
+
+func (m *AddressHandler) RegisterAddressRoutes(r chi.Router) {
+	r.Route("/addresses", func(r chi.Router) {
+		r.Group(func(r chi.Router) {
+			r.Use(middlewares.AuthToken(m.jwtService, m.sessions))
+
+			r.Get("/", m.List)
+			r.Post("/", m.Create)
+			r.Put("/{id}", m.Update)
+			r.Delete("/{id}", m.Delete)
+		})
+	})
+}
+
+func (m *AddressHandler) Create(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, ok := ctx.Value(middlewares.UserIDKey).(string)
+	if !ok {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "user ID not found in context",
+		})
+		return
+	}
+
+	userID, err := uuid.Parse(id)
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "invalid user ID format",
+		})
+		return
+	}
+
+	data, problems, err := jsonutils.DecodeValidJson[*AddressReq](r)
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+
+	record, err := m.svc.Create(ctx, userID, data.Name, data.Line1, data.Line2, data.City, data.PostalCode, data.Region)
+	if err != nil {
+		logrus.WithField("err", err.Error()).Error("Handler.Create")
+
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "unexpected internal server error",
+		})
+		return
+	}
+
+	jsonutils.EncodeJson(w, r, http.StatusCreated, map[string]any{
+		"address": toAddressResponse(record),
+	})
+}
+
+func (m *AddressHandler) List(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, ok := ctx.Value(middlewares.UserIDKey).(string)
+	if !ok {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "user ID not found in context",
+		})
+		return
+	}
+
+	userID, err := uuid.Parse(id)
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "invalid user ID format",
+		})
+		return
+	}
+
+	records, err := m.svc.List(ctx, userID)
+	if err != nil {
+		logrus.WithField("err", err.Error()).Error("Handler.List")
+
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "unexpected internal server error",
+		})
+		return
+	}
+
+	res := make([]AddressResponse, len(records))
+	for i, record := range records {
+		res[i] = toAddressResponse(record)
+	}
+
+	jsonutils.EncodeJson(w, r, http.StatusOK, map[string]any{
+		"addresses": res,
+	})
+}
+
+func (m *AddressHandler) Update(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, ok := ctx.Value(middlewares.UserIDKey).(string)
+	if !ok {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "user ID not found in context",
+		})
+		return
+	}
+
+	userID, err := uuid.Parse(id)
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "invalid user ID format",
+		})
+		return
+	}
+
+	parsedID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "invalid address ID format",
+		})
+		return
+	}
+
+	data, problems, err := jsonutils.DecodeValidJson[*AddressReq](r)
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+
+	record, err := m.svc.Update(ctx, parsedID, userID, data.Name, data.Line1, data.Line2, data.City, data.PostalCode, data.Region)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			jsonutils.EncodeJson(w, r, http.StatusNotFound, map[string]any{
+				"error": "address not found",
+			})
+			return
+		}
+
+		logrus.WithField("err", err.Error()).Error("Handler.Update")
+
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "unexpected internal server error",
+		})
+		return
+	}
+
+	jsonutils.EncodeJson(w, r, http.StatusOK, map[string]any{
+		"address": toAddressResponse(record),
+	})
+}
+
+func (m *AddressHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, ok := ctx.Value(middlewares.UserIDKey).(string)
+	if !ok {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "user ID not found in context",
+		})
+		return
+	}
+
+	userID, err := uuid.Parse(id)
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "invalid user ID format",
+		})
+		return
+	}
+
+	parsedID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "invalid address ID format",
+		})
+		return
+	}
+
+	if err := m.svc.Delete(ctx, parsedID, userID); err != nil {
+		if errors.Is(err, ErrNotFound) {
+			jsonutils.EncodeJson(w, r, http.StatusNotFound, map[string]any{
+				"error": "address not found",
+			})
+			return
+		}
+
+		logrus.WithField("err", err.Error()).Error("Handler.Delete")
+
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "unexpected internal server error",
+		})
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func toAddressResponse(record *pgstore.Address) AddressResponse {
+	return AddressResponse{
+		ID:         record.ID,
+		Name:       record.Name,
+		Line1:      record.Line1,
+		Line2:      record.Line2,
+		City:       record.City,
+		PostalCode: record.PostalCode,
+		Region:     record.Region,
+		CreatedAt:  record.CreatedAt,
+		UpdatedAt:  record.UpdatedAt,
+	}
+}
@@ -0,0 +1,97 @@
+package addresses
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/EduardoMark/gobid/internal/store/pgstore"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type Service interface {
+	Create(ctx context.Context, userID uuid.UUID, name, line1, line2, city, postalCode, region string) (*pgstore.Address, error)
+	List(ctx context.Context, userID uuid.UUID) ([]*pgstore.Address, error)
+	Update(ctx context.Context, id, userID uuid.UUID, name, line1, line2, city, postalCode, region string) (*pgstore.Address, error)
+	Delete(ctx context.Context, id, userID uuid.UUID) error
+}
+
+type addressService struct {
+	pool *pgxpool.Pool
+	q    *pgstore.Queries
+}
+
+var ErrNotFound = errors.New("not found")
+
+func NewAddressService(pool *pgxpool.Pool) Service {
+	return &addressService{
+		pool: pool,
+		q:    pgstore.New(pool),
+	}
+}
+
+func (s *addressService) Create(ctx context.Context, userID uuid.UUID, name, line1, line2, city, postalCode, region string) (*pgstore.Address, error) {
+	record, err := s.q.CreateAddress(ctx, pgstore.CreateAddressParams{
+		UserID:     userID,
+		Name:       name,
+		Line1:      line1,
+		Line2:      line2,
+		City:       city,
+		PostalCode: postalCode,
+		Region:     strings.ToUpper(region),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("service.create: %v", err)
+	}
+
+	return record, nil
+}
+
+func (s *addressService) List(ctx context.Context, userID uuid.UUID) ([]*pgstore.Address, error) {
+	records, err := s.q.ListAddressesByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("service.list: %v", err)
+	}
+
+	return records, nil
+}
+
+func (s *addressService) Update(ctx context.Context, id, userID uuid.UUID, name, line1, line2, city, postalCode, region string) (*pgstore.Address, error) {
+	record, err := s.q.UpdateAddress(ctx, pgstore.UpdateAddressParams{
+		ID:         id,
+		UserID:     userID,
+		Name:       name,
+		Line1:      line1,
+		Line2:      line2,
+		City:       city,
+		PostalCode: postalCode,
+		Region:     strings.ToUpper(region),
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("service.update: %v", err)
+	}
+
+	return record, nil
+}
+
+func (s *addressService) Delete(ctx context.Context, id, userID uuid.UUID) error {
+	rows, err := s.q.DeleteAddress(ctx, pgstore.DeleteAddressParams{
+		ID:     id,
+		UserID: userID,
+	})
+	if err != nil {
+		return fmt.Errorf("service.delete: %v", err)
+	}
+
+	if rows == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
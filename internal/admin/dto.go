@@ -0,0 +1,119 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/EduardoMark/gobid/internal/money"
+	"github.com/EduardoMark/gobid/internal/validator"
+	"github.com/google/uuid"
+)
+
+type ReasonReq struct {
+	Reason string `json:"reason"`
+}
+
+func (r *ReasonReq) Valid(ctx context.Context) validator.Evaluator {
+	var eval validator.Evaluator
+
+	eval.CheckField(validator.NotBlank(r.Reason), "reason", "this field cannot be blank")
+	eval.CheckField(validator.MaxChars(r.Reason, 500), "reason", "this field must have at most 500 characters")
+
+	return eval
+}
+
+type SuspendReq struct {
+	Reason string     `json:"reason"`
+	Until  *time.Time `json:"until"`
+}
+
+func (r *SuspendReq) Valid(ctx context.Context) validator.Evaluator {
+	var eval validator.Evaluator
+
+	eval.CheckField(validator.NotBlank(r.Reason), "reason", "this field cannot be blank")
+	eval.CheckField(validator.MaxChars(r.Reason, 500), "reason", "this field must have at most 500 characters")
+	eval.CheckField(r.Until == nil || r.Until.After(time.Now()), "until", "this field must be in the future")
+
+	return eval
+}
+
+type TaxRuleReq struct {
+	Name      string  `json:"name"`
+	Rate      float64 `json:"rate"`
+	Inclusive bool    `json:"inclusive"`
+}
+
+func (r *TaxRuleReq) Valid(ctx context.Context) validator.Evaluator {
+	var eval validator.Evaluator
+
+	eval.CheckField(validator.NotBlank(r.Name), "name", "this field cannot be blank")
+	eval.CheckField(validator.MaxChars(r.Name, 100), "name", "this field must have at most 100 characters")
+	eval.CheckField(r.Rate >= 0 && r.Rate < 100, "rate", "this field must be between 0 and 100")
+
+	return eval
+}
+
+type UserResponse struct {
+	ID                    uuid.UUID  `json:"id"`
+	Username              string     `json:"username"`
+	Email                 string     `json:"email"`
+	EmailVerifiedAt       *time.Time `json:"email_verified_at"`
+	SuspendedAt           *time.Time `json:"suspended_at"`
+	SuspendedUntil        *time.Time `json:"suspended_until"`
+	SuspensionReason      string     `json:"suspension_reason,omitempty"`
+	Banned                bool       `json:"banned"`
+	PasswordResetRequired bool       `json:"password_reset_required"`
+	CreatedAt             time.Time  `json:"created_at"`
+	UpdatedAt             time.Time  `json:"updated_at"`
+}
+
+type ListingResponse struct {
+	ID             uuid.UUID  `json:"id"`
+	SellerID       uuid.UUID  `json:"seller_id"`
+	Name           string     `json:"name"`
+	TakenDownAt    *time.Time `json:"taken_down_at"`
+	TakedownReason string     `json:"takedown_reason"`
+}
+
+type OrderResponse struct {
+	ID          uuid.UUID    `json:"id"`
+	ProductID   uuid.UUID    `json:"product_id"`
+	BuyerID     uuid.UUID    `json:"buyer_id"`
+	SellerID    uuid.UUID    `json:"seller_id"`
+	Status      string       `json:"status"`
+	TotalAmount money.Amount `json:"total_amount"`
+	PaidAt      *time.Time   `json:"paid_at"`
+	CreatedAt   time.Time    `json:"created_at"`
+}
+
+type BidResponse struct {
+	ID        uuid.UUID    `json:"id"`
+	ProductID uuid.UUID    `json:"product_id"`
+	Amount    money.Amount `json:"amount"`
+	CreatedAt time.Time    `json:"created_at"`
+}
+
+type EventResponse struct {
+	ID         uuid.UUID       `json:"id"`
+	ActorID    *uuid.UUID      `json:"actor_id"`
+	Action     string          `json:"action"`
+	TargetType string          `json:"target_type"`
+	TargetID   *uuid.UUID      `json:"target_id"`
+	Before     json.RawMessage `json:"before"`
+	After      json.RawMessage `json:"after"`
+	Reason     string          `json:"reason"`
+	IP         string          `json:"ip"`
+	RequestID  string          `json:"request_id"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+type TaxRuleResponse struct {
+	ID        uuid.UUID `json:"id"`
+	Region    string    `json:"region"`
+	Category  string    `json:"category"`
+	Name      string    `json:"name"`
+	Rate      float64   `json:"rate"`
+	Inclusive bool      `json:"inclusive"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,604 @@
+package admin
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/EduardoMark/gobid/internal/api/middlewares"
+	"github.com/EduardoMark/gobid/internal/audit"
+	"github.com/EduardoMark/gobid/internal/auth/session"
+	"github.com/EduardoMark/gobid/internal/auth/token"
+	"github.com/EduardoMark/gobid/internal/jsonutils"
+	"github.com/EduardoMark/gobid/internal/rbac"
+	"github.com/EduardoMark/gobid/internal/store/pgstore"
+	"github.com/EduardoMark/gobid/internal/validator"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/sirupsen/logrus"
+)
+
+const defaultPageSize = 50
+const maxPageSize = 200
+
+var errInvalidQuery = errors.New("invalid query parameter")
+
+type AdminHandler struct {
+	svc        Service
+	jwtService token.JwtService
+	sessions   session.Checker
+}
+
+func NewAdminHandler(svc Service, jwtService token.JwtService, sessions session.Checker) AdminHandler {
+	return AdminHandler{
+		svc:        svc,
+		jwtService: jwtService,
+		sessions:   sessions,
+	}
+}
+
+func (m *AdminHandler) RegisterAdminRoutes(r chi.Router) {
+	r.Route("/admin", func(r chi.Router) {
+		r.Use(middlewares.AuthToken(m.jwtService, m.sessions))
+		r.Use(middlewares.RequirePermission(rbac.PermAdminAccess))
+
+		r.Get("/users", m.SearchUsers)
+		r.Get("/users/{id}", m.GetUser)
+		r.Post("/users/{id}/suspend", m.SuspendUser)
+		r.Post("/users/{id}/ban", m.BanUser)
+		r.Post("/users/{id}/unsuspend", m.UnsuspendUser)
+		r.Post("/users/{id}/force-password-reset", m.ForcePasswordReset)
+		r.Get("/users/{id}/orders", m.ListUserOrders)
+		r.Get("/users/{id}/bids", m.ListUserBids)
+		r.Post("/listings/{id}/takedown", m.TakeDownListing)
+		r.Get("/audit", m.ListEvents)
+
+		r.Group(func(r chi.Router) {
+			r.Use(middlewares.RequirePermission(rbac.PermTaxManage))
+
+			r.Get("/tax-rules", m.ListTaxRules)
+			r.Put("/tax-rules/{region}/{category}", m.SetTaxRule)
+			r.Delete("/tax-rules/{region}/{category}", m.DeleteTaxRule)
+		})
+	})
+}
+
+func (m *AdminHandler) SearchUsers(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	suspended, err := parseBool(query.Get("suspended"))
+	if err != nil {
+		writeInvalidQuery(w, r, "suspended")
+		return
+	}
+
+	verified, err := parseBool(query.Get("verified"))
+	if err != nil {
+		writeInvalidQuery(w, r, "verified")
+		return
+	}
+
+	page, ok := parsePage(w, r)
+	if !ok {
+		return
+	}
+
+	records, err := m.svc.SearchUsers(r.Context(), UserFilter{
+		Query:     query.Get("q"),
+		Role:      query.Get("role"),
+		Suspended: suspended,
+		Verified:  verified,
+	}, page)
+	if err != nil {
+		writeInternalError(w, r, "Handler.SearchUsers", err)
+		return
+	}
+
+	res := make([]UserResponse, len(records))
+	for i, record := range records {
+		res[i] = toUserResponse(record)
+	}
+
+	jsonutils.EncodeJson(w, r, http.StatusOK, map[string]any{
+		"users":  res,
+		"limit":  page.Limit,
+		"offset": page.Offset,
+	})
+}
+
+func (m *AdminHandler) GetUser(w http.ResponseWriter, r *http.Request) {
+	userID, ok := parseID(w, r)
+	if !ok {
+		return
+	}
+
+	record, err := m.svc.GetUser(r.Context(), userID)
+	if err != nil {
+		m.writeError(w, r, "Handler.GetUser", err)
+		return
+	}
+
+	jsonutils.EncodeJson(w, r, http.StatusOK, map[string]any{
+		"user": toUserResponse(record),
+	})
+}
+
+func (m *AdminHandler) SuspendUser(w http.ResponseWriter, r *http.Request) {
+	actorID, userID, data, ok := parseAction[*SuspendReq](w, r)
+	if !ok {
+		return
+	}
+
+	if err := m.svc.SuspendUser(r.Context(), actorID, userID, data.Until, data.Reason); err != nil {
+		m.writeError(w, r, "Handler.SuspendUser", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (m *AdminHandler) BanUser(w http.ResponseWriter, r *http.Request) {
+	actorID, userID, data, ok := parseAction[*ReasonReq](w, r)
+	if !ok {
+		return
+	}
+
+	if err := m.svc.BanUser(r.Context(), actorID, userID, data.Reason); err != nil {
+		m.writeError(w, r, "Handler.BanUser", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (m *AdminHandler) UnsuspendUser(w http.ResponseWriter, r *http.Request) {
+	actorID, userID, data, ok := parseAction[*ReasonReq](w, r)
+	if !ok {
+		return
+	}
+
+	if err := m.svc.UnsuspendUser(r.Context(), actorID, userID, data.Reason); err != nil {
+		m.writeError(w, r, "Handler.UnsuspendUser", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (m *AdminHandler) ForcePasswordReset(w http.ResponseWriter, r *http.Request) {
+	actorID, userID, data, ok := parseAction[*ReasonReq](w, r)
+	if !ok {
+		return
+	}
+
+	if err := m.svc.ForcePasswordReset(r.Context(), actorID, userID, data.Reason); err != nil {
+		m.writeError(w, r, "Handler.ForcePasswordReset", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (m *AdminHandler) ListUserOrders(w http.ResponseWriter, r *http.Request) {
+	userID, ok := parseID(w, r)
+	if !ok {
+		return
+	}
+
+	page, ok := parsePage(w, r)
+	if !ok {
+		return
+	}
+
+	records, err := m.svc.ListUserOrders(r.Context(), userID, page)
+	if err != nil {
+		m.writeError(w, r, "Handler.ListUserOrders", err)
+		return
+	}
+
+	res := make([]OrderResponse, len(records))
+	for i, record := range records {
+		res[i] = OrderResponse{
+			ID:          record.ID,
+			ProductID:   record.ProductID,
+			BuyerID:     record.BuyerID,
+			SellerID:    record.SellerID,
+			Status:      record.Status,
+			TotalAmount: record.TotalAmount,
+			CreatedAt:   record.CreatedAt,
+		}
+		if record.PaidAt.Valid {
+			res[i].PaidAt = &record.PaidAt.Time
+		}
+	}
+
+	jsonutils.EncodeJson(w, r, http.StatusOK, map[string]any{
+		"orders": res,
+		"limit":  page.Limit,
+		"offset": page.Offset,
+	})
+}
+
+func (m *AdminHandler) ListUserBids(w http.ResponseWriter, r *http.Request) {
+	userID, ok := parseID(w, r)
+	if !ok {
+		return
+	}
+
+	page, ok := parsePage(w, r)
+	if !ok {
+		return
+	}
+
+	if _, err := m.svc.GetUser(r.Context(), userID); err != nil {
+		m.writeError(w, r, "Handler.ListUserBids", err)
+		return
+	}
+
+	jsonutils.EncodeJson(w, r, http.StatusOK, map[string]any{
+		"bids":     []BidResponse{},
+		"recorded": false,
+		"note":     "bids are not persisted yet, so this list is always empty",
+		"limit":    page.Limit,
+		"offset":   page.Offset,
+	})
+}
+
+func (m *AdminHandler) TakeDownListing(w http.ResponseWriter, r *http.Request) {
+	actorID, productID, data, ok := parseAction[*ReasonReq](w, r)
+	if !ok {
+		return
+	}
+
+	product, err := m.svc.TakeDownListing(r.Context(), actorID, productID, data.Reason)
+	if err != nil {
+		m.writeError(w, r, "Handler.TakeDownListing", err)
+		return
+	}
+
+	res := ListingResponse{
+		ID:             product.ID,
+		SellerID:       product.SellerID,
+		Name:           product.Name,
+		TakedownReason: product.TakedownReason,
+	}
+	if product.TakenDownAt.Valid {
+		res.TakenDownAt = &product.TakenDownAt.Time
+	}
+
+	jsonutils.EncodeJson(w, r, http.StatusOK, map[string]any{
+		"listing": res,
+	})
+}
+
+func (m *AdminHandler) ListEvents(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	filter := audit.Filter{
+		TargetType: query.Get("target_type"),
+		Action:     query.Get("action"),
+		IP:         query.Get("ip"),
+		RequestID:  query.Get("request_id"),
+	}
+
+	for key, dst := range map[string]*uuid.UUID{"actor_id": &filter.ActorID, "target_id": &filter.TargetID} {
+		if raw := query.Get(key); raw != "" {
+			parsed, err := uuid.Parse(raw)
+			if err != nil {
+				writeInvalidQuery(w, r, key)
+				return
+			}
+			*dst = parsed
+		}
+	}
+
+	for key, dst := range map[string]**time.Time{"since": &filter.Since, "until": &filter.Until} {
+		if raw := query.Get(key); raw != "" {
+			parsed, err := time.Parse(time.RFC3339, raw)
+			if err != nil {
+				writeInvalidQuery(w, r, key)
+				return
+			}
+			*dst = &parsed
+		}
+	}
+
+	page, ok := parsePage(w, r)
+	if !ok {
+		return
+	}
+
+	records, err := m.svc.ListEvents(r.Context(), filter, page)
+	if err != nil {
+		writeInternalError(w, r, "Handler.ListEvents", err)
+		return
+	}
+
+	res := make([]EventResponse, len(records))
+	for i, record := range records {
+		res[i] = toEventResponse(record)
+	}
+
+	jsonutils.EncodeJson(w, r, http.StatusOK, map[string]any{
+		"events": res,
+		"limit":  page.Limit,
+		"offset": page.Offset,
+	})
+}
+
+func parseAction[T validator.Validator](w http.ResponseWriter, r *http.Request) (uuid.UUID, uuid.UUID, T, bool) {
+	var zero T
+
+	actorID, ok := parseActor(w, r)
+	if !ok {
+		return uuid.UUID{}, uuid.UUID{}, zero, false
+	}
+
+	targetID, ok := parseID(w, r)
+	if !ok {
+		return uuid.UUID{}, uuid.UUID{}, zero, false
+	}
+
+	data, problems, err := jsonutils.DecodeValidJson[T](r)
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return uuid.UUID{}, uuid.UUID{}, zero, false
+	}
+
+	return actorID, targetID, data, true
+}
+
+func (m *AdminHandler) ListTaxRules(w http.ResponseWriter, r *http.Request) {
+	records, err := m.svc.ListTaxRules(r.Context())
+	if err != nil {
+		writeInternalError(w, r, "Handler.ListTaxRules", err)
+		return
+	}
+
+	res := make([]TaxRuleResponse, len(records))
+	for i, record := range records {
+		res[i] = toTaxRuleResponse(record)
+	}
+
+	jsonutils.EncodeJson(w, r, http.StatusOK, map[string]any{
+		"tax_rules": res,
+	})
+}
+
+func (m *AdminHandler) SetTaxRule(w http.ResponseWriter, r *http.Request) {
+	actorID, ok := parseActor(w, r)
+	if !ok {
+		return
+	}
+
+	region, category, ok := parseTaxRuleKey(w, r)
+	if !ok {
+		return
+	}
+
+	data, problems, err := jsonutils.DecodeValidJson[*TaxRuleReq](r)
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+
+	record, err := m.svc.SetTaxRule(r.Context(), actorID, region, category, data.Name, data.Rate, data.Inclusive)
+	if err != nil {
+		m.writeError(w, r, "Handler.SetTaxRule", err)
+		return
+	}
+
+	jsonutils.EncodeJson(w, r, http.StatusOK, map[string]any{
+		"tax_rule": toTaxRuleResponse(record),
+	})
+}
+
+func (m *AdminHandler) DeleteTaxRule(w http.ResponseWriter, r *http.Request) {
+	actorID, ok := parseActor(w, r)
+	if !ok {
+		return
+	}
+
+	region, category, ok := parseTaxRuleKey(w, r)
+	if !ok {
+		return
+	}
+
+	if err := m.svc.DeleteTaxRule(r.Context(), actorID, region, category); err != nil {
+		m.writeError(w, r, "Handler.DeleteTaxRule", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func parseTaxRuleKey(w http.ResponseWriter, r *http.Request) (string, string, bool) {
+	region := strings.ToUpper(chi.URLParam(r, "region"))
+	category := chi.URLParam(r, "category")
+
+	if region == "" || len(region) > 10 || category == "" || len(category) > 50 {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "invalid tax rule region or category",
+		})
+		return "", "", false
+	}
+
+	return region, category, true
+}
+
+func toTaxRuleResponse(record *pgstore.TaxRule) TaxRuleResponse {
+	return TaxRuleResponse{
+		ID:        record.ID,
+		Region:    record.Region,
+		Category:  record.Category,
+		Name:      record.Name,
+		Rate:      record.Rate,
+		Inclusive: record.Inclusive,
+		CreatedAt: record.CreatedAt,
+	}
+}
+
+func (m *AdminHandler) writeError(w http.ResponseWriter, r *http.Request, op string, err error) {
+	switch {
+	case errors.Is(err, ErrNotFound):
+		jsonutils.EncodeJson(w, r, http.StatusNotFound, map[string]any{
+			"error": "not found",
+		})
+	case errors.Is(err, ErrAlreadyBanned):
+		jsonutils.EncodeJson(w, r, http.StatusConflict, map[string]any{
+			"error": "user already banned",
+		})
+	case errors.Is(err, ErrNotSuspended):
+		jsonutils.EncodeJson(w, r, http.StatusConflict, map[string]any{
+			"error": "user not suspended",
+		})
+	case errors.Is(err, ErrAlreadyTakenDown):
+		jsonutils.EncodeJson(w, r, http.StatusConflict, map[string]any{
+			"error": "listing already taken down",
+		})
+	case errors.Is(err, ErrSelfAction):
+		jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]any{
+			"error": "admins cannot act on their own account",
+		})
+	default:
+		writeInternalError(w, r, op, err)
+	}
+}
+
+func writeInternalError(w http.ResponseWriter, r *http.Request, op string, err error) {
+	logrus.WithField("err", err.Error()).Error(op)
+
+	jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+		"error": "unexpected internal server error",
+	})
+}
+
+func writeInvalidQuery(w http.ResponseWriter, r *http.Request, key string) {
+	jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+		"error": "invalid value for query parameter " + key,
+	})
+}
+
+func parseActor(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	actor, ok := r.Context().Value(middlewares.UserIDKey).(string)
+	if !ok {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "user ID not found in context",
+		})
+		return uuid.UUID{}, false
+	}
+
+	actorID, err := uuid.Parse(actor)
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "invalid user ID format",
+		})
+		return uuid.UUID{}, false
+	}
+
+	return actorID, true
+}
+
+func parseID(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "invalid uuid type",
+		})
+		return uuid.UUID{}, false
+	}
+
+	return id, true
+}
+
+func parsePage(w http.ResponseWriter, r *http.Request) (Page, bool) {
+	page := Page{Limit: defaultPageSize}
+	query := r.URL.Query()
+
+	if raw := query.Get("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit < 1 || limit > maxPageSize {
+			writeInvalidQuery(w, r, "limit")
+			return Page{}, false
+		}
+		page.Limit = int32(limit)
+	}
+
+	if raw := query.Get("offset"); raw != "" {
+		offset, err := strconv.Atoi(raw)
+		if err != nil || offset < 0 {
+			writeInvalidQuery(w, r, "offset")
+			return Page{}, false
+		}
+		page.Offset = int32(offset)
+	}
+
+	return page, true
+}
+
+func parseBool(raw string) (pgtype.Bool, error) {
+	if raw == "" {
+		return pgtype.Bool{}, nil
+	}
+
+	value, err := strconv.ParseBool(raw)
+	if err != nil {
+		return pgtype.Bool{}, errInvalidQuery
+	}
+
+	return pgtype.Bool{Bool: value, Valid: true}, nil
+}
+
+func toUserResponse(record *pgstore.User) UserResponse {
+	res := UserResponse{
+		ID:                    record.ID,
+		Username:              record.Username,
+		Email:                 record.Email,
+		SuspensionReason:      record.SuspensionReason,
+		Banned:                record.Banned,
+		PasswordResetRequired: record.PasswordResetRequired,
+		CreatedAt:             record.CreatedAt,
+		UpdatedAt:             record.UpdatedAt,
+	}
+
+	if record.EmailVerifiedAt.Valid {
+		res.EmailVerifiedAt = &record.EmailVerifiedAt.Time
+	}
+
+	if record.SuspendedAt.Valid {
+		res.SuspendedAt = &record.SuspendedAt.Time
+	}
+
+	if record.SuspendedUntil.Valid {
+		res.SuspendedUntil = &record.SuspendedUntil.Time
+	}
+
+	return res
+}
+
+func toEventResponse(record *pgstore.AuditEvent) EventResponse {
+	res := EventResponse{
+		ID:         record.ID,
+		Action:     record.Action,
+		TargetType: record.TargetType,
+		Before:     record.Before,
+		After:      record.After,
+		Reason:     record.Reason,
+		IP:         record.Ip,
+		RequestID:  record.RequestID,
+		CreatedAt:  record.CreatedAt,
+	}
+	if record.ActorID.Valid {
+		actorID := uuid.UUID(record.ActorID.Bytes)
+		res.ActorID = &actorID
+	}
+	if record.TargetID.Valid {
+		targetID := uuid.UUID(record.TargetID.Bytes)
+		res.TargetID = &targetID
+	}
+
+	return res
+}
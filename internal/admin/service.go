@@ -0,0 +1,516 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/EduardoMark/gobid/internal/audit"
+	"github.com/EduardoMark/gobid/internal/mailer"
+	"github.com/EduardoMark/gobid/internal/store/pgstore"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/sirupsen/logrus"
+)
+
+type UserFilter struct {
+	Query     string
+	Role      string
+	Suspended pgtype.Bool
+	Verified  pgtype.Bool
+}
+
+type Page struct {
+	Limit  int32
+	Offset int32
+}
+
+type PasswordResetter interface {
+	ForcePasswordReset(ctx context.Context, userID uuid.UUID) error
+}
+
+type Service interface {
+	SearchUsers(ctx context.Context, filter UserFilter, page Page) ([]*pgstore.User, error)
+	GetUser(ctx context.Context, id uuid.UUID) (*pgstore.User, error)
+	SuspendUser(ctx context.Context, actorID, userID uuid.UUID, until *time.Time, reason string) error
+	BanUser(ctx context.Context, actorID, userID uuid.UUID, reason string) error
+	UnsuspendUser(ctx context.Context, actorID, userID uuid.UUID, reason string) error
+	ReleaseExpiredSuspensions(ctx context.Context) (int, error)
+	ForcePasswordReset(ctx context.Context, actorID, userID uuid.UUID, reason string) error
+	TakeDownListing(ctx context.Context, actorID, productID uuid.UUID, reason string) (*pgstore.Product, error)
+	ListUserOrders(ctx context.Context, userID uuid.UUID, page Page) ([]*pgstore.Order, error)
+	ListEvents(ctx context.Context, filter audit.Filter, page Page) ([]*pgstore.AuditEvent, error)
+	ListTaxRules(ctx context.Context) ([]*pgstore.TaxRule, error)
+	SetTaxRule(ctx context.Context, actorID uuid.UUID, region, category, name string, rate float64, inclusive bool) (*pgstore.TaxRule, error)
+	DeleteTaxRule(ctx context.Context, actorID uuid.UUID, region, category string) error
+}
+
+type adminService struct {
+	pool      *pgxpool.Pool
+	q         *pgstore.Queries
+	passwords PasswordResetter
+	mailer    mailer.Mailer
+	audit     audit.Service
+}
+
+var ErrNotFound = errors.New("not found")
+var ErrAlreadyBanned = errors.New("user already banned")
+var ErrNotSuspended = errors.New("user not suspended")
+var ErrSelfAction = errors.New("admins cannot act on their own account")
+var ErrAlreadyTakenDown = errors.New("listing already taken down")
+
+func NewAdminService(pool *pgxpool.Pool, passwords PasswordResetter, mailer mailer.Mailer, audits audit.Service) Service {
+	return &adminService{
+		pool:      pool,
+		q:         pgstore.New(pool),
+		passwords: passwords,
+		mailer:    mailer,
+		audit:     audits,
+	}
+}
+
+func (s *adminService) SearchUsers(ctx context.Context, filter UserFilter, page Page) ([]*pgstore.User, error) {
+	records, err := s.q.SearchUsers(ctx, pgstore.SearchUsersParams{
+		Query:     filter.Query,
+		Role:      filter.Role,
+		Suspended: filter.Suspended,
+		Verified:  filter.Verified,
+		Limit:     page.Limit,
+		Offset:    page.Offset,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("service.searchUsers: %v", err)
+	}
+
+	return records, nil
+}
+
+func (s *adminService) GetUser(ctx context.Context, id uuid.UUID) (*pgstore.User, error) {
+	record, err := s.q.GetUserByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("service.getUser: %v", err)
+	}
+
+	return record, nil
+}
+
+func (s *adminService) SuspendUser(ctx context.Context, actorID, userID uuid.UUID, until *time.Time, reason string) error {
+	return s.restrict(ctx, actorID, userID, audit.ActionSuspendUser, reason, func(qtx *pgstore.Queries) (int64, error) {
+		params := pgstore.SuspendUserParams{
+			ID:               userID,
+			SuspensionReason: reason,
+		}
+		if until != nil {
+			params.SuspendedUntil = pgtype.Timestamptz{Time: *until, Valid: true}
+		}
+
+		return qtx.SuspendUser(ctx, params)
+	})
+}
+
+func (s *adminService) BanUser(ctx context.Context, actorID, userID uuid.UUID, reason string) error {
+	return s.restrict(ctx, actorID, userID, audit.ActionBanUser, reason, func(qtx *pgstore.Queries) (int64, error) {
+		return qtx.BanUser(ctx, pgstore.BanUserParams{
+			ID:               userID,
+			SuspensionReason: reason,
+		})
+	})
+}
+
+func (s *adminService) restrict(ctx context.Context, actorID, userID uuid.UUID, action, reason string, apply func(qtx *pgstore.Queries) (int64, error)) error {
+	if actorID == userID {
+		return ErrSelfAction
+	}
+
+	before, err := s.GetUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("service.restrict: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.q.WithTx(tx)
+
+	rows, err := apply(qtx)
+	if err != nil {
+		return fmt.Errorf("service.restrict: %v", err)
+	}
+
+	if rows == 0 {
+		return ErrAlreadyBanned
+	}
+
+	if _, err := qtx.FreezeSellerAuctions(ctx, userID); err != nil {
+		return fmt.Errorf("service.restrict: %v", err)
+	}
+
+	if err := qtx.RevokeUserSessions(ctx, userID); err != nil {
+		return fmt.Errorf("service.restrict: %v", err)
+	}
+
+	if err := qtx.RevokeUserRefreshTokens(ctx, userID); err != nil {
+		return fmt.Errorf("service.restrict: %v", err)
+	}
+
+	if err := qtx.InvalidateUserAccessTokens(ctx, userID); err != nil {
+		return fmt.Errorf("service.restrict: %v", err)
+	}
+
+	user, err := qtx.GetUserByID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("service.restrict: %v", err)
+	}
+
+	err = s.audit.WithTx(tx).Record(ctx, audit.Event{
+		ActorID:    actorID,
+		Action:     action,
+		TargetType: audit.TargetUser,
+		TargetID:   userID,
+		Before:     restriction(before),
+		After:      restriction(user),
+		Reason:     reason,
+	})
+	if err != nil {
+		return fmt.Errorf("service.restrict: %v", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("service.restrict: %v", err)
+	}
+
+	s.notify(ctx, user, restrictionNotice(user))
+
+	return nil
+}
+
+func (s *adminService) UnsuspendUser(ctx context.Context, actorID, userID uuid.UUID, reason string) error {
+	if _, err := s.GetUser(ctx, userID); err != nil {
+		return err
+	}
+
+	if err := s.lift(ctx, actorID, userID, reason); err != nil {
+		return fmt.Errorf("service.unsuspendUser: %w", err)
+	}
+
+	return nil
+}
+
+func (s *adminService) ReleaseExpiredSuspensions(ctx context.Context) (int, error) {
+	userIDs, err := s.q.ListExpiredSuspensions(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("service.releaseExpiredSuspensions: %v", err)
+	}
+
+	released := 0
+	for _, userID := range userIDs {
+		err := s.lift(ctx, uuid.Nil, userID, "suspension period ended")
+		if errors.Is(err, ErrNotSuspended) {
+			continue
+		}
+		if err != nil {
+			return released, fmt.Errorf("service.releaseExpiredSuspensions: %v", err)
+		}
+		released++
+	}
+
+	return released, nil
+}
+
+func (s *adminService) lift(ctx context.Context, actorID, userID uuid.UUID, reason string) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.q.WithTx(tx)
+
+	before, err := qtx.GetUserByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := qtx.UnsuspendUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return ErrNotSuspended
+	}
+
+	if _, err := qtx.UnfreezeSellerAuctions(ctx, userID); err != nil {
+		return err
+	}
+
+	user, err := qtx.GetUserByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	err = s.audit.WithTx(tx).Record(ctx, audit.Event{
+		ActorID:    actorID,
+		Action:     audit.ActionUnsuspendUser,
+		TargetType: audit.TargetUser,
+		TargetID:   userID,
+		Before:     restriction(before),
+		After:      restriction(user),
+		Reason:     reason,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+
+	s.notify(ctx, user, "Your gobid account has been reinstated. You can sign in again, and any auctions that were frozen have resumed with their remaining time restored.")
+
+	return nil
+}
+
+func (s *adminService) notify(ctx context.Context, user *pgstore.User, body string) {
+	err := s.mailer.Send(ctx, mailer.Message{
+		To:      user.Email,
+		Subject: "Your gobid account status has changed",
+		Body:    fmt.Sprintf("Hi %s,\n\n%s\n", user.Username, body),
+	})
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"err":     err.Error(),
+			"user_id": user.ID,
+		}).Error("adminService.notify")
+	}
+}
+
+func restriction(user *pgstore.User) map[string]any {
+	state := map[string]any{
+		"suspended":       user.SuspendedAt.Valid,
+		"suspended_until": nil,
+		"banned":          user.Banned,
+	}
+	if user.SuspendedUntil.Valid {
+		state["suspended_until"] = user.SuspendedUntil.Time
+	}
+
+	return state
+}
+
+func restrictionNotice(user *pgstore.User) string {
+	switch {
+	case user.Banned:
+		return fmt.Sprintf("Your gobid account has been permanently banned.\n\nReason: %s", user.SuspensionReason)
+	case user.SuspendedUntil.Valid:
+		return fmt.Sprintf(
+			"Your gobid account has been suspended until %s. Your active auctions are frozen until then.\n\nReason: %s",
+			user.SuspendedUntil.Time.UTC().Format(time.RFC1123), user.SuspensionReason,
+		)
+	default:
+		return fmt.Sprintf("Your gobid account has been suspended until further notice. Your active auctions are frozen.\n\nReason: %s", user.SuspensionReason)
+	}
+}
+
+func (s *adminService) ForcePasswordReset(ctx context.Context, actorID, userID uuid.UUID, reason string) error {
+	if _, err := s.GetUser(ctx, userID); err != nil {
+		return err
+	}
+
+	if err := s.passwords.ForcePasswordReset(ctx, userID); err != nil {
+		return fmt.Errorf("service.forcePasswordReset: %v", err)
+	}
+
+	err := s.audit.Record(ctx, audit.Event{
+		ActorID:    actorID,
+		Action:     audit.ActionForcePasswordReset,
+		TargetType: audit.TargetUser,
+		TargetID:   userID,
+		Reason:     reason,
+	})
+	if err != nil {
+		return fmt.Errorf("service.forcePasswordReset: %v", err)
+	}
+
+	return nil
+}
+
+func (s *adminService) TakeDownListing(ctx context.Context, actorID, productID uuid.UUID, reason string) (*pgstore.Product, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("service.takeDownListing: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.q.WithTx(tx)
+
+	product, err := qtx.TakeDownProduct(ctx, pgstore.TakeDownProductParams{
+		ID:             productID,
+		TakedownReason: reason,
+		TakenDownBy:    pgtype.UUID{Bytes: actorID, Valid: true},
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			if _, err := qtx.GetOneProductByID(ctx, productID); err == nil {
+				return nil, ErrAlreadyTakenDown
+			}
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("service.takeDownListing: %v", err)
+	}
+
+	err = s.audit.WithTx(tx).Record(ctx, audit.Event{
+		ActorID:    actorID,
+		Action:     audit.ActionTakeDownListing,
+		TargetType: audit.TargetListing,
+		TargetID:   productID,
+		After:      map[string]any{"taken_down_at": product.TakenDownAt.Time},
+		Reason:     reason,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("service.takeDownListing: %v", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("service.takeDownListing: %v", err)
+	}
+
+	return product, nil
+}
+
+func (s *adminService) ListUserOrders(ctx context.Context, userID uuid.UUID, page Page) ([]*pgstore.Order, error) {
+	if _, err := s.GetUser(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	records, err := s.q.ListOrdersByUserID(ctx, pgstore.ListOrdersByUserIDParams{
+		BuyerID: userID,
+		Limit:   page.Limit,
+		Offset:  page.Offset,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("service.listUserOrders: %v", err)
+	}
+
+	return records, nil
+}
+
+func (s *adminService) ListEvents(ctx context.Context, filter audit.Filter, page Page) ([]*pgstore.AuditEvent, error) {
+	records, err := s.audit.List(ctx, filter, page.Limit, page.Offset)
+	if err != nil {
+		return nil, fmt.Errorf("service.listEvents: %v", err)
+	}
+
+	return records, nil
+}
+
+func (s *adminService) ListTaxRules(ctx context.Context) ([]*pgstore.TaxRule, error) {
+	records, err := s.q.ListTaxRules(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("service.listTaxRules: %v", err)
+	}
+
+	return records, nil
+}
+
+func (s *adminService) SetTaxRule(ctx context.Context, actorID uuid.UUID, region, category, name string, rate float64, inclusive bool) (*pgstore.TaxRule, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("service.setTaxRule: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.q.WithTx(tx)
+
+	var before any
+	existing, err := qtx.FindTaxRule(ctx, pgstore.FindTaxRuleParams{Region: region, Category: category})
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("service.setTaxRule: %v", err)
+	}
+	if err == nil {
+		before = taxRuleState(existing)
+	}
+
+	record, err := qtx.UpsertTaxRule(ctx, pgstore.UpsertTaxRuleParams{
+		Region:    region,
+		Category:  category,
+		Name:      name,
+		Rate:      rate,
+		Inclusive: inclusive,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("service.setTaxRule: %v", err)
+	}
+
+	err = s.audit.WithTx(tx).Record(ctx, audit.Event{
+		ActorID:    actorID,
+		Action:     audit.ActionTaxRuleSet,
+		TargetType: audit.TargetTaxRule,
+		TargetID:   record.ID,
+		Before:     before,
+		After:      taxRuleState(record),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("service.setTaxRule: %v", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("service.setTaxRule: %v", err)
+	}
+
+	return record, nil
+}
+
+func (s *adminService) DeleteTaxRule(ctx context.Context, actorID uuid.UUID, region, category string) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("service.deleteTaxRule: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.q.WithTx(tx)
+
+	record, err := qtx.DeleteTaxRule(ctx, pgstore.DeleteTaxRuleParams{Region: region, Category: category})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrNotFound
+		}
+		return fmt.Errorf("service.deleteTaxRule: %v", err)
+	}
+
+	err = s.audit.WithTx(tx).Record(ctx, audit.Event{
+		ActorID:    actorID,
+		Action:     audit.ActionTaxRuleDelete,
+		TargetType: audit.TargetTaxRule,
+		TargetID:   record.ID,
+		Before:     taxRuleState(record),
+	})
+	if err != nil {
+		return fmt.Errorf("service.deleteTaxRule: %v", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("service.deleteTaxRule: %v", err)
+	}
+
+	return nil
+}
+
+func taxRuleState(rule *pgstore.TaxRule) map[string]any {
+	return map[string]any{
+		"region":    rule.Region,
+		"category":  rule.Category,
+		"name":      rule.Name,
+		"rate":      rule.Rate,
+		"inclusive": rule.Inclusive,
+	}
+}
@@ -0,0 +1,21 @@
+package api
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+)
+
+func LoadPublicURL() (*url.URL, error) {
+	raw := os.Getenv("GOBID_PUBLIC_URL")
+	if raw == "" {
+		raw = "http://localhost:8080"
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("invalid GOBID_PUBLIC_URL: %q", raw)
+	}
+
+	return u, nil
+}
@@ -2,18 +2,32 @@ package middlewares
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"strings"
 
+	"github.com/EduardoMark/gobid/internal/auth/session"
 	"github.com/EduardoMark/gobid/internal/auth/token"
 	"github.com/EduardoMark/gobid/internal/jsonutils"
+	"github.com/EduardoMark/gobid/internal/requestctx"
+	"github.com/EduardoMark/gobid/internal/suspension"
+	"github.com/sirupsen/logrus"
 )
 
 type ctxKey string
 
-const UserIDKey ctxKey = "user_id"
+const UserIDKey = requestctx.UserIDKey
+const ClaimsKey ctxKey = "claims"
 
-func AuthToken(jwtService token.JwtService) func(http.Handler) http.Handler {
+const APIKeyHeader = "X-API-Key"
+
+var ErrInvalidAPIKey = errors.New("api key is invalid, expired or revoked")
+
+type APIKeyAuthenticator interface {
+	Authenticate(ctx context.Context, key string) (*token.Claims, error)
+}
+
+func AuthToken(jwtService token.JwtService, sessions session.Checker) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			const BearerSchema = "Bearer "
@@ -29,14 +43,149 @@ func AuthToken(jwtService token.JwtService) func(http.Handler) http.Handler {
 			tokenStr := strings.TrimPrefix(header, BearerSchema)
 			claims, err := jwtService.ValidateToken(tokenStr)
 			if err != nil {
+				message := "invalid token"
+				if errors.Is(err, token.ErrExpired) {
+					message = "token expired"
+				}
+
+				jsonutils.EncodeJson(w, r, http.StatusUnauthorized, map[string]any{
+					"error": message,
+				})
+				return
+			}
+
+			revoked, err := sessions.IsRevoked(r.Context(), claims)
+			if err != nil {
+				jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+					"error": "unexpected internal server error",
+				})
+				return
+			}
+
+			if revoked {
 				jsonutils.EncodeJson(w, r, http.StatusUnauthorized, map[string]any{
 					"error": "invalid token",
 				})
 				return
 			}
 
+			if !checkAccount(w, r, sessions, claims, "invalid token") {
+				return
+			}
+
+			if err := sessions.Touch(r.Context(), claims); err != nil {
+				logrus.WithField("err", err.Error()).Error("AuthToken")
+			}
+
 			ctx := context.WithValue(r.Context(), UserIDKey, claims.UserID)
+			ctx = context.WithValue(ctx, ClaimsKey, claims)
 			next.ServeHTTP(w, r.WithContext(ctx))
 		})
 	}
 }
+
+func AuthAPIKey(sessions session.Checker, keys APIKeyAuthenticator) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(APIKeyHeader)
+			if key == "" {
+				jsonutils.EncodeJson(w, r, http.StatusUnauthorized, map[string]any{
+					"error": "unauthorized",
+				})
+				return
+			}
+
+			claims, err := keys.Authenticate(r.Context(), key)
+			if err != nil {
+				if errors.Is(err, ErrInvalidAPIKey) {
+					jsonutils.EncodeJson(w, r, http.StatusUnauthorized, map[string]any{
+						"error": "invalid api key",
+					})
+					return
+				}
+
+				logrus.WithField("err", err.Error()).Error("AuthAPIKey")
+
+				jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+					"error": "unexpected internal server error",
+				})
+				return
+			}
+
+			if !checkAccount(w, r, sessions, claims, "invalid api key") {
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), UserIDKey, claims.UserID)
+			ctx = context.WithValue(ctx, ClaimsKey, claims)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+func AuthTokenOrAPIKey(jwtService token.JwtService, sessions session.Checker, keys APIKeyAuthenticator) func(http.Handler) http.Handler {
+	bearer := AuthToken(jwtService, sessions)
+	apiKey := AuthAPIKey(sessions, keys)
+
+	return func(next http.Handler) http.Handler {
+		withToken := bearer(next)
+		withKey := apiKey(next)
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get(APIKeyHeader) != "" {
+				withKey.ServeHTTP(w, r)
+				return
+			}
+
+			withToken.ServeHTTP(w, r)
+		})
+	}
+}
+
+func checkAccount(w http.ResponseWriter, r *http.Request, sessions session.Checker, claims *token.Claims, invalid string) bool {
+	err := sessions.CheckAccount(r.Context(), claims)
+	if err == nil {
+		return true
+	}
+
+	if WriteSuspended(w, r, err) {
+		return false
+	}
+
+	if errors.Is(err, session.ErrUnknownSubject) {
+		jsonutils.EncodeJson(w, r, http.StatusUnauthorized, map[string]any{
+			"error": invalid,
+		})
+		return false
+	}
+
+	logrus.WithField("err", err.Error()).Error("checkAccount")
+
+	jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+		"error": "unexpected internal server error",
+	})
+	return false
+}
+
+func WriteSuspended(w http.ResponseWriter, r *http.Request, err error) bool {
+	var suspended *suspension.Error
+	if !errors.As(err, &suspended) {
+		return false
+	}
+
+	body := map[string]any{
+		"error":  "account suspended",
+		"reason": suspended.Reason,
+	}
+
+	if suspended.Banned {
+		body["error"] = "account banned"
+	}
+
+	if suspended.Until != nil {
+		body["suspended_until"] = suspended.Until
+	}
+
+	jsonutils.EncodeJson(w, r, http.StatusForbidden, body)
+	return true
+}
@@ -0,0 +1,53 @@
+package middlewares
+
+import (
+	"log"
+	"net/http"
+	"os"
+
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+var Logger = middleware.RequestLogger(redactedFormatter{
+	LogFormatter: &middleware.DefaultLogFormatter{
+		Logger:  log.New(os.Stdout, "", log.LstdFlags),
+		NoColor: !isTerminal(os.Stdout),
+	},
+})
+
+type redactedFormatter struct {
+	middleware.LogFormatter
+}
+
+func (f redactedFormatter) NewLogEntry(r *http.Request) middleware.LogEntry {
+	return f.LogFormatter.NewLogEntry(redactQuery(r))
+}
+
+func redactQuery(r *http.Request) *http.Request {
+	if r.URL.RawQuery == "" {
+		return r
+	}
+
+	query := r.URL.Query()
+	for key := range query {
+		query[key] = []string{"REDACTED"}
+	}
+
+	u := *r.URL
+	u.RawQuery = query.Encode()
+
+	redacted := *r
+	redacted.URL = &u
+	redacted.RequestURI = u.RequestURI()
+
+	return &redacted
+}
+
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+
+	return info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,44 @@
+package middlewares
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRedactQuery(t *testing.T) {
+	tests := []struct {
+		name   string
+		target string
+		want   string
+	}{
+		{
+			name:   "download token is redacted",
+			target: "/api/v1/exports/42/download?token=secret",
+			want:   "/api/v1/exports/42/download?token=REDACTED",
+		},
+		{
+			name:   "every query value is redacted",
+			target: "/api/v1/auth/oidc/fake/callback?state=abc&code=xyz",
+			want:   "/api/v1/auth/oidc/fake/callback?code=REDACTED&state=REDACTED",
+		},
+		{
+			name:   "path without query is untouched",
+			target: "/api/v1/products/",
+			want:   "/api/v1/products/",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", tt.target, nil)
+
+			got := redactQuery(r)
+			if got.RequestURI != tt.want || got.URL.RequestURI() != tt.want {
+				t.Fatalf("redactQuery() = %q, want %q", got.RequestURI, tt.want)
+			}
+			if r.RequestURI != tt.target || r.URL.RequestURI() != tt.target {
+				t.Fatalf("redactQuery() changed the original request to %q", r.RequestURI)
+			}
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package middlewares
+
+import (
+	"net/http"
+
+	"github.com/EduardoMark/gobid/internal/auth/token"
+	"github.com/EduardoMark/gobid/internal/jsonutils"
+)
+
+func RequirePermission(permissions ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			claims, ok := r.Context().Value(ClaimsKey).(*token.Claims)
+			if !ok {
+				jsonutils.EncodeJson(w, r, http.StatusUnauthorized, map[string]any{
+					"error": "unauthorized",
+				})
+				return
+			}
+
+			for _, permission := range permissions {
+				if !claims.HasPermission(permission) {
+					jsonutils.EncodeJson(w, r, http.StatusForbidden, map[string]any{
+						"error": "forbidden",
+					})
+					return
+				}
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func HasPermission(r *http.Request, permission string) bool {
+	claims, ok := r.Context().Value(ClaimsKey).(*token.Claims)
+	return ok && claims.HasPermission(permission)
+}
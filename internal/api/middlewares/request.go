@@ -0,0 +1,23 @@
+package middlewares
+
+import (
+	"context"
+	"net"
+	"net/http"
+
+	"github.com/EduardoMark/gobid/internal/requestctx"
+)
+
+const ClientIPKey = requestctx.ClientIPKey
+
+func ClientIP(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ip, _, err := net.SplitHostPort(r.RemoteAddr)
+		if err != nil {
+			ip = r.RemoteAddr
+		}
+
+		ctx := context.WithValue(r.Context(), ClientIPKey, ip)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
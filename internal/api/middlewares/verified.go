@@ -0,0 +1,52 @@
+package middlewares
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/EduardoMark/gobid/internal/jsonutils"
+	"github.com/google/uuid"
+)
+
+type EmailVerificationChecker interface {
+	IsEmailVerified(ctx context.Context, id uuid.UUID) (bool, error)
+}
+
+func RequireVerifiedEmail(checker EmailVerificationChecker) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id, ok := r.Context().Value(UserIDKey).(string)
+			if !ok {
+				jsonutils.EncodeJson(w, r, http.StatusUnauthorized, map[string]any{
+					"error": "unauthorized",
+				})
+				return
+			}
+
+			parsedID, err := uuid.Parse(id)
+			if err != nil {
+				jsonutils.EncodeJson(w, r, http.StatusUnauthorized, map[string]any{
+					"error": "unauthorized",
+				})
+				return
+			}
+
+			verified, err := checker.IsEmailVerified(r.Context(), parsedID)
+			if err != nil {
+				jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+					"error": "unexpected internal server error",
+				})
+				return
+			}
+
+			if !verified {
+				jsonutils.EncodeJson(w, r, http.StatusForbidden, map[string]any{
+					"error": "email address not verified",
+				})
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
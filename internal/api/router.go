@@ -1,9 +1,30 @@
 package api
 
 import (
+	"net/http"
+	"net/url"
+
+	"github.com/EduardoMark/gobid/internal/addresses"
+	"github.com/EduardoMark/gobid/internal/admin"
+	"github.com/EduardoMark/gobid/internal/api/middlewares"
+	"github.com/EduardoMark/gobid/internal/apikeys"
+	"github.com/EduardoMark/gobid/internal/audit"
 	"github.com/EduardoMark/gobid/internal/auth"
+	"github.com/EduardoMark/gobid/internal/auth/oidc"
+	"github.com/EduardoMark/gobid/internal/auth/session"
 	"github.com/EduardoMark/gobid/internal/auth/token"
+	"github.com/EduardoMark/gobid/internal/disputes"
+	"github.com/EduardoMark/gobid/internal/exports"
+	"github.com/EduardoMark/gobid/internal/fees"
+	"github.com/EduardoMark/gobid/internal/jsonutils"
+	"github.com/EduardoMark/gobid/internal/mailer"
+	"github.com/EduardoMark/gobid/internal/media"
+	"github.com/EduardoMark/gobid/internal/orders"
+	"github.com/EduardoMark/gobid/internal/payments"
+	"github.com/EduardoMark/gobid/internal/policy"
 	"github.com/EduardoMark/gobid/internal/products"
+	"github.com/EduardoMark/gobid/internal/rbac"
+	"github.com/EduardoMark/gobid/internal/storage"
 	"github.com/EduardoMark/gobid/internal/users"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
@@ -12,32 +33,81 @@ import (
 
 type Config struct {
 	DBPool *pgxpool.Pool
+	Fees   fees.Schedule
+	Jwt    token.Config
+	Mailer mailer.Mailer
+	OIDC   oidc.Config
+	Blobs  storage.BlobStore
+	URL    *url.URL
 }
 
 func BindRoutes(cfg Config) *chi.Mux {
 	r := chi.NewMux()
+	jwtService := token.NewJwtService(cfg.Jwt)
+
+	r.Get("/.well-known/jwks.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "public, max-age=300")
+		jsonutils.EncodeJson(w, r, http.StatusOK, jwtService.JWKS())
+	})
 
 	r.Route("/api/v1", func(r chi.Router) {
-		r.Use(middleware.Logger)
+		r.Use(middleware.RequestID)
+		r.Use(middlewares.ClientIP)
+		r.Use(middlewares.Logger)
 
-		setupAuthRoutes(r, cfg.DBPool)
+		setupAuthRoutes(r, cfg, jwtService)
 	})
 
 	return r
 }
 
-func setupAuthRoutes(r chi.Router, pool *pgxpool.Pool) {
-	jwtService := token.NewJwtService()
+func setupAuthRoutes(r chi.Router, cfg Config, jwtService token.JwtService) {
+	pool := cfg.DBPool
+	sessions := session.NewChecker(pool)
+
+	auditSvc := audit.NewAuditService(pool)
+
+	userSvc := users.NewUserService(pool, cfg.Blobs, auditSvc)
+
+	mediaHandler := media.NewMediaHandler(cfg.Blobs)
+	mediaHandler.RegisterMediaRoutes(r)
+
+	rbacSvc := rbac.NewRBACService(pool, auditSvc)
+	rbacHandler := rbac.NewRBACHandler(rbacSvc, jwtService, sessions)
+	rbacHandler.RegisterRBACRoutes(r)
 
-	authSvc := auth.NewAuthService(pool)
-	authHandler := auth.NewAuthHandler(authSvc, jwtService)
+	authSvc := auth.NewAuthService(pool, cfg.Mailer, oidc.NewClient(cfg.OIDC), auditSvc)
+	authHandler := auth.NewAuthHandler(authSvc, jwtService, sessions, rbacSvc)
 	authHandler.RegisterAuthRoutes(r)
 
-	userSvc := users.NewUserService(pool)
-	userHandler := users.NewUserHandler(userSvc, jwtService)
+	adminSvc := admin.NewAdminService(pool, authSvc, cfg.Mailer, auditSvc)
+	adminHandler := admin.NewAdminHandler(adminSvc, jwtService, sessions)
+	adminHandler.RegisterAdminRoutes(r)
+
+	userHandler := users.NewUserHandler(userSvc, jwtService, sessions, policy.NewOwnershipPolicy())
 	userHandler.RegisterUserRoutes(r)
 
-	productSvc := products.NewProductService(pool)
-	productHandler := products.NewProductHandler(productSvc, jwtService)
+	apiKeySvc := apikeys.NewAPIKeyService(pool, auditSvc)
+	apiKeyHandler := apikeys.NewAPIKeyHandler(apiKeySvc, jwtService, sessions)
+	apiKeyHandler.RegisterAPIKeyRoutes(r)
+
+	exportSvc := exports.NewExportService(pool, cfg.Mailer, cfg.URL)
+	exportHandler := exports.NewExportHandler(exportSvc, jwtService, sessions)
+	exportHandler.RegisterExportRoutes(r)
+
+	addressSvc := addresses.NewAddressService(pool)
+	addressHandler := addresses.NewAddressHandler(addressSvc, jwtService, sessions)
+	addressHandler.RegisterAddressRoutes(r)
+
+	productSvc := products.NewProductService(pool, cfg.Blobs, auditSvc)
+	productHandler := products.NewProductHandler(productSvc, jwtService, sessions, apiKeySvc, userSvc, policy.NewOwnershipPolicy())
 	productHandler.RegisterProductsRoutes(r)
+
+	orderSvc := orders.NewOrderService(pool, cfg.Fees, auditSvc)
+	orderHandler := orders.NewOrderHandler(orderSvc, jwtService, sessions, userSvc)
+	orderHandler.RegisterOrderRoutes(r)
+
+	disputeSvc := disputes.NewDisputeService(pool, payments.NewLogProvider(), auditSvc)
+	disputeHandler := disputes.NewDisputeHandler(disputeSvc, jwtService, sessions)
+	disputeHandler.RegisterDisputeRoutes(r)
 }
@@ -0,0 +1,36 @@
+package apikeys
+
+import (
+	"context"
+	"time"
+
+	"github.com/EduardoMark/gobid/internal/validator"
+	"github.com/google/uuid"
+)
+
+type CreateAPIKeyReq struct {
+	Name      string     `json:"name"`
+	Scopes    []string   `json:"scopes"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+func (r *CreateAPIKeyReq) Valid(ctx context.Context) validator.Evaluator {
+	var eval validator.Evaluator
+
+	eval.CheckField(validator.NotBlank(r.Name), "name", "this field cannot be blank")
+	eval.CheckField(validator.MaxChars(r.Name, 100), "name", "this field must have at most 100 characters")
+	eval.CheckField(len(r.Scopes) > 0, "scopes", "at least one scope is required")
+	eval.CheckField(r.ExpiresAt == nil || r.ExpiresAt.After(time.Now()), "expires_at", "this field must be in the future")
+
+	return eval
+}
+
+type APIKeyResponse struct {
+	ID         uuid.UUID  `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
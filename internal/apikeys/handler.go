@@ -0,0 +1,187 @@
+package apikeys
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/EduardoMark/gobid/internal/api/middlewares"
+	"github.com/EduardoMark/gobid/internal/auth/session"
+	"github.com/EduardoMark/gobid/internal/auth/token"
+	"github.com/EduardoMark/gobid/internal/jsonutils"
+	"github.com/EduardoMark/gobid/internal/store/pgstore"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+type APIKeyHandler struct {
+	svc        Service
+	jwtService token.JwtService
+	sessions   session.Checker
+}
+
+func NewAPIKeyHandler(svc Service, jwtService token.JwtService, sessions session.Checker) APIKeyHandler {
+	return APIKeyHandler{
+		svc:        svc,
+		jwtService: jwtService,
+		sessions:   sessions,
+	}
+}
+
+func (m *APIKeyHandler) RegisterAPIKeyRoutes(r chi.Router) {
+	r.Group(func(r chi.Router) {
+		r.Use(middlewares.AuthToken(m.jwtService, m.sessions))
+
+		r.Post("/users/me/api-keys", m.Create)
+		r.Get("/users/me/api-keys", m.List)
+		r.Delete("/users/me/api-keys/{id}", m.Revoke)
+	})
+}
+
+func (m *APIKeyHandler) Create(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, ok := currentUserID(w, r)
+	if !ok {
+		return
+	}
+
+	data, problems, err := jsonutils.DecodeValidJson[*CreateAPIKeyReq](r)
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+
+	record, key, err := m.svc.Create(ctx, userID, data.Name, data.Scopes, data.ExpiresAt)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrInvalidScope):
+			jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]any{
+				"scopes": err.Error(),
+			})
+		case errors.Is(err, ErrTooManyKeys):
+			jsonutils.EncodeJson(w, r, http.StatusConflict, map[string]any{
+				"error": "api key limit reached",
+			})
+		default:
+			logrus.WithField("err", err.Error()).Error("Handler.Create")
+
+			jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+				"error": "unexpected internal server error",
+			})
+		}
+		return
+	}
+
+	jsonutils.EncodeJson(w, r, http.StatusCreated, map[string]any{
+		"api_key": toAPIKeyResponse(record),
+		"key":     key,
+	})
+}
+
+func (m *APIKeyHandler) List(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, ok := currentUserID(w, r)
+	if !ok {
+		return
+	}
+
+	records, err := m.svc.List(ctx, userID)
+	if err != nil {
+		logrus.WithField("err", err.Error()).Error("Handler.List")
+
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "unexpected internal server error",
+		})
+		return
+	}
+
+	res := make([]APIKeyResponse, len(records))
+	for i, record := range records {
+		res[i] = toAPIKeyResponse(record)
+	}
+
+	jsonutils.EncodeJson(w, r, http.StatusOK, map[string]any{
+		"api_keys": res,
+	})
+}
+
+func (m *APIKeyHandler) Revoke(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, ok := currentUserID(w, r)
+	if !ok {
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "invalid uuid type",
+		})
+		return
+	}
+
+	if err := m.svc.Revoke(ctx, userID, id); err != nil {
+		if errors.Is(err, ErrNotFound) {
+			jsonutils.EncodeJson(w, r, http.StatusNotFound, map[string]any{
+				"error": "api key not found",
+			})
+			return
+		}
+
+		logrus.WithField("err", err.Error()).Error("Handler.Revoke")
+
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "unexpected internal server error",
+		})
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func currentUserID(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	id, ok := r.Context().Value(middlewares.UserIDKey).(string)
+	if !ok {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "user ID not found in context",
+		})
+		return uuid.UUID{}, false
+	}
+
+	userID, err := uuid.Parse(id)
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "invalid user ID format",
+		})
+		return uuid.UUID{}, false
+	}
+
+	return userID, true
+}
+
+func toAPIKeyResponse(record *pgstore.ApiKey) APIKeyResponse {
+	res := APIKeyResponse{
+		ID:        record.ID,
+		Name:      record.Name,
+		Prefix:    record.Prefix,
+		Scopes:    record.Scopes,
+		CreatedAt: record.CreatedAt,
+	}
+
+	if res.Scopes == nil {
+		res.Scopes = []string{}
+	}
+
+	if record.ExpiresAt.Valid {
+		res.ExpiresAt = &record.ExpiresAt.Time
+	}
+
+	if record.LastUsedAt.Valid {
+		res.LastUsedAt = &record.LastUsedAt.Time
+	}
+
+	return res
+}
@@ -0,0 +1,222 @@
+package apikeys
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/EduardoMark/gobid/internal/api/middlewares"
+	"github.com/EduardoMark/gobid/internal/audit"
+	"github.com/EduardoMark/gobid/internal/auth/token"
+	"github.com/EduardoMark/gobid/internal/store/pgstore"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/sirupsen/logrus"
+)
+
+type Service interface {
+	Create(ctx context.Context, userID uuid.UUID, name string, scopes []string, expiresAt *time.Time) (*pgstore.ApiKey, string, error)
+	List(ctx context.Context, userID uuid.UUID) ([]*pgstore.ApiKey, error)
+	Revoke(ctx context.Context, userID, id uuid.UUID) error
+	Authenticate(ctx context.Context, key string) (*token.Claims, error)
+}
+
+type apiKeyService struct {
+	pool  *pgxpool.Pool
+	q     *pgstore.Queries
+	audit audit.Recorder
+}
+
+var ErrNotFound = errors.New("not found")
+var ErrInvalidScope = errors.New("scope not held by user")
+var ErrTooManyKeys = errors.New("api key limit reached")
+
+const keyPrefix = "gbk_"
+const displayPrefixLength = 12
+const maxActiveKeys = 20
+
+func NewAPIKeyService(pool *pgxpool.Pool, recorder audit.Recorder) Service {
+	return &apiKeyService{
+		pool:  pool,
+		q:     pgstore.New(pool),
+		audit: recorder,
+	}
+}
+
+func (s *apiKeyService) Create(ctx context.Context, userID uuid.UUID, name string, scopes []string, expiresAt *time.Time) (*pgstore.ApiKey, string, error) {
+	permissions, err := s.q.ListUserPermissions(ctx, userID)
+	if err != nil {
+		return nil, "", fmt.Errorf("service.create: %v", err)
+	}
+
+	for _, scope := range scopes {
+		if !slices.Contains(permissions, scope) {
+			return nil, "", fmt.Errorf("%w: %s", ErrInvalidScope, scope)
+		}
+	}
+
+	active, err := s.q.CountActiveAPIKeys(ctx, userID)
+	if err != nil {
+		return nil, "", fmt.Errorf("service.create: %v", err)
+	}
+
+	if active >= maxActiveKeys {
+		return nil, "", ErrTooManyKeys
+	}
+
+	key, err := newKey()
+	if err != nil {
+		return nil, "", fmt.Errorf("service.create: %v", err)
+	}
+
+	params := pgstore.CreateAPIKeyParams{
+		UserID:  userID,
+		Name:    name,
+		Prefix:  key[:displayPrefixLength],
+		KeyHash: hashKey(key),
+		Scopes:  slices.Compact(slices.Sorted(slices.Values(scopes))),
+	}
+	if expiresAt != nil {
+		params.ExpiresAt = pgtype.Timestamptz{Time: *expiresAt, Valid: true}
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, "", fmt.Errorf("service.create: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	record, err := s.q.WithTx(tx).CreateAPIKey(ctx, params)
+	if err != nil {
+		return nil, "", fmt.Errorf("service.create: %v", err)
+	}
+
+	err = s.audit.WithTx(tx).Record(ctx, audit.Event{
+		ActorID:    userID,
+		Action:     audit.ActionAPIKeyCreate,
+		TargetType: audit.TargetUser,
+		TargetID:   userID,
+		After: map[string]any{
+			"api_key_id": record.ID,
+			"name":       record.Name,
+			"scopes":     record.Scopes,
+			"expires_at": expiresAt,
+		},
+	})
+	if err != nil {
+		return nil, "", fmt.Errorf("service.create: %v", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, "", fmt.Errorf("service.create: %v", err)
+	}
+
+	return record, key, nil
+}
+
+func (s *apiKeyService) List(ctx context.Context, userID uuid.UUID) ([]*pgstore.ApiKey, error) {
+	records, err := s.q.ListAPIKeysByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("service.list: %v", err)
+	}
+
+	return records, nil
+}
+
+func (s *apiKeyService) Revoke(ctx context.Context, userID, id uuid.UUID) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("service.revoke: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	record, err := s.q.WithTx(tx).RevokeAPIKey(ctx, pgstore.RevokeAPIKeyParams{
+		ID:     id,
+		UserID: userID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrNotFound
+		}
+		return fmt.Errorf("service.revoke: %v", err)
+	}
+
+	err = s.audit.WithTx(tx).Record(ctx, audit.Event{
+		ActorID:    userID,
+		Action:     audit.ActionAPIKeyRevoke,
+		TargetType: audit.TargetUser,
+		TargetID:   userID,
+		Before:     map[string]any{"api_key_id": record.ID, "name": record.Name},
+	})
+	if err != nil {
+		return fmt.Errorf("service.revoke: %v", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("service.revoke: %v", err)
+	}
+
+	return nil
+}
+
+func (s *apiKeyService) Authenticate(ctx context.Context, key string) (*token.Claims, error) {
+	if !strings.HasPrefix(key, keyPrefix) {
+		return nil, middlewares.ErrInvalidAPIKey
+	}
+
+	record, err := s.q.GetAPIKeyByHash(ctx, hashKey(key))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, middlewares.ErrInvalidAPIKey
+		}
+		return nil, fmt.Errorf("service.authenticate: %v", err)
+	}
+
+	if record.ExpiresAt.Valid && !record.ExpiresAt.Time.After(time.Now()) {
+		return nil, middlewares.ErrInvalidAPIKey
+	}
+
+	held, err := s.q.ListUserPermissions(ctx, record.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("service.authenticate: %v", err)
+	}
+
+	permissions := make([]string, 0, len(record.Scopes))
+	for _, scope := range record.Scopes {
+		if slices.Contains(held, scope) {
+			permissions = append(permissions, scope)
+		}
+	}
+
+	if err := s.q.TouchAPIKey(ctx, record.ID); err != nil {
+		logrus.WithField("err", err.Error()).Error("APIKeyService.Authenticate")
+	}
+
+	return &token.Claims{
+		UserID:      record.UserID.String(),
+		Permissions: permissions,
+	}, nil
+}
+
+func newKey() (string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+
+	return keyPrefix + base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+func hashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,96 @@
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+const (
+	ActionLogin               = "auth.login"
+	ActionLoginFailed         = "auth.login_failed"
+	ActionOIDCLogin           = "auth.oidc_login"
+	ActionPasswordChange      = "auth.password_change"
+	ActionPasswordReset       = "auth.password_reset"
+	ActionTwoFactorEnable     = "auth.two_factor_enable"
+	ActionTwoFactorDisable    = "auth.two_factor_disable"
+	ActionProfileUpdate       = "user.update"
+	ActionPrivacyUpdate       = "user.privacy_update"
+	ActionAvatarUpdate        = "user.avatar_update"
+	ActionAvatarRemove        = "user.avatar_remove"
+	ActionDeletionRequest     = "user.deletion_request"
+	ActionDeletionCancel      = "user.deletion_cancel"
+	ActionAnonymise           = "user.anonymise"
+	ActionSuspendUser         = "user.suspend"
+	ActionBanUser             = "user.ban"
+	ActionUnsuspendUser       = "user.unsuspend"
+	ActionUnlockUser          = "user.unlock"
+	ActionForcePasswordReset  = "user.force_password_reset"
+	ActionRoleGrant           = "user.role_grant"
+	ActionRoleRevoke          = "user.role_revoke"
+	ActionAPIKeyCreate        = "user.api_key_create"
+	ActionAPIKeyRevoke        = "user.api_key_revoke"
+	ActionListingCreate       = "listing.create"
+	ActionListingImageAdd     = "listing.image_add"
+	ActionListingImageRemove  = "listing.image_remove"
+	ActionListingImageReorder = "listing.image_reorder"
+	ActionTakeDownListing     = "listing.take_down"
+	ActionOrderCreate         = "order.create"
+	ActionOrderPay            = "order.pay"
+	ActionDisputeResolve      = "dispute.resolve"
+	ActionTaxRuleSet          = "tax_rule.set"
+	ActionTaxRuleDelete       = "tax_rule.delete"
+)
+
+const (
+	TargetUser    = "user"
+	TargetListing = "listing"
+	TargetOrder   = "order"
+	TargetDispute = "dispute"
+	TargetTaxRule = "tax_rule"
+)
+
+func diff(before, after any) ([]byte, []byte, error) {
+	beforeJSON, err := marshal(before)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	afterJSON, err := marshal(after)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if beforeJSON == nil || afterJSON == nil {
+		return beforeJSON, afterJSON, nil
+	}
+
+	var beforeFields, afterFields map[string]json.RawMessage
+	if json.Unmarshal(beforeJSON, &beforeFields) != nil || json.Unmarshal(afterJSON, &afterFields) != nil {
+		return beforeJSON, afterJSON, nil
+	}
+
+	for key, value := range beforeFields {
+		if other, ok := afterFields[key]; ok && bytes.Equal(value, other) {
+			delete(beforeFields, key)
+			delete(afterFields, key)
+		}
+	}
+
+	if beforeJSON, err = json.Marshal(beforeFields); err != nil {
+		return nil, nil, err
+	}
+
+	if afterJSON, err = json.Marshal(afterFields); err != nil {
+		return nil, nil, err
+	}
+
+	return beforeJSON, afterJSON, nil
+}
+
+func marshal(value any) ([]byte, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	return json.Marshal(value)
+}
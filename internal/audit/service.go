@@ -0,0 +1,123 @@
+package audit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/EduardoMark/gobid/internal/requestctx"
+	"github.com/EduardoMark/gobid/internal/store/pgstore"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type Event struct {
+	ActorID    uuid.UUID
+	Action     string
+	TargetType string
+	TargetID   uuid.UUID
+	Before     any
+	After      any
+	Reason     string
+}
+
+type Filter struct {
+	ActorID    uuid.UUID
+	TargetType string
+	TargetID   uuid.UUID
+	Action     string
+	IP         string
+	RequestID  string
+	Since      *time.Time
+	Until      *time.Time
+}
+
+type Recorder interface {
+	Record(ctx context.Context, event Event) error
+	WithTx(tx pgx.Tx) Recorder
+}
+
+type Service interface {
+	Recorder
+	List(ctx context.Context, filter Filter, limit, offset int32) ([]*pgstore.AuditEvent, error)
+}
+
+type auditService struct {
+	q *pgstore.Queries
+}
+
+func NewAuditService(pool *pgxpool.Pool) Service {
+	return &auditService{
+		q: pgstore.New(pool),
+	}
+}
+
+func (s *auditService) WithTx(tx pgx.Tx) Recorder {
+	return &auditService{
+		q: s.q.WithTx(tx),
+	}
+}
+
+func (s *auditService) Record(ctx context.Context, event Event) error {
+	before, after, err := diff(event.Before, event.After)
+	if err != nil {
+		return fmt.Errorf("audit.record: %v", err)
+	}
+
+	actorID := event.ActorID
+	if actorID == uuid.Nil {
+		actorID, _ = uuid.Parse(requestctx.UserID(ctx))
+	}
+
+	ip := requestctx.ClientIP(ctx)
+
+	err = s.q.CreateAuditEvent(ctx, pgstore.CreateAuditEventParams{
+		ActorID:    nullableUUID(actorID),
+		Action:     event.Action,
+		TargetType: event.TargetType,
+		TargetID:   nullableUUID(event.TargetID),
+		Before:     before,
+		After:      after,
+		Reason:     event.Reason,
+		Ip:         ip,
+		RequestID:  middleware.GetReqID(ctx),
+	})
+	if err != nil {
+		return fmt.Errorf("audit.record: %v", err)
+	}
+
+	return nil
+}
+
+func (s *auditService) List(ctx context.Context, filter Filter, limit, offset int32) ([]*pgstore.AuditEvent, error) {
+	params := pgstore.ListAuditEventsParams{
+		ActorID:    nullableUUID(filter.ActorID),
+		TargetType: filter.TargetType,
+		TargetID:   nullableUUID(filter.TargetID),
+		Action:     filter.Action,
+		Ip:         filter.IP,
+		RequestID:  filter.RequestID,
+		Limit:      limit,
+		Offset:     offset,
+	}
+	if filter.Since != nil {
+		params.CreatedAt = pgtype.Timestamptz{Time: *filter.Since, Valid: true}
+	}
+	if filter.Until != nil {
+		params.CreatedAt_2 = pgtype.Timestamptz{Time: *filter.Until, Valid: true}
+	}
+
+	records, err := s.q.ListAuditEvents(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("audit.list: %v", err)
+	}
+
+	return records, nil
+}
+
+func nullableUUID(id uuid.UUID) pgtype.UUID {
+	return pgtype.UUID{Bytes: id, Valid: id != uuid.Nil}
+}
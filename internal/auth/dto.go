@@ -2,8 +2,10 @@ package auth
 
 import (
 	"context"
+	"time"
 
 	"github.com/EduardoMark/gobid/internal/validator"
+	"github.com/google/uuid"
 )
 
 type SignupReq struct {
@@ -55,3 +57,116 @@ func (r *ChangePasswordReq) Valid(ctx context.Context) validator.Evaluator {
 
 	return eval
 }
+
+type RefreshReq struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+func (r *RefreshReq) Valid(ctx context.Context) validator.Evaluator {
+	var eval validator.Evaluator
+
+	eval.CheckField(validator.NotBlank(r.RefreshToken), "refresh_token", "this field cannot be blank")
+
+	return eval
+}
+
+type LogoutReq struct {
+	RefreshToken string `json:"refresh_token"`
+	All          bool   `json:"all"`
+}
+
+func (r *LogoutReq) Valid(ctx context.Context) validator.Evaluator {
+	var eval validator.Evaluator
+
+	return eval
+}
+
+type VerifyEmailReq struct {
+	Token string `json:"token"`
+}
+
+func (r *VerifyEmailReq) Valid(ctx context.Context) validator.Evaluator {
+	var eval validator.Evaluator
+
+	eval.CheckField(validator.NotBlank(r.Token), "token", "this field cannot be blank")
+
+	return eval
+}
+
+type ForgotPasswordReq struct {
+	Email string `json:"email"`
+}
+
+func (r *ForgotPasswordReq) Valid(ctx context.Context) validator.Evaluator {
+	var eval validator.Evaluator
+
+	eval.CheckField(validator.Matches(r.Email, validator.EmailRX), "email", "this field must be a valid email")
+
+	return eval
+}
+
+type ResetPasswordReq struct {
+	Token       string `json:"token"`
+	NewPassword string `json:"new_password"`
+}
+
+func (r *ResetPasswordReq) Valid(ctx context.Context) validator.Evaluator {
+	var eval validator.Evaluator
+
+	eval.CheckField(validator.NotBlank(r.Token), "token", "this field cannot be blank")
+	eval.CheckField(validator.MinChars(r.NewPassword, 8), "new_password", "this field must have least 8 characters")
+
+	return eval
+}
+
+type LoginTwoFactorReq struct {
+	ChallengeToken string `json:"challenge_token"`
+	Code           string `json:"code"`
+}
+
+func (r *LoginTwoFactorReq) Valid(ctx context.Context) validator.Evaluator {
+	var eval validator.Evaluator
+
+	eval.CheckField(validator.NotBlank(r.ChallengeToken), "challenge_token", "this field cannot be blank")
+	eval.CheckField(validator.NotBlank(r.Code), "code", "this field cannot be blank")
+
+	return eval
+}
+
+type TwoFactorCodeReq struct {
+	Code string `json:"code"`
+}
+
+func (r *TwoFactorCodeReq) Valid(ctx context.Context) validator.Evaluator {
+	var eval validator.Evaluator
+
+	eval.CheckField(validator.NotBlank(r.Code), "code", "this field cannot be blank")
+
+	return eval
+}
+
+type DisableTwoFactorReq struct {
+	Password string `json:"password"`
+}
+
+func (r *DisableTwoFactorReq) Valid(ctx context.Context) validator.Evaluator {
+	var eval validator.Evaluator
+
+	eval.CheckField(validator.MinChars(r.Password, 8), "password", "this field must have least 8 characters")
+
+	return eval
+}
+
+type TwoFactorEnrollmentResponse struct {
+	Secret     string `json:"secret"`
+	OtpauthURI string `json:"otpauth_uri"`
+}
+
+type SessionResponse struct {
+	ID         uuid.UUID `json:"id"`
+	UserAgent  string    `json:"user_agent"`
+	IP         string    `json:"ip"`
+	Current    bool      `json:"current"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+}
@@ -2,11 +2,17 @@ package auth
 
 import (
 	"errors"
+	"math"
 	"net/http"
+	"strconv"
 
 	"github.com/EduardoMark/gobid/internal/api/middlewares"
+	"github.com/EduardoMark/gobid/internal/auth/session"
 	"github.com/EduardoMark/gobid/internal/auth/token"
 	"github.com/EduardoMark/gobid/internal/jsonutils"
+	"github.com/EduardoMark/gobid/internal/lockout"
+	"github.com/EduardoMark/gobid/internal/rbac"
+	"github.com/EduardoMark/gobid/internal/requestctx"
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
@@ -15,12 +21,16 @@ import (
 type AuthHandler struct {
 	svc        Service
 	jwtService token.JwtService
+	sessions   session.Checker
+	roles      rbac.Service
 }
 
-func NewAuthHandler(svc Service, jwtService token.JwtService) AuthHandler {
+func NewAuthHandler(svc Service, jwtService token.JwtService, sessions session.Checker, roles rbac.Service) AuthHandler {
 	return AuthHandler{
 		svc:        svc,
 		jwtService: jwtService,
+		sessions:   sessions,
+		roles:      roles,
 	}
 }
 
@@ -28,11 +38,27 @@ func (m *AuthHandler) RegisterAuthRoutes(r chi.Router) {
 	r.Route("/auth", func(r chi.Router) {
 		r.Post("/signup", m.Signup)
 		r.Post("/login", m.Login)
+		r.Post("/login/2fa", m.LoginTwoFactor)
+		r.Post("/refresh", m.Refresh)
+		r.Post("/verify-email", m.VerifyEmail)
+		r.Post("/forgot-password", m.ForgotPassword)
+		r.Post("/reset-password", m.ResetPassword)
+		r.Get("/oidc/providers", m.ListOIDCProviders)
+		r.Get("/oidc/{provider}/authorize", m.AuthorizeOIDC)
+		r.Get("/oidc/{provider}/callback", m.OIDCCallback)
 
 		r.Group(func(r chi.Router) {
-			r.Use(middlewares.AuthToken(m.jwtService))
+			r.Use(middlewares.AuthToken(m.jwtService, m.sessions))
 
 			r.Post("/change-password", m.ChangePassword)
+			r.Post("/logout", m.Logout)
+			r.Get("/sessions", m.ListSessions)
+			r.Delete("/sessions/{id}", m.RevokeSession)
+			r.Post("/verify-email/resend", m.ResendEmailVerification)
+			r.Post("/2fa/enroll", m.EnrollTwoFactor)
+			r.Post("/2fa/confirm", m.ConfirmTwoFactor)
+			r.Post("/2fa/disable", m.DisableTwoFactor)
+			r.With(middlewares.RequirePermission(rbac.PermLockoutsUnlock)).Post("/lockouts/{id}/unlock", m.UnlockAccount)
 		})
 	})
 }
@@ -70,6 +96,10 @@ func (m *AuthHandler) Signup(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err := m.svc.SendEmailVerification(ctx, id); err != nil {
+		logrus.WithField("err", err.Error()).Error("Handler.Signup")
+	}
+
 	jsonutils.EncodeJson(w, r, http.StatusCreated, map[string]any{
 		"id": id,
 	})
@@ -84,8 +114,12 @@ func (m *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	id, err := m.svc.AuthLogin(ctx, data.Email, data.Password)
+	id, err := m.svc.AuthLogin(ctx, data.Email, data.Password, requestctx.ClientIP(ctx))
 	if err != nil {
+		if writeLocked(w, r, err) {
+			return
+		}
+
 		if errors.Is(err, ErrInvalidCredentials) {
 			jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
 				"error": "invalid credentials",
@@ -93,6 +127,17 @@ func (m *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
+		if middlewares.WriteSuspended(w, r, err) {
+			return
+		}
+
+		if errors.Is(err, ErrPasswordResetRequired) {
+			jsonutils.EncodeJson(w, r, http.StatusForbidden, map[string]any{
+				"error": "password reset required, check your email for a reset token",
+			})
+			return
+		}
+
 		logrus.WithField("error", err.Error())
 
 		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
@@ -101,9 +146,168 @@ func (m *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	token, err := m.jwtService.GenerateToken(id.String())
+	m.completeLogin(w, r, id, "Handler.Login")
+}
+
+func (m *AuthHandler) completeLogin(w http.ResponseWriter, r *http.Request, id uuid.UUID, op string) {
+	enabled, err := m.svc.TwoFactorEnabled(r.Context(), id)
+	if err != nil {
+		logrus.WithField("err", err.Error()).Error(op)
+
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "unexpected internal server error",
+		})
+		return
+	}
+
+	if enabled {
+		challengeToken, err := m.jwtService.GenerateChallengeToken(id.String())
+		if err != nil {
+			logrus.WithField("err", err.Error()).Error(op)
+
+			jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+				"error": "unexpected internal server error",
+			})
+			return
+		}
+
+		jsonutils.EncodeJson(w, r, http.StatusOK, map[string]any{
+			"two_factor_required": true,
+			"challenge_token":     challengeToken,
+		})
+		return
+	}
+
+	m.writeTokens(w, r, id, op)
+}
+
+func (m *AuthHandler) LoginTwoFactor(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	data, problems, err := jsonutils.DecodeValidJson[*LoginTwoFactorReq](r)
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+
+	claims, err := m.jwtService.ValidateChallengeToken(data.ChallengeToken)
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusUnauthorized, map[string]any{
+			"error": "invalid challenge token",
+		})
+		return
+	}
+
+	revoked, err := m.sessions.IsRevoked(ctx, claims)
+	if err != nil {
+		logrus.WithField("err", err.Error()).Error("Handler.LoginTwoFactor")
+
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "unexpected internal server error",
+		})
+		return
+	}
+
+	userID, err := uuid.Parse(claims.UserID)
+	if revoked || err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusUnauthorized, map[string]any{
+			"error": "invalid challenge token",
+		})
+		return
+	}
+
+	if err := m.svc.VerifyTwoFactor(ctx, userID, data.Code); err != nil {
+		if writeLocked(w, r, err) {
+			return
+		}
+
+		if errors.Is(err, ErrInvalidTwoFactorCode) || errors.Is(err, ErrTwoFactorNotEnrolled) {
+			jsonutils.EncodeJson(w, r, http.StatusUnauthorized, map[string]any{
+				"error": "invalid two-factor code",
+			})
+			return
+		}
+
+		logrus.WithField("err", err.Error()).Error("Handler.LoginTwoFactor")
+
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "unexpected internal server error",
+		})
+		return
+	}
+
+	if err := m.sessions.Revoke(ctx, claims); err != nil {
+		logrus.WithField("err", err.Error()).Error("Handler.LoginTwoFactor")
+	}
+
+	m.writeTokens(w, r, userID, "Handler.LoginTwoFactor")
+}
+
+func (m *AuthHandler) writeTokens(w http.ResponseWriter, r *http.Request, id uuid.UUID, op string) {
+	sessionID, refreshToken, err := m.svc.StartSession(r.Context(), id, r.UserAgent(), requestctx.ClientIP(r.Context()))
+	if err != nil {
+		logrus.WithField("err", err.Error()).Error(op)
+
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "unexpected internal server error",
+		})
+		return
+	}
+
+	token, err := m.accessToken(r, id, sessionID)
+	if err != nil {
+		logrus.WithField("err", err.Error()).Error(op)
+
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "unexpected internal server error",
+		})
+		return
+	}
+
+	jsonutils.EncodeJson(w, r, http.StatusOK, map[string]any{
+		"token":         token,
+		"refresh_token": refreshToken,
+	})
+}
+
+func (m *AuthHandler) accessToken(r *http.Request, userID, sessionID uuid.UUID) (string, error) {
+	access, err := m.roles.Access(r.Context(), userID)
+	if err != nil {
+		return "", err
+	}
+
+	return m.jwtService.GenerateToken(userID.String(), sessionID.String(), access)
+}
+
+func (m *AuthHandler) Refresh(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	data, problems, err := jsonutils.DecodeValidJson[*RefreshReq](r)
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+
+	userID, sessionID, refreshToken, err := m.svc.RotateRefreshToken(ctx, data.RefreshToken)
+	if err != nil {
+		if errors.Is(err, ErrInvalidRefreshToken) || errors.Is(err, ErrRefreshTokenReused) {
+			jsonutils.EncodeJson(w, r, http.StatusUnauthorized, map[string]any{
+				"error": "invalid refresh token",
+			})
+			return
+		}
+
+		logrus.WithField("err", err.Error()).Error("Handler.Refresh")
+
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "unexpected internal server error",
+		})
+		return
+	}
+
+	token, err := m.accessToken(r, userID, sessionID)
 	if err != nil {
-		logrus.WithField("err", err.Error())
+		logrus.WithField("err", err.Error()).Error("Handler.Refresh")
 
 		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
 			"error": "unexpected internal server error",
@@ -112,10 +316,72 @@ func (m *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 	}
 
 	jsonutils.EncodeJson(w, r, http.StatusOK, map[string]any{
-		"token": token,
+		"token":         token,
+		"refresh_token": refreshToken,
 	})
 }
 
+func (m *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, ok := ctx.Value(middlewares.UserIDKey).(string)
+	if !ok {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "user ID not found in context",
+		})
+		return
+	}
+
+	parsedID, err := uuid.Parse(id)
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "invalid user ID format",
+		})
+		return
+	}
+
+	data, problems, err := jsonutils.DecodeValidJson[*LogoutReq](r)
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+
+	claims, _ := ctx.Value(middlewares.ClaimsKey).(*token.Claims)
+
+	switch {
+	case data.All:
+		err = m.svc.RevokeAllSessions(ctx, parsedID)
+	case data.RefreshToken != "":
+		err = m.svc.RevokeRefreshToken(ctx, parsedID, data.RefreshToken)
+	case claims != nil && claims.SessionID != "":
+		var sessionID uuid.UUID
+		if sessionID, err = uuid.Parse(claims.SessionID); err == nil {
+			err = m.svc.RevokeSession(ctx, parsedID, sessionID)
+		}
+	}
+	if err != nil && !errors.Is(err, ErrInvalidRefreshToken) && !errors.Is(err, ErrSessionNotFound) {
+		logrus.WithField("err", err.Error()).Error("Handler.Logout")
+
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "unexpected internal server error",
+		})
+		return
+	}
+
+	if claims != nil {
+		if err := m.sessions.Revoke(ctx, claims); err != nil {
+			logrus.WithField("err", err.Error()).Error("Handler.Logout")
+
+			jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+				"error": "unexpected internal server error",
+			})
+			return
+		}
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
 func (m *AuthHandler) ChangePassword(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
@@ -171,3 +437,549 @@ func (m *AuthHandler) ChangePassword(w http.ResponseWriter, r *http.Request) {
 
 	w.WriteHeader(http.StatusNoContent)
 }
+
+func (m *AuthHandler) VerifyEmail(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	data, problems, err := jsonutils.DecodeValidJson[*VerifyEmailReq](r)
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+
+	if err := m.svc.VerifyEmail(ctx, data.Token); err != nil {
+		if errors.Is(err, ErrInvalidVerificationToken) {
+			jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+				"error": "invalid or expired verification token",
+			})
+			return
+		}
+
+		logrus.WithField("err", err.Error()).Error("Handler.VerifyEmail")
+
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "unexpected internal server error",
+		})
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (m *AuthHandler) ResendEmailVerification(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, ok := ctx.Value(middlewares.UserIDKey).(string)
+	if !ok {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "user ID not found in context",
+		})
+		return
+	}
+
+	parsedID, err := uuid.Parse(id)
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "invalid user ID format",
+		})
+		return
+	}
+
+	if err := m.svc.SendEmailVerification(ctx, parsedID); err != nil {
+		if errors.Is(err, ErrAlreadyVerified) {
+			jsonutils.EncodeJson(w, r, http.StatusConflict, map[string]any{
+				"error": "email already verified",
+			})
+			return
+		}
+
+		if errors.Is(err, ErrNotFound) {
+			jsonutils.EncodeJson(w, r, http.StatusNotFound, map[string]any{
+				"error": "user not found",
+			})
+			return
+		}
+
+		logrus.WithField("err", err.Error()).Error("Handler.ResendEmailVerification")
+
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "unexpected internal server error",
+		})
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (m *AuthHandler) ForgotPassword(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	data, problems, err := jsonutils.DecodeValidJson[*ForgotPasswordReq](r)
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+
+	if err := m.svc.ForgotPassword(ctx, data.Email, requestctx.ClientIP(ctx)); err != nil {
+		if writeLocked(w, r, err) {
+			return
+		}
+
+		if errors.Is(err, ErrPasswordResetBusy) {
+			w.Header().Set("Retry-After", "30")
+			jsonutils.EncodeJson(w, r, http.StatusServiceUnavailable, map[string]any{
+				"error": "too many password reset requests, try again shortly",
+			})
+			return
+		}
+
+		logrus.WithField("err", err.Error()).Error("Handler.ForgotPassword")
+
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "unexpected internal server error",
+		})
+		return
+	}
+
+	jsonutils.EncodeJson(w, r, http.StatusAccepted, map[string]any{
+		"message": "if an account exists for this email, a password reset link has been sent",
+	})
+}
+
+func (m *AuthHandler) ResetPassword(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	data, problems, err := jsonutils.DecodeValidJson[*ResetPasswordReq](r)
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+
+	if err := m.svc.ResetPassword(ctx, data.Token, data.NewPassword); err != nil {
+		if errors.Is(err, ErrInvalidResetToken) {
+			jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+				"error": "invalid or expired reset token",
+			})
+			return
+		}
+
+		logrus.WithField("err", err.Error()).Error("Handler.ResetPassword")
+
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "unexpected internal server error",
+		})
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (m *AuthHandler) EnrollTwoFactor(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, ok := ctx.Value(middlewares.UserIDKey).(string)
+	if !ok {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "user ID not found in context",
+		})
+		return
+	}
+
+	parsedID, err := uuid.Parse(id)
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "invalid user ID format",
+		})
+		return
+	}
+
+	secret, uri, err := m.svc.EnrollTwoFactor(ctx, parsedID)
+	if err != nil {
+		if errors.Is(err, ErrTwoFactorEnabled) {
+			jsonutils.EncodeJson(w, r, http.StatusConflict, map[string]any{
+				"error": "two-factor authentication already enabled",
+			})
+			return
+		}
+
+		if errors.Is(err, ErrNotFound) {
+			jsonutils.EncodeJson(w, r, http.StatusNotFound, map[string]any{
+				"error": "user not found",
+			})
+			return
+		}
+
+		logrus.WithField("err", err.Error()).Error("Handler.EnrollTwoFactor")
+
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "unexpected internal server error",
+		})
+		return
+	}
+
+	jsonutils.EncodeJson(w, r, http.StatusOK, TwoFactorEnrollmentResponse{
+		Secret:     secret,
+		OtpauthURI: uri,
+	})
+}
+
+func (m *AuthHandler) ConfirmTwoFactor(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, ok := ctx.Value(middlewares.UserIDKey).(string)
+	if !ok {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "user ID not found in context",
+		})
+		return
+	}
+
+	parsedID, err := uuid.Parse(id)
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "invalid user ID format",
+		})
+		return
+	}
+
+	data, problems, err := jsonutils.DecodeValidJson[*TwoFactorCodeReq](r)
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+
+	codes, err := m.svc.ConfirmTwoFactor(ctx, parsedID, data.Code)
+	if err != nil {
+		if errors.Is(err, ErrTwoFactorNotEnrolled) {
+			jsonutils.EncodeJson(w, r, http.StatusNotFound, map[string]any{
+				"error": "two-factor enrollment not started",
+			})
+			return
+		}
+
+		if errors.Is(err, ErrTwoFactorEnabled) {
+			jsonutils.EncodeJson(w, r, http.StatusConflict, map[string]any{
+				"error": "two-factor authentication already enabled",
+			})
+			return
+		}
+
+		if errors.Is(err, ErrInvalidTwoFactorCode) {
+			jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+				"error": "invalid two-factor code",
+			})
+			return
+		}
+
+		logrus.WithField("err", err.Error()).Error("Handler.ConfirmTwoFactor")
+
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "unexpected internal server error",
+		})
+		return
+	}
+
+	jsonutils.EncodeJson(w, r, http.StatusOK, map[string]any{
+		"recovery_codes": codes,
+	})
+}
+
+func (m *AuthHandler) DisableTwoFactor(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, ok := ctx.Value(middlewares.UserIDKey).(string)
+	if !ok {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "user ID not found in context",
+		})
+		return
+	}
+
+	parsedID, err := uuid.Parse(id)
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "invalid user ID format",
+		})
+		return
+	}
+
+	data, problems, err := jsonutils.DecodeValidJson[*DisableTwoFactorReq](r)
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+
+	if err := m.svc.DisableTwoFactor(ctx, parsedID, data.Password); err != nil {
+		if errors.Is(err, ErrInvalidCredentials) {
+			jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+				"error": "invalid credentials",
+			})
+			return
+		}
+
+		if errors.Is(err, ErrNotFound) {
+			jsonutils.EncodeJson(w, r, http.StatusNotFound, map[string]any{
+				"error": "user not found",
+			})
+			return
+		}
+
+		logrus.WithField("err", err.Error()).Error("Handler.DisableTwoFactor")
+
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "unexpected internal server error",
+		})
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (m *AuthHandler) UnlockAccount(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, ok := ctx.Value(middlewares.UserIDKey).(string)
+	if !ok {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "user ID not found in context",
+		})
+		return
+	}
+
+	adminID, err := uuid.Parse(id)
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "invalid user ID format",
+		})
+		return
+	}
+
+	userID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "invalid user ID format",
+		})
+		return
+	}
+
+	if err := m.svc.UnlockAccount(ctx, userID, adminID); err != nil {
+		if errors.Is(err, ErrNotFound) {
+			jsonutils.EncodeJson(w, r, http.StatusNotFound, map[string]any{
+				"error": "user not found",
+			})
+			return
+		}
+
+		logrus.WithField("err", err.Error()).Error("Handler.UnlockAccount")
+
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "unexpected internal server error",
+		})
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeLocked(w http.ResponseWriter, r *http.Request, err error) bool {
+	var locked *lockout.LockedError
+	if !errors.As(err, &locked) {
+		return false
+	}
+
+	seconds := int(math.Ceil(locked.RetryAfter.Seconds()))
+	w.Header().Set("Retry-After", strconv.Itoa(seconds))
+
+	jsonutils.EncodeJson(w, r, http.StatusTooManyRequests, map[string]any{
+		"error":       "too many failed attempts, try again later",
+		"retry_after": seconds,
+	})
+
+	return true
+}
+
+func (m *AuthHandler) ListSessions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, ok := ctx.Value(middlewares.UserIDKey).(string)
+	if !ok {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "user ID not found in context",
+		})
+		return
+	}
+
+	parsedID, err := uuid.Parse(id)
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "invalid user ID format",
+		})
+		return
+	}
+
+	records, err := m.svc.ListSessions(ctx, parsedID)
+	if err != nil {
+		logrus.WithField("err", err.Error()).Error("Handler.ListSessions")
+
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "unexpected internal server error",
+		})
+		return
+	}
+
+	var current string
+	if claims, ok := ctx.Value(middlewares.ClaimsKey).(*token.Claims); ok {
+		current = claims.SessionID
+	}
+
+	res := make([]SessionResponse, len(records))
+	for i, record := range records {
+		res[i] = SessionResponse{
+			ID:         record.ID,
+			UserAgent:  record.UserAgent,
+			IP:         record.Ip,
+			Current:    record.ID.String() == current,
+			CreatedAt:  record.CreatedAt,
+			LastSeenAt: record.LastSeenAt,
+		}
+	}
+
+	jsonutils.EncodeJson(w, r, http.StatusOK, map[string]any{
+		"sessions": res,
+	})
+}
+
+func (m *AuthHandler) RevokeSession(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, ok := ctx.Value(middlewares.UserIDKey).(string)
+	if !ok {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "user ID not found in context",
+		})
+		return
+	}
+
+	parsedID, err := uuid.Parse(id)
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "invalid user ID format",
+		})
+		return
+	}
+
+	sessionID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "invalid session ID format",
+		})
+		return
+	}
+
+	if err := m.svc.RevokeSession(ctx, parsedID, sessionID); err != nil {
+		if errors.Is(err, ErrSessionNotFound) {
+			jsonutils.EncodeJson(w, r, http.StatusNotFound, map[string]any{
+				"error": "session not found",
+			})
+			return
+		}
+
+		logrus.WithField("err", err.Error()).Error("Handler.RevokeSession")
+
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "unexpected internal server error",
+		})
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (m *AuthHandler) ListOIDCProviders(w http.ResponseWriter, r *http.Request) {
+	jsonutils.EncodeJson(w, r, http.StatusOK, map[string]any{
+		"providers": m.svc.OIDCProviders(),
+	})
+}
+
+func (m *AuthHandler) AuthorizeOIDC(w http.ResponseWriter, r *http.Request) {
+	authURL, err := m.svc.BeginOIDCLogin(r.Context(), chi.URLParam(r, "provider"))
+	if err != nil {
+		if errors.Is(err, ErrUnknownProvider) {
+			jsonutils.EncodeJson(w, r, http.StatusNotFound, map[string]any{
+				"error": "unknown identity provider",
+			})
+			return
+		}
+
+		logrus.WithField("err", err.Error()).Error("Handler.AuthorizeOIDC")
+
+		jsonutils.EncodeJson(w, r, http.StatusBadGateway, map[string]any{
+			"error": "identity provider unavailable",
+		})
+		return
+	}
+
+	http.Redirect(w, r, authURL, http.StatusFound)
+}
+
+func (m *AuthHandler) OIDCCallback(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	if providerErr := query.Get("error"); providerErr != "" {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "identity provider returned an error: " + providerErr,
+		})
+		return
+	}
+
+	state, code := query.Get("state"), query.Get("code")
+	if state == "" || code == "" {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "missing state or code",
+		})
+		return
+	}
+
+	id, err := m.svc.CompleteOIDCLogin(r.Context(), chi.URLParam(r, "provider"), state, code)
+	if err != nil {
+		if middlewares.WriteSuspended(w, r, err) {
+			return
+		}
+
+		switch {
+		case errors.Is(err, ErrUnknownProvider):
+			jsonutils.EncodeJson(w, r, http.StatusNotFound, map[string]any{
+				"error": "unknown identity provider",
+			})
+		case errors.Is(err, ErrInvalidOIDCState):
+			jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+				"error": "invalid or expired login state",
+			})
+		case errors.Is(err, ErrOIDCLoginFailed):
+			jsonutils.EncodeJson(w, r, http.StatusUnauthorized, map[string]any{
+				"error": "identity provider login failed",
+			})
+		case errors.Is(err, ErrOIDCEmailNotVerified):
+			jsonutils.EncodeJson(w, r, http.StatusForbidden, map[string]any{
+				"error": "identity provider did not return a verified email",
+			})
+		case errors.Is(err, ErrOIDCAccountConflict):
+			jsonutils.EncodeJson(w, r, http.StatusConflict, map[string]any{
+				"error": "an unverified account already uses this email; verify it before signing in with this provider",
+			})
+		case errors.Is(err, ErrPasswordResetRequired):
+			jsonutils.EncodeJson(w, r, http.StatusForbidden, map[string]any{
+				"error": "password reset required, check your email for a reset token",
+			})
+		default:
+			logrus.WithField("err", err.Error()).Error("Handler.OIDCCallback")
+
+			jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+				"error": "unexpected internal server error",
+			})
+		}
+		return
+	}
+
+	m.completeLogin(w, r, id, "Handler.OIDCCallback")
+}
@@ -0,0 +1,202 @@
+package oidc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	gooidc "github.com/coreos/go-oidc/v3/oidc"
+	"golang.org/x/oauth2"
+)
+
+var (
+	ErrUnknownProvider = errors.New("unknown identity provider")
+	ErrInvalidIDToken  = errors.New("invalid id token")
+	ErrExchangeFailed  = errors.New("authorization code exchange failed")
+)
+
+var defaultScopes = []string{gooidc.ScopeOpenID, "email", "profile"}
+
+type ProviderConfig struct {
+	Name         string
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+}
+
+type Config struct {
+	Providers map[string]ProviderConfig
+}
+
+type Identity struct {
+	Provider          string
+	Subject           string
+	Email             string
+	EmailVerified     bool
+	Name              string
+	PreferredUsername string
+}
+
+type Client interface {
+	Providers() []string
+	AuthCodeURL(ctx context.Context, provider, state, nonce, verifier string) (string, error)
+	Exchange(ctx context.Context, provider, code, nonce, verifier string) (*Identity, error)
+}
+
+func LoadConfig() (Config, error) {
+	cfg := Config{Providers: map[string]ProviderConfig{}}
+
+	raw := os.Getenv("GOBID_OIDC_PROVIDERS")
+	for _, name := range strings.Split(raw, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+
+		prefix := "GOBID_OIDC_" + strings.ToUpper(strings.ReplaceAll(name, "-", "_")) + "_"
+
+		provider := ProviderConfig{
+			Name:         name,
+			Issuer:       os.Getenv(prefix + "ISSUER"),
+			ClientID:     os.Getenv(prefix + "CLIENT_ID"),
+			ClientSecret: os.Getenv(prefix + "CLIENT_SECRET"),
+			RedirectURL:  os.Getenv(prefix + "REDIRECT_URL"),
+			Scopes:       defaultScopes,
+		}
+
+		for _, key := range []string{"ISSUER", "CLIENT_ID", "REDIRECT_URL"} {
+			if os.Getenv(prefix+key) == "" {
+				return Config{}, fmt.Errorf("missing %s%s for provider %q", prefix, key, name)
+			}
+		}
+
+		if scopes := strings.Fields(strings.ReplaceAll(os.Getenv(prefix+"SCOPES"), ",", " ")); len(scopes) > 0 {
+			provider.Scopes = scopes
+		}
+
+		cfg.Providers[name] = provider
+	}
+
+	return cfg, nil
+}
+
+type client struct {
+	cfg Config
+
+	mu        sync.Mutex
+	providers map[string]*gooidc.Provider
+}
+
+func NewClient(cfg Config) Client {
+	return &client{
+		cfg:       cfg,
+		providers: map[string]*gooidc.Provider{},
+	}
+}
+
+func (c *client) Providers() []string {
+	names := make([]string, 0, len(c.cfg.Providers))
+	for name := range c.cfg.Providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+func (c *client) AuthCodeURL(ctx context.Context, provider, state, nonce, verifier string) (string, error) {
+	conf, _, err := c.oauth2Config(ctx, provider)
+	if err != nil {
+		return "", err
+	}
+
+	return conf.AuthCodeURL(state, gooidc.Nonce(nonce), oauth2.S256ChallengeOption(verifier)), nil
+}
+
+func (c *client) Exchange(ctx context.Context, provider, code, nonce, verifier string) (*Identity, error) {
+	conf, remote, err := c.oauth2Config(ctx, provider)
+	if err != nil {
+		return nil, err
+	}
+
+	tok, err := conf.Exchange(ctx, code, oauth2.VerifierOption(verifier))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrExchangeFailed, err)
+	}
+
+	rawIDToken, ok := tok.Extra("id_token").(string)
+	if !ok || rawIDToken == "" {
+		return nil, fmt.Errorf("%w: missing id_token", ErrInvalidIDToken)
+	}
+
+	idToken, err := remote.Verifier(&gooidc.Config{ClientID: conf.ClientID}).Verify(ctx, rawIDToken)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidIDToken, err)
+	}
+
+	if idToken.Nonce != nonce {
+		return nil, fmt.Errorf("%w: nonce mismatch", ErrInvalidIDToken)
+	}
+
+	var claims struct {
+		Email             string `json:"email"`
+		EmailVerified     bool   `json:"email_verified"`
+		Name              string `json:"name"`
+		PreferredUsername string `json:"preferred_username"`
+	}
+	if err := idToken.Claims(&claims); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidIDToken, err)
+	}
+
+	return &Identity{
+		Provider:          provider,
+		Subject:           idToken.Subject,
+		Email:             strings.TrimSpace(claims.Email),
+		EmailVerified:     claims.EmailVerified,
+		Name:              claims.Name,
+		PreferredUsername: claims.PreferredUsername,
+	}, nil
+}
+
+func (c *client) oauth2Config(ctx context.Context, name string) (*oauth2.Config, *gooidc.Provider, error) {
+	cfg, ok := c.cfg.Providers[name]
+	if !ok {
+		return nil, nil, ErrUnknownProvider
+	}
+
+	remote, err := c.discover(ctx, cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &oauth2.Config{
+		ClientID:     cfg.ClientID,
+		ClientSecret: cfg.ClientSecret,
+		RedirectURL:  cfg.RedirectURL,
+		Endpoint:     remote.Endpoint(),
+		Scopes:       cfg.Scopes,
+	}, remote, nil
+}
+
+func (c *client) discover(ctx context.Context, cfg ProviderConfig) (*gooidc.Provider, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if remote, ok := c.providers[cfg.Name]; ok {
+		return remote, nil
+	}
+
+	remote, err := gooidc.NewProvider(ctx, cfg.Issuer)
+	if err != nil {
+		return nil, fmt.Errorf("oidc.discover %s: %v", cfg.Name, err)
+	}
+	c.providers[cfg.Name] = remote
+
+	return remote, nil
+}
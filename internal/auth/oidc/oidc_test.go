@@ -0,0 +1,197 @@
+package oidc
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+
+	"github.com/EduardoMark/gobid/internal/auth/oidc/oidctest"
+	"golang.org/x/oauth2"
+)
+
+func testClient(provider *oidctest.Provider) Client {
+	return NewClient(Config{Providers: map[string]ProviderConfig{
+		"fake": {
+			Name:         "fake",
+			Issuer:       provider.URL,
+			ClientID:     oidctest.ClientID,
+			ClientSecret: oidctest.ClientSecret,
+			RedirectURL:  oidctest.RedirectURL,
+			Scopes:       defaultScopes,
+		},
+	}})
+}
+
+func TestAuthCodeURL(t *testing.T) {
+	provider := oidctest.NewProvider(t)
+	client := testClient(provider)
+	verifier := oauth2.GenerateVerifier()
+
+	authURL, err := client.AuthCodeURL(context.Background(), "fake", "state-1", "nonce-1", verifier)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	u, err := url.Parse(authURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	query := u.Query()
+	want := map[string]string{
+		"state":                 "state-1",
+		"nonce":                 "nonce-1",
+		"code_challenge":        oauth2.S256ChallengeFromVerifier(verifier),
+		"code_challenge_method": "S256",
+		"scope":                 "openid email profile",
+	}
+	for key, value := range want {
+		if got := query.Get(key); got != value {
+			t.Errorf("authorization url %s = %q, want %q", key, got, value)
+		}
+	}
+
+	if _, err := client.AuthCodeURL(context.Background(), "missing", "state-1", "nonce-1", verifier); !errors.Is(err, ErrUnknownProvider) {
+		t.Fatalf("AuthCodeURL(missing) error = %v, want %v", err, ErrUnknownProvider)
+	}
+}
+
+func TestExchange(t *testing.T) {
+	provider := oidctest.NewProvider(t)
+	client := testClient(provider)
+	rogueKey := oidctest.NewKey(t)
+
+	claims := oidctest.Claims{
+		Subject:           "subject-1",
+		Email:             " alice@example.com ",
+		EmailVerified:     true,
+		Name:              "Alice Example",
+		PreferredUsername: "alice",
+	}
+
+	tests := []struct {
+		name          string
+		claims        oidctest.Claims
+		opts          []oidctest.Option
+		provider      string
+		wrongVerifier bool
+		wantErr       error
+		want          *Identity
+	}{
+		{
+			name:     "verified identity",
+			claims:   claims,
+			provider: "fake",
+			want: &Identity{
+				Provider:          "fake",
+				Subject:           "subject-1",
+				Email:             "alice@example.com",
+				EmailVerified:     true,
+				Name:              "Alice Example",
+				PreferredUsername: "alice",
+			},
+		},
+		{
+			name:     "unverified email is reported, not hidden",
+			claims:   oidctest.Claims{Subject: "subject-2", Email: "bob@example.com"},
+			provider: "fake",
+			want:     &Identity{Provider: "fake", Subject: "subject-2", Email: "bob@example.com"},
+		},
+		{
+			name:          "pkce verifier mismatch",
+			claims:        claims,
+			provider:      "fake",
+			wrongVerifier: true,
+			wantErr:       ErrExchangeFailed,
+		},
+		{
+			name:     "id token signed with an unknown key",
+			claims:   claims,
+			opts:     []oidctest.Option{oidctest.SignedWith(rogueKey)},
+			provider: "fake",
+			wantErr:  ErrInvalidIDToken,
+		},
+		{
+			name:     "id token for another client",
+			claims:   claims,
+			opts:     []oidctest.Option{oidctest.WithAudience("someone-else")},
+			provider: "fake",
+			wantErr:  ErrInvalidIDToken,
+		},
+		{
+			name:     "nonce mismatch",
+			claims:   claims,
+			opts:     []oidctest.Option{oidctest.WithNonce("replayed-nonce")},
+			provider: "fake",
+			wantErr:  ErrInvalidIDToken,
+		},
+		{
+			name:     "missing id token",
+			claims:   claims,
+			opts:     []oidctest.Option{oidctest.WithoutIDToken()},
+			provider: "fake",
+			wantErr:  ErrInvalidIDToken,
+		},
+		{
+			name:     "unknown provider",
+			claims:   claims,
+			provider: "missing",
+			wantErr:  ErrUnknownProvider,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			verifier := oauth2.GenerateVerifier()
+
+			authURL, err := client.AuthCodeURL(ctx, "fake", "state-1", "nonce-1", verifier)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			code, _ := provider.Authorize(authURL, tt.claims, tt.opts...)
+
+			if tt.wrongVerifier {
+				verifier = oauth2.GenerateVerifier()
+			}
+
+			identity, err := client.Exchange(ctx, tt.provider, code, "nonce-1", verifier)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Exchange() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Exchange() unexpected error: %v", err)
+			}
+			if *identity != *tt.want {
+				t.Fatalf("Exchange() = %+v, want %+v", identity, tt.want)
+			}
+		})
+	}
+}
+
+func TestExchangeCodeIsSingleUse(t *testing.T) {
+	provider := oidctest.NewProvider(t)
+	client := testClient(provider)
+	ctx := context.Background()
+	verifier := oauth2.GenerateVerifier()
+
+	authURL, err := client.AuthCodeURL(ctx, "fake", "state-1", "nonce-1", verifier)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	code, _ := provider.Authorize(authURL, oidctest.Claims{Subject: "subject-1", Email: "alice@example.com", EmailVerified: true})
+
+	if _, err := client.Exchange(ctx, "fake", code, "nonce-1", verifier); err != nil {
+		t.Fatalf("Exchange() unexpected error: %v", err)
+	}
+
+	if _, err := client.Exchange(ctx, "fake", code, "nonce-1", verifier); !errors.Is(err, ErrExchangeFailed) {
+		t.Fatalf("Exchange(reused code) error = %v, want %v", err, ErrExchangeFailed)
+	}
+}
@@ -0,0 +1,251 @@
+package oidctest
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	ClientID     = "gobid-test-client"
+	ClientSecret = "gobid-test-secret"
+	RedirectURL  = "https://gobid.test/auth/oidc/callback"
+	keyID        = "oidctest-key"
+)
+
+type Claims struct {
+	Subject           string
+	Email             string
+	EmailVerified     bool
+	Name              string
+	PreferredUsername string
+}
+
+type Option func(*grant)
+
+func WithNonce(nonce string) Option {
+	return func(g *grant) { g.nonce = nonce }
+}
+
+func WithAudience(audience string) Option {
+	return func(g *grant) { g.audience = audience }
+}
+
+func SignedWith(key *rsa.PrivateKey) Option {
+	return func(g *grant) { g.key = key }
+}
+
+func WithoutIDToken() Option {
+	return func(g *grant) { g.omitIDToken = true }
+}
+
+type grant struct {
+	challenge   string
+	nonce       string
+	audience    string
+	claims      Claims
+	key         *rsa.PrivateKey
+	omitIDToken bool
+}
+
+type Provider struct {
+	URL string
+
+	t      *testing.T
+	server *httptest.Server
+	key    *rsa.PrivateKey
+
+	mu     sync.Mutex
+	grants map[string]*grant
+}
+
+func NewProvider(t *testing.T) *Provider {
+	t.Helper()
+
+	p := &Provider{
+		t:      t,
+		key:    NewKey(t),
+		grants: map[string]*grant{},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /.well-known/openid-configuration", p.discovery)
+	mux.HandleFunc("GET /jwks", p.jwks)
+	mux.HandleFunc("POST /token", p.token)
+
+	p.server = httptest.NewServer(mux)
+	p.URL = p.server.URL
+	t.Cleanup(p.server.Close)
+
+	return p
+}
+
+func NewKey(t *testing.T) *rsa.PrivateKey {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return key
+}
+
+func (p *Provider) Authorize(authURL string, claims Claims, opts ...Option) (code, state string) {
+	p.t.Helper()
+
+	u, err := url.Parse(authURL)
+	if err != nil {
+		p.t.Fatalf("oidctest: invalid authorization url: %v", err)
+	}
+
+	query := u.Query()
+	if got := u.Scheme + "://" + u.Host + u.Path; got != p.URL+"/authorize" {
+		p.t.Fatalf("oidctest: authorization url %q does not target the provider", got)
+	}
+	if query.Get("response_type") != "code" || query.Get("client_id") != ClientID || query.Get("redirect_uri") != RedirectURL {
+		p.t.Fatalf("oidctest: unexpected authorization request %v", query)
+	}
+	if query.Get("code_challenge_method") != "S256" || query.Get("code_challenge") == "" {
+		p.t.Fatalf("oidctest: authorization request without an S256 code challenge: %v", query)
+	}
+
+	g := &grant{
+		challenge: query.Get("code_challenge"),
+		nonce:     query.Get("nonce"),
+		audience:  ClientID,
+		claims:    claims,
+		key:       p.key,
+	}
+	for _, opt := range opts {
+		opt(g)
+	}
+
+	code = rand.Text()
+
+	p.mu.Lock()
+	p.grants[code] = g
+	p.mu.Unlock()
+
+	return code, query.Get("state")
+}
+
+func (p *Provider) discovery(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]any{
+		"issuer":                                p.URL,
+		"authorization_endpoint":                p.URL + "/authorize",
+		"token_endpoint":                        p.URL + "/token",
+		"jwks_uri":                              p.URL + "/jwks",
+		"response_types_supported":              []string{"code"},
+		"subject_types_supported":               []string{"public"},
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+		"code_challenge_methods_supported":      []string{"S256"},
+	})
+}
+
+func (p *Provider) jwks(w http.ResponseWriter, r *http.Request) {
+	pub := p.key.PublicKey
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": keyID,
+			"alg": "RS256",
+			"use": "sig",
+			"n":   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+		}},
+	})
+}
+
+func (p *Provider) token(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_request"})
+		return
+	}
+
+	clientID, clientSecret, ok := r.BasicAuth()
+	if !ok {
+		clientID, clientSecret = r.PostForm.Get("client_id"), r.PostForm.Get("client_secret")
+	}
+	if clientID != ClientID || clientSecret != ClientSecret {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid_client"})
+		return
+	}
+
+	p.mu.Lock()
+	g, ok := p.grants[r.PostForm.Get("code")]
+	delete(p.grants, r.PostForm.Get("code"))
+	p.mu.Unlock()
+
+	if !ok || r.PostForm.Get("grant_type") != "authorization_code" || r.PostForm.Get("redirect_uri") != RedirectURL {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	if base64.RawURLEncoding.EncodeToString(sum[:]) != g.challenge {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant", "error_description": "pkce verification failed"})
+		return
+	}
+
+	res := map[string]any{
+		"access_token": rand.Text(),
+		"token_type":   "Bearer",
+		"expires_in":   3600,
+	}
+
+	if !g.omitIDToken {
+		idToken, err := p.idToken(g)
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "server_error"})
+			return
+		}
+		res["id_token"] = idToken
+	}
+
+	writeJSON(w, http.StatusOK, res)
+}
+
+func (p *Provider) idToken(g *grant) (string, error) {
+	now := time.Now()
+
+	claims := jwt.MapClaims{
+		"iss":            p.URL,
+		"sub":            g.claims.Subject,
+		"aud":            g.audience,
+		"iat":            now.Unix(),
+		"exp":            now.Add(time.Hour).Unix(),
+		"nonce":          g.nonce,
+		"email":          g.claims.Email,
+		"email_verified": g.claims.EmailVerified,
+	}
+	if g.claims.Name != "" {
+		claims["name"] = g.claims.Name
+	}
+	if g.claims.PreferredUsername != "" {
+		claims["preferred_username"] = g.claims.PreferredUsername
+	}
+
+	tok := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	tok.Header["kid"] = keyID
+
+	return tok.SignedString(g.key)
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
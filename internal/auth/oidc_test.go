@@ -0,0 +1,372 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/EduardoMark/gobid/internal/auth/oidc"
+	"github.com/EduardoMark/gobid/internal/auth/oidc/oidctest"
+	"github.com/EduardoMark/gobid/internal/store/pgstore"
+	"github.com/EduardoMark/gobid/internal/suspension"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type memStates struct {
+	states map[pgstore.ConsumeOIDCLoginStateParams]*pgstore.OidcLoginState
+}
+
+func (m *memStates) DeleteExpiredOIDCLoginStates(ctx context.Context) error {
+	return nil
+}
+
+func (m *memStates) CreateOIDCLoginState(ctx context.Context, arg pgstore.CreateOIDCLoginStateParams) error {
+	m.states[pgstore.ConsumeOIDCLoginStateParams{StateHash: arg.StateHash, Provider: arg.Provider}] = &pgstore.OidcLoginState{
+		StateHash:    arg.StateHash,
+		Provider:     arg.Provider,
+		Nonce:        arg.Nonce,
+		CodeVerifier: arg.CodeVerifier,
+		ExpiresAt:    arg.ExpiresAt,
+	}
+	return nil
+}
+
+func (m *memStates) ConsumeOIDCLoginState(ctx context.Context, arg pgstore.ConsumeOIDCLoginStateParams) (*pgstore.OidcLoginState, error) {
+	state, ok := m.states[arg]
+	if !ok {
+		return nil, pgx.ErrNoRows
+	}
+	delete(m.states, arg)
+	return state, nil
+}
+
+type memIdentities struct {
+	users      map[string]*pgstore.User
+	identities []*pgstore.UserIdentity
+	touched    []uuid.UUID
+	roles      []uuid.UUID
+}
+
+func (m *memIdentities) GetUserIdentity(ctx context.Context, arg pgstore.GetUserIdentityParams) (*pgstore.UserIdentity, error) {
+	for _, identity := range m.identities {
+		if identity.Provider == arg.Provider && identity.Subject == arg.Subject {
+			return identity, nil
+		}
+	}
+	return nil, pgx.ErrNoRows
+}
+
+func (m *memIdentities) TouchUserIdentity(ctx context.Context, arg pgstore.TouchUserIdentityParams) error {
+	m.touched = append(m.touched, arg.ID)
+	return nil
+}
+
+func (m *memIdentities) CreateUserIdentity(ctx context.Context, arg pgstore.CreateUserIdentityParams) error {
+	m.identities = append(m.identities, &pgstore.UserIdentity{
+		ID:       uuid.New(),
+		UserID:   arg.UserID,
+		Provider: arg.Provider,
+		Subject:  arg.Subject,
+		Email:    arg.Email,
+	})
+	return nil
+}
+
+func (m *memIdentities) GetUserByEmail(ctx context.Context, email string) (*pgstore.User, error) {
+	record, ok := m.users[email]
+	if !ok {
+		return nil, pgx.ErrNoRows
+	}
+	return record, nil
+}
+
+func (m *memIdentities) CreateUser(ctx context.Context, arg pgstore.CreateUserParams) (uuid.UUID, error) {
+	record := &pgstore.User{ID: uuid.New(), Username: arg.Username, Email: arg.Email}
+	m.users[arg.Email] = record
+	return record.ID, nil
+}
+
+func (m *memIdentities) MarkEmailVerified(ctx context.Context, id uuid.UUID) error {
+	for _, record := range m.users {
+		if record.ID == id {
+			record.EmailVerifiedAt = pgtype.Timestamptz{Valid: true}
+			return nil
+		}
+	}
+	return pgx.ErrNoRows
+}
+
+func (m *memIdentities) AssignDefaultRoles(ctx context.Context, userID uuid.UUID) error {
+	m.roles = append(m.roles, userID)
+	return nil
+}
+
+func (m *memIdentities) GetUserByID(ctx context.Context, id uuid.UUID) (*pgstore.User, error) {
+	for _, record := range m.users {
+		if record.ID == id {
+			return record, nil
+		}
+	}
+	return nil, pgx.ErrNoRows
+}
+
+func testOIDCService(provider *oidctest.Provider) AuthService {
+	return AuthService{
+		oidc: oidc.NewClient(oidc.Config{Providers: map[string]oidc.ProviderConfig{
+			"fake": {
+				Name:         "fake",
+				Issuer:       provider.URL,
+				ClientID:     oidctest.ClientID,
+				ClientSecret: oidctest.ClientSecret,
+				RedirectURL:  oidctest.RedirectURL,
+				Scopes:       []string{"openid", "email", "profile"},
+			},
+		}}),
+	}
+}
+
+func TestExchangeOIDCLogin(t *testing.T) {
+	provider := oidctest.NewProvider(t)
+	svc := testOIDCService(provider)
+	rogueKey := oidctest.NewKey(t)
+	alice := oidctest.Claims{Subject: "subject-1", Email: "alice@example.com", EmailVerified: true}
+
+	tests := []struct {
+		name   string
+		opts   []oidctest.Option
+		tamper func(states *memStates, state string) string
+		replay bool
+		want   error
+	}{
+		{
+			name: "valid callback",
+		},
+		{
+			name: "state mismatch",
+			tamper: func(states *memStates, state string) string {
+				return state + "-forged"
+			},
+			want: ErrInvalidOIDCState,
+		},
+		{
+			name:   "state replayed after a successful login",
+			replay: true,
+			want:   ErrInvalidOIDCState,
+		},
+		{
+			name: "pkce verifier mismatch",
+			tamper: func(states *memStates, state string) string {
+				for _, stored := range states.states {
+					stored.CodeVerifier = "a-different-verifier-that-is-long-enough-for-pkce-checks"
+				}
+				return state
+			},
+			want: ErrOIDCLoginFailed,
+		},
+		{
+			name: "bad id token signature",
+			opts: []oidctest.Option{oidctest.SignedWith(rogueKey)},
+			want: ErrOIDCLoginFailed,
+		},
+		{
+			name: "nonce mismatch",
+			opts: []oidctest.Option{oidctest.WithNonce("another-login")},
+			want: ErrOIDCLoginFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			states := &memStates{states: map[pgstore.ConsumeOIDCLoginStateParams]*pgstore.OidcLoginState{}}
+
+			authURL, err := svc.beginOIDCLogin(ctx, states, "fake")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			code, state := provider.Authorize(authURL, alice, tt.opts...)
+			if tt.tamper != nil {
+				state = tt.tamper(states, state)
+			}
+
+			identity, err := svc.exchangeOIDCLogin(ctx, states, "fake", state, code)
+			if tt.replay {
+				if err != nil {
+					t.Fatalf("exchangeOIDCLogin() unexpected error: %v", err)
+				}
+				_, err = svc.exchangeOIDCLogin(ctx, states, "fake", state, code)
+			}
+
+			if tt.want != nil {
+				if !errors.Is(err, tt.want) {
+					t.Fatalf("exchangeOIDCLogin() error = %v, want %v", err, tt.want)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("exchangeOIDCLogin() unexpected error: %v", err)
+			}
+			if identity.Subject != alice.Subject || identity.Email != alice.Email || !identity.EmailVerified {
+				t.Fatalf("exchangeOIDCLogin() = %+v", identity)
+			}
+		})
+	}
+}
+
+func TestResolveIdentity(t *testing.T) {
+	verifiedID := uuid.New()
+	unverifiedID := uuid.New()
+	linkedID := uuid.New()
+
+	newStore := func() *memIdentities {
+		return &memIdentities{
+			users: map[string]*pgstore.User{
+				"alice@example.com": {ID: verifiedID, Email: "alice@example.com", EmailVerifiedAt: pgtype.Timestamptz{Valid: true}},
+				"bob@example.com":   {ID: unverifiedID, Email: "bob@example.com"},
+			},
+			identities: []*pgstore.UserIdentity{
+				{ID: uuid.New(), UserID: linkedID, Provider: "fake", Subject: "linked-subject", Email: "carol@example.com"},
+			},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		identity oidc.Identity
+		wantUser func(store *memIdentities) uuid.UUID
+		wantErr  error
+		check    func(t *testing.T, store *memIdentities, userID uuid.UUID)
+	}{
+		{
+			name:     "links to an existing verified account",
+			identity: oidc.Identity{Provider: "fake", Subject: "new-subject", Email: "alice@example.com", EmailVerified: true},
+			wantUser: func(*memIdentities) uuid.UUID { return verifiedID },
+			check: func(t *testing.T, store *memIdentities, userID uuid.UUID) {
+				if len(store.identities) != 2 || store.identities[1].UserID != verifiedID {
+					t.Fatalf("identity not linked to the existing account: %+v", store.identities)
+				}
+				if len(store.roles) != 0 {
+					t.Fatalf("existing account should not get default roles again")
+				}
+			},
+		},
+		{
+			name:     "unverified provider email is rejected",
+			identity: oidc.Identity{Provider: "fake", Subject: "new-subject", Email: "alice@example.com"},
+			wantErr:  ErrOIDCEmailNotVerified,
+		},
+		{
+			name:     "missing provider email is rejected",
+			identity: oidc.Identity{Provider: "fake", Subject: "new-subject", EmailVerified: true},
+			wantErr:  ErrOIDCEmailNotVerified,
+		},
+		{
+			name:     "does not take over an unverified local account",
+			identity: oidc.Identity{Provider: "fake", Subject: "new-subject", Email: "bob@example.com", EmailVerified: true},
+			wantErr:  ErrOIDCAccountConflict,
+		},
+		{
+			name:     "already linked identity signs in without email checks",
+			identity: oidc.Identity{Provider: "fake", Subject: "linked-subject", Email: "carol@new.example.com"},
+			wantUser: func(*memIdentities) uuid.UUID { return linkedID },
+			check: func(t *testing.T, store *memIdentities, userID uuid.UUID) {
+				if len(store.touched) != 1 || len(store.identities) != 1 {
+					t.Fatalf("linked identity should be touched, not duplicated")
+				}
+			},
+		},
+		{
+			name:     "creates a verified account for a new email",
+			identity: oidc.Identity{Provider: "fake", Subject: "new-subject", Email: "dave@example.com", EmailVerified: true, PreferredUsername: "dave"},
+			wantUser: func(store *memIdentities) uuid.UUID { return store.users["dave@example.com"].ID },
+			check: func(t *testing.T, store *memIdentities, userID uuid.UUID) {
+				record := store.users["dave@example.com"]
+				if !record.EmailVerifiedAt.Valid || record.Username != "dave" {
+					t.Fatalf("new account = %+v", record)
+				}
+				if len(store.roles) != 1 || store.roles[0] != userID {
+					t.Fatalf("new account should get default roles")
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newStore()
+
+			userID, err := AuthService{}.resolveIdentity(context.Background(), store, &tt.identity)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("resolveIdentity() error = %v, want %v", err, tt.wantErr)
+				}
+				if len(store.identities) != 1 {
+					t.Fatalf("rejected identity was linked: %+v", store.identities)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("resolveIdentity() unexpected error: %v", err)
+			}
+			if want := tt.wantUser(store); userID != want {
+				t.Fatalf("resolveIdentity() = %s, want %s", userID, want)
+			}
+			if tt.check != nil {
+				tt.check(t, store, userID)
+			}
+		})
+	}
+}
+
+func TestSignInIdentity(t *testing.T) {
+	tests := []struct {
+		name    string
+		user    pgstore.User
+		wantErr error
+	}{
+		{
+			name: "active account signs in",
+		},
+		{
+			name:    "forced password reset blocks the linked identity",
+			user:    pgstore.User{PasswordResetRequired: true},
+			wantErr: ErrPasswordResetRequired,
+		},
+		{
+			name:    "banned account is rejected",
+			user:    pgstore.User{Banned: true, SuspendedAt: pgtype.Timestamptz{Valid: true}},
+			wantErr: suspension.ErrSuspended,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			record := tt.user
+			record.ID = uuid.New()
+			record.Email = "carol@example.com"
+			store := &memIdentities{
+				users: map[string]*pgstore.User{record.Email: &record},
+				identities: []*pgstore.UserIdentity{
+					{ID: uuid.New(), UserID: record.ID, Provider: "fake", Subject: "linked-subject", Email: record.Email},
+				},
+			}
+
+			userID, err := AuthService{}.signInIdentity(context.Background(), store, &oidc.Identity{Provider: "fake", Subject: "linked-subject"})
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("signInIdentity() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("signInIdentity() unexpected error: %v", err)
+			}
+			if userID != record.ID {
+				t.Fatalf("signInIdentity() = %s, want %s", userID, record.ID)
+			}
+		})
+	}
+}
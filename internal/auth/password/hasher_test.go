@@ -0,0 +1,173 @@
+package password
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+var testParams = Params{
+	Memory:      1024,
+	Iterations:  1,
+	Parallelism: 1,
+	SaltLength:  16,
+	KeyLength:   32,
+}
+
+func TestVerify(t *testing.T) {
+	hasher := NewArgon2idHasher(testParams)
+
+	current, err := hasher.Hash("correct horse")
+	if err != nil {
+		t.Fatalf("Hash() unexpected error: %v", err)
+	}
+
+	weak, err := NewArgon2idHasher(Params{Memory: 512, Iterations: 1, Parallelism: 1, SaltLength: 8, KeyLength: 16}).Hash("correct horse")
+	if err != nil {
+		t.Fatalf("Hash() unexpected error: %v", err)
+	}
+
+	legacy, err := bcrypt.GenerateFromPassword([]byte("correct horse"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("bcrypt.GenerateFromPassword() unexpected error: %v", err)
+	}
+
+	parts := strings.Split(current, "$")
+
+	tests := []struct {
+		name       string
+		hash       string
+		password   string
+		wantMatch  bool
+		wantRehash bool
+		wantErr    error
+	}{
+		{
+			name:      "round trip",
+			hash:      current,
+			password:  "correct horse",
+			wantMatch: true,
+		},
+		{
+			name:     "wrong password",
+			hash:     current,
+			password: "battery staple",
+		},
+		{
+			name:     "empty hash never matches",
+			hash:     "",
+			password: "correct horse",
+		},
+		{
+			name:       "legacy bcrypt hash asks for a rehash",
+			hash:       string(legacy),
+			password:   "correct horse",
+			wantMatch:  true,
+			wantRehash: true,
+		},
+		{
+			name:     "legacy bcrypt hash with wrong password",
+			hash:     string(legacy),
+			password: "battery staple",
+		},
+		{
+			name:       "weaker params ask for a rehash",
+			hash:       weak,
+			password:   "correct horse",
+			wantMatch:  true,
+			wantRehash: true,
+		},
+		{
+			name:     "truncated hash",
+			hash:     strings.Join(parts[:5], "$"),
+			password: "correct horse",
+			wantErr:  ErrUnsupportedHash,
+		},
+		{
+			name:     "unknown algorithm",
+			hash:     strings.Replace(current, "argon2id", "argon2i", 1),
+			password: "correct horse",
+			wantErr:  ErrUnsupportedHash,
+		},
+		{
+			name:     "unsupported version",
+			hash:     strings.Replace(current, "v=19", "v=16", 1),
+			password: "correct horse",
+			wantErr:  ErrUnsupportedHash,
+		},
+		{
+			name:     "malformed params",
+			hash:     strings.Replace(current, "m=1024,t=1,p=1", "m=x,t=1,p=1", 1),
+			password: "correct horse",
+			wantErr:  ErrUnsupportedHash,
+		},
+		{
+			name:     "zero iterations",
+			hash:     strings.Replace(current, "t=1", "t=0", 1),
+			password: "correct horse",
+			wantErr:  ErrUnsupportedHash,
+		},
+		{
+			name:     "zero parallelism",
+			hash:     strings.Replace(current, "p=1", "p=0", 1),
+			password: "correct horse",
+			wantErr:  ErrUnsupportedHash,
+		},
+		{
+			name:     "excessive memory",
+			hash:     strings.Replace(current, "m=1024", "m=4294967295", 1),
+			password: "correct horse",
+			wantErr:  ErrUnsupportedHash,
+		},
+		{
+			name:     "invalid salt encoding",
+			hash:     strings.Join([]string{parts[0], parts[1], parts[2], parts[3], "!!!", parts[5]}, "$"),
+			password: "correct horse",
+			wantErr:  ErrUnsupportedHash,
+		},
+		{
+			name:     "empty key",
+			hash:     strings.Join([]string{parts[0], parts[1], parts[2], parts[3], parts[4], ""}, "$"),
+			password: "correct horse",
+			wantErr:  ErrUnsupportedHash,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			match, rehash, err := hasher.Verify(tt.hash, tt.password)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Verify() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Verify() unexpected error: %v", err)
+			}
+			if match != tt.wantMatch || rehash != tt.wantRehash {
+				t.Fatalf("Verify() = (%v, %v), want (%v, %v)", match, rehash, tt.wantMatch, tt.wantRehash)
+			}
+		})
+	}
+}
+
+func TestHashUsesFreshSalt(t *testing.T) {
+	hasher := NewArgon2idHasher(testParams)
+
+	first, err := hasher.Hash("correct horse")
+	if err != nil {
+		t.Fatalf("Hash() unexpected error: %v", err)
+	}
+
+	second, err := hasher.Hash("correct horse")
+	if err != nil {
+		t.Fatalf("Hash() unexpected error: %v", err)
+	}
+
+	if first == second {
+		t.Fatalf("Hash() returned the same hash twice: %s", first)
+	}
+}
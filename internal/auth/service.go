@@ -2,33 +2,103 @@ package auth
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"strings"
+	"time"
 
+	"github.com/EduardoMark/gobid/internal/audit"
+	"github.com/EduardoMark/gobid/internal/auth/oidc"
+	"github.com/EduardoMark/gobid/internal/auth/password"
+	"github.com/EduardoMark/gobid/internal/lockout"
+	"github.com/EduardoMark/gobid/internal/mailer"
 	"github.com/EduardoMark/gobid/internal/store/pgstore"
+	"github.com/EduardoMark/gobid/internal/suspension"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/pquerna/otp"
+	"github.com/pquerna/otp/totp"
 	"github.com/sirupsen/logrus"
-	"golang.org/x/crypto/bcrypt"
+	"golang.org/x/oauth2"
 )
 
 type Service interface {
 	Create(ctx context.Context, username, email, password, bio string) (uuid.UUID, error)
-	AuthLogin(ctx context.Context, email, password string) (uuid.UUID, error)
+	AuthLogin(ctx context.Context, email, password, ip string) (uuid.UUID, error)
+	UnlockAccount(ctx context.Context, userID, adminID uuid.UUID) error
 	ChangePassword(ctx context.Context, id uuid.UUID, currentPassword, newPassword string) error
+	StartSession(ctx context.Context, userID uuid.UUID, userAgent, ip string) (uuid.UUID, string, error)
+	RotateRefreshToken(ctx context.Context, refreshToken string) (uuid.UUID, uuid.UUID, string, error)
+	RevokeRefreshToken(ctx context.Context, userID uuid.UUID, refreshToken string) error
+	ListSessions(ctx context.Context, userID uuid.UUID) ([]*pgstore.Session, error)
+	RevokeSession(ctx context.Context, userID, sessionID uuid.UUID) error
+	RevokeAllSessions(ctx context.Context, userID uuid.UUID) error
+	SendEmailVerification(ctx context.Context, userID uuid.UUID) error
+	VerifyEmail(ctx context.Context, token string) error
+	ForgotPassword(ctx context.Context, email, ip string) error
+	ResetPassword(ctx context.Context, token, newPassword string) error
+	TwoFactorEnabled(ctx context.Context, userID uuid.UUID) (bool, error)
+	EnrollTwoFactor(ctx context.Context, userID uuid.UUID) (string, string, error)
+	ConfirmTwoFactor(ctx context.Context, userID uuid.UUID, code string) ([]string, error)
+	DisableTwoFactor(ctx context.Context, userID uuid.UUID, password string) error
+	VerifyTwoFactor(ctx context.Context, userID uuid.UUID, code string) error
+	OIDCProviders() []string
+	BeginOIDCLogin(ctx context.Context, provider string) (string, error)
+	CompleteOIDCLogin(ctx context.Context, provider, state, code string) (uuid.UUID, error)
+	ForcePasswordReset(ctx context.Context, userID uuid.UUID) error
 }
 
+const refreshTokenTTL = time.Hour * 24 * 30
+const emailVerificationTTL = time.Hour * 24
+const passwordResetTTL = time.Hour
+const passwordResetJobTimeout = time.Second * 30
+const maxPasswordResetJobs = 8
+const oidcStateTTL = time.Minute * 10
+
+const purposeEmailVerification = "email_verification"
+const purposePasswordReset = "password_reset"
+
+const totpIssuer = "gobid"
+const totpPeriod = 30
+const recoveryCodeCount = 10
+
 type AuthService struct {
-	pool    *pgxpool.Pool
-	queries *pgstore.Queries
+	pool      *pgxpool.Pool
+	queries   *pgstore.Queries
+	mailer    mailer.Mailer
+	guard     lockout.Guard
+	hasher    password.Hasher
+	dummyHash string
+	oidc      oidc.Client
+	audit     audit.Recorder
+	resets    chan struct{}
 }
 
-func NewAuthService(pool *pgxpool.Pool) AuthService {
+func NewAuthService(pool *pgxpool.Pool, mailer mailer.Mailer, identities oidc.Client, recorder audit.Recorder) AuthService {
+	hasher := password.NewHasher()
+
+	dummyHash, err := hasher.Hash(uuid.NewString())
+	if err != nil {
+		logrus.WithField("err", err.Error()).Error("NewAuthService.dummyHash")
+	}
+
 	return AuthService{
-		pool:    pool,
-		queries: pgstore.New(pool),
+		pool:      pool,
+		queries:   pgstore.New(pool),
+		mailer:    mailer,
+		guard:     lockout.NewGuard(pool),
+		hasher:    hasher,
+		dummyHash: dummyHash,
+		oidc:      identities,
+		audit:     recorder,
+		resets:    make(chan struct{}, maxPasswordResetJobs),
 	}
 }
 
@@ -36,9 +106,25 @@ var ErrEmailAlreadyExist = errors.New("email already exists")
 var ErrInvalidCredentials = errors.New("invalid credentials")
 var ErrSamePassword = errors.New("same password")
 var ErrNotFound = errors.New("not found")
+var ErrInvalidRefreshToken = errors.New("invalid refresh token")
+var ErrRefreshTokenReused = errors.New("refresh token reused")
+var ErrSessionNotFound = errors.New("session not found")
+var ErrInvalidVerificationToken = errors.New("invalid verification token")
+var ErrAlreadyVerified = errors.New("email already verified")
+var ErrInvalidResetToken = errors.New("invalid reset token")
+var ErrTwoFactorEnabled = errors.New("two-factor authentication already enabled")
+var ErrTwoFactorNotEnrolled = errors.New("two-factor authentication not enrolled")
+var ErrInvalidTwoFactorCode = errors.New("invalid two-factor code")
+var ErrUnknownProvider = errors.New("unknown identity provider")
+var ErrInvalidOIDCState = errors.New("invalid or expired login state")
+var ErrOIDCLoginFailed = errors.New("identity provider login failed")
+var ErrOIDCEmailNotVerified = errors.New("identity provider email not verified")
+var ErrOIDCAccountConflict = errors.New("email belongs to an unverified account")
+var ErrPasswordResetRequired = errors.New("password reset required")
+var ErrPasswordResetBusy = errors.New("too many pending password resets")
 
 func (s AuthService) Create(ctx context.Context, username, email, password, bio string) (uuid.UUID, error) {
-	passwordHash, err := bcrypt.GenerateFromPassword([]byte(password), 12)
+	passwordHash, err := s.hasher.Hash(password)
 	if err != nil {
 		return uuid.UUID{}, fmt.Errorf("create: %v", err)
 	}
@@ -46,11 +132,19 @@ func (s AuthService) Create(ctx context.Context, username, email, password, bio
 	args := pgstore.CreateUserParams{
 		Username:     username,
 		Email:        email,
-		PasswordHash: string(passwordHash),
+		PasswordHash: passwordHash,
 		Bio:          bio,
 	}
 
-	id, err := s.queries.CreateUser(ctx, args)
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return uuid.UUID{}, fmt.Errorf("create: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.queries.WithTx(tx)
+
+	id, err := qtx.CreateUser(ctx, args)
 	if err != nil {
 		var pgErr *pgconn.PgError
 		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
@@ -64,14 +158,29 @@ func (s AuthService) Create(ctx context.Context, username, email, password, bio
 		return uuid.UUID{}, fmt.Errorf("create: %v", err)
 	}
 
+	if err := qtx.AssignDefaultRoles(ctx, id); err != nil {
+		return uuid.UUID{}, fmt.Errorf("create: %v", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return uuid.UUID{}, fmt.Errorf("create: %v", err)
+	}
+
 	return id, nil
 }
 
-func (s AuthService) AuthLogin(ctx context.Context, email, password string) (uuid.UUID, error) {
+func (s AuthService) AuthLogin(ctx context.Context, email, password, ip string) (uuid.UUID, error) {
+	if err := s.guard.Check(ctx, lockout.Account(email, uuid.Nil), lockout.IP(ip)); err != nil {
+		s.recordLogin(ctx, audit.ActionLoginFailed, uuid.Nil, "locked out")
+		return uuid.UUID{}, err
+	}
+
 	record, err := s.queries.GetUserByEmail(ctx, email)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return uuid.UUID{}, ErrInvalidCredentials
+			s.hasher.Verify(s.dummyHash, password)
+			s.recordLogin(ctx, audit.ActionLoginFailed, uuid.Nil, "unknown email")
+			return uuid.UUID{}, s.loginFailed(ctx, lockout.Account(email, uuid.Nil), lockout.IP(ip))
 		}
 
 		logrus.WithField(
@@ -81,14 +190,116 @@ func (s AuthService) AuthLogin(ctx context.Context, email, password string) (uui
 		return uuid.UUID{}, fmt.Errorf("auth login: %v", err)
 	}
 
-	isValidPassword := bcrypt.CompareHashAndPassword([]byte(record.PasswordHash), []byte(password))
-	if isValidPassword != nil {
-		return uuid.UUID{}, ErrInvalidCredentials
+	passwordHash := record.PasswordHash
+	if passwordHash == "" {
+		passwordHash = s.dummyHash
+	}
+
+	isValidPassword, needsRehash, err := s.hasher.Verify(passwordHash, password)
+	if err != nil {
+		return uuid.UUID{}, fmt.Errorf("auth login: %v", err)
+	}
+
+	if !isValidPassword {
+		s.recordLogin(ctx, audit.ActionLoginFailed, record.ID, "invalid password")
+		return uuid.UUID{}, s.loginFailed(ctx, lockout.Account(email, record.ID), lockout.IP(ip))
+	}
+
+	if err := accountStatus(record); err != nil {
+		s.recordLogin(ctx, audit.ActionLoginFailed, record.ID, err.Error())
+		return uuid.UUID{}, err
+	}
+
+	if needsRehash {
+		s.rehashPassword(ctx, record.ID, password)
+	}
+
+	if err := s.guard.Reset(ctx, lockout.Account(email, record.ID)); err != nil {
+		return uuid.UUID{}, fmt.Errorf("auth login: %v", err)
 	}
 
+	s.recordLogin(ctx, audit.ActionLogin, record.ID, "")
+
 	return record.ID, nil
 }
 
+func (s AuthService) recordLogin(ctx context.Context, action string, userID uuid.UUID, reason string) {
+	err := s.audit.Record(ctx, audit.Event{
+		ActorID:    userID,
+		Action:     action,
+		TargetType: audit.TargetUser,
+		TargetID:   userID,
+		Reason:     reason,
+	})
+	if err != nil {
+		logrus.WithField("err", err.Error()).Error("AuthService.recordLogin")
+	}
+}
+
+func accountStatus(record *pgstore.User) error {
+	if err := suspension.Check(record, time.Now()); err != nil {
+		return err
+	}
+
+	if record.PasswordResetRequired {
+		return ErrPasswordResetRequired
+	}
+
+	return nil
+}
+
+func (s AuthService) rehashPassword(ctx context.Context, id uuid.UUID, plain string) {
+	passwordHash, err := s.hasher.Hash(plain)
+	if err == nil {
+		err = s.queries.ChangePassword(ctx, pgstore.ChangePasswordParams{
+			ID:           id,
+			PasswordHash: passwordHash,
+		})
+	}
+
+	if err != nil {
+		logrus.WithField("err", err.Error()).Error("AuthLogin.rehash")
+	}
+}
+
+func (s AuthService) UnlockAccount(ctx context.Context, userID, adminID uuid.UUID) error {
+	record, err := s.queries.GetUserByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrNotFound
+		}
+		return fmt.Errorf("service.unlockAccount: %v", err)
+	}
+
+	err = s.guard.Unlock(ctx, userID, adminID, lockout.Account(record.Email, userID), lockout.TwoFactor(userID))
+	if err != nil {
+		return fmt.Errorf("service.unlockAccount: %v", err)
+	}
+
+	err = s.audit.Record(ctx, audit.Event{
+		ActorID:    adminID,
+		Action:     audit.ActionUnlockUser,
+		TargetType: audit.TargetUser,
+		TargetID:   userID,
+	})
+	if err != nil {
+		return fmt.Errorf("service.unlockAccount: %v", err)
+	}
+
+	return nil
+}
+
+func (s AuthService) loginFailed(ctx context.Context, attempts ...lockout.Attempt) error {
+	if err := s.guard.Fail(ctx, attempts...); err != nil {
+		if errors.Is(err, lockout.ErrLocked) {
+			return err
+		}
+		return fmt.Errorf("auth login: %v", err)
+	}
+
+	return ErrInvalidCredentials
+}
+
 func (s AuthService) ChangePassword(ctx context.Context, id uuid.UUID, currentPassword, newPassword string) error {
 	record, err := s.queries.GetUserByID(ctx, id)
 	if err != nil {
@@ -98,8 +309,12 @@ func (s AuthService) ChangePassword(ctx context.Context, id uuid.UUID, currentPa
 		return fmt.Errorf("service.changePassword: %v", err)
 	}
 
-	isMatched := bcrypt.CompareHashAndPassword([]byte(record.PasswordHash), []byte(currentPassword))
-	if isMatched != nil {
+	isMatched, _, err := s.hasher.Verify(record.PasswordHash, currentPassword)
+	if err != nil {
+		return fmt.Errorf("service.changePassword: %v", err)
+	}
+
+	if !isMatched {
 		return ErrInvalidCredentials
 	}
 
@@ -107,18 +322,1069 @@ func (s AuthService) ChangePassword(ctx context.Context, id uuid.UUID, currentPa
 		return ErrSamePassword
 	}
 
-	newPasswordHash, err := bcrypt.GenerateFromPassword([]byte(newPassword), 12)
+	newPasswordHash, err := s.hasher.Hash(newPassword)
+	if err != nil {
+		return fmt.Errorf("service.changePassword: %v", err)
+	}
+
+	tx, err := s.pool.Begin(ctx)
 	if err != nil {
 		return fmt.Errorf("service.changePassword: %v", err)
 	}
+	defer tx.Rollback(ctx)
 
-	err = s.queries.ChangePassword(ctx, pgstore.ChangePasswordParams{
+	err = s.queries.WithTx(tx).ChangePassword(ctx, pgstore.ChangePasswordParams{
 		ID:           id,
-		PasswordHash: string(newPasswordHash),
+		PasswordHash: newPasswordHash,
+	})
+	if err != nil {
+		return fmt.Errorf("service.changePassword: %v", err)
+	}
+
+	err = s.audit.WithTx(tx).Record(ctx, audit.Event{
+		ActorID:    id,
+		Action:     audit.ActionPasswordChange,
+		TargetType: audit.TargetUser,
+		TargetID:   id,
 	})
 	if err != nil {
 		return fmt.Errorf("service.changePassword: %v", err)
 	}
 
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("service.changePassword: %v", err)
+	}
+
+	return nil
+}
+
+func (s AuthService) StartSession(ctx context.Context, userID uuid.UUID, userAgent, ip string) (uuid.UUID, string, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return uuid.UUID{}, "", fmt.Errorf("service.startSession: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.queries.WithTx(tx)
+
+	sessionID, err := qtx.CreateSession(ctx, pgstore.CreateSessionParams{
+		UserID:    userID,
+		UserAgent: truncate(userAgent, 512),
+		Ip:        ip,
+	})
+	if err != nil {
+		return uuid.UUID{}, "", fmt.Errorf("service.startSession: %v", err)
+	}
+
+	refreshToken, err := s.createRefreshToken(ctx, qtx, userID, sessionID)
+	if err != nil {
+		return uuid.UUID{}, "", fmt.Errorf("service.startSession: %v", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return uuid.UUID{}, "", fmt.Errorf("service.startSession: %v", err)
+	}
+
+	return sessionID, refreshToken, nil
+}
+
+func (s AuthService) RotateRefreshToken(ctx context.Context, refreshToken string) (uuid.UUID, uuid.UUID, string, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return uuid.UUID{}, uuid.UUID{}, "", fmt.Errorf("service.rotateRefreshToken: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.queries.WithTx(tx)
+
+	record, err := qtx.GetRefreshTokenByHash(ctx, hashToken(refreshToken))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return uuid.UUID{}, uuid.UUID{}, "", ErrInvalidRefreshToken
+		}
+		return uuid.UUID{}, uuid.UUID{}, "", fmt.Errorf("service.rotateRefreshToken: %v", err)
+	}
+
+	if record.UsedAt.Valid || record.RevokedAt.Valid {
+		if err := s.revokeSession(ctx, qtx, record.UserID, record.FamilyID); err != nil {
+			return uuid.UUID{}, uuid.UUID{}, "", fmt.Errorf("service.rotateRefreshToken: %v", err)
+		}
+
+		if err := tx.Commit(ctx); err != nil {
+			return uuid.UUID{}, uuid.UUID{}, "", fmt.Errorf("service.rotateRefreshToken: %v", err)
+		}
+
+		logrus.WithField("family_id", record.FamilyID.String()).Warn("refresh token reuse detected")
+
+		return uuid.UUID{}, uuid.UUID{}, "", ErrRefreshTokenReused
+	}
+
+	if time.Now().After(record.ExpiresAt) {
+		return uuid.UUID{}, uuid.UUID{}, "", ErrInvalidRefreshToken
+	}
+
+	rows, err := qtx.MarkRefreshTokenUsed(ctx, record.ID)
+	if err != nil {
+		return uuid.UUID{}, uuid.UUID{}, "", fmt.Errorf("service.rotateRefreshToken: %v", err)
+	}
+
+	if rows == 0 {
+		return uuid.UUID{}, uuid.UUID{}, "", ErrRefreshTokenReused
+	}
+
+	next, err := s.createRefreshToken(ctx, qtx, record.UserID, record.FamilyID)
+	if err != nil {
+		return uuid.UUID{}, uuid.UUID{}, "", fmt.Errorf("service.rotateRefreshToken: %v", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return uuid.UUID{}, uuid.UUID{}, "", fmt.Errorf("service.rotateRefreshToken: %v", err)
+	}
+
+	return record.UserID, record.FamilyID, next, nil
+}
+
+func (s AuthService) RevokeRefreshToken(ctx context.Context, userID uuid.UUID, refreshToken string) error {
+	record, err := s.queries.GetRefreshTokenByHash(ctx, hashToken(refreshToken))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrInvalidRefreshToken
+		}
+		return fmt.Errorf("service.revokeRefreshToken: %v", err)
+	}
+
+	if record.UserID != userID {
+		return ErrInvalidRefreshToken
+	}
+
+	if err := s.revokeSession(ctx, s.queries, userID, record.FamilyID); err != nil {
+		return fmt.Errorf("service.revokeRefreshToken: %v", err)
+	}
+
+	return nil
+}
+
+func (s AuthService) ListSessions(ctx context.Context, userID uuid.UUID) ([]*pgstore.Session, error) {
+	records, err := s.queries.ListActiveSessionsByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("service.listSessions: %v", err)
+	}
+
+	return records, nil
+}
+
+func (s AuthService) RevokeSession(ctx context.Context, userID, sessionID uuid.UUID) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("service.revokeSession: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.queries.WithTx(tx)
+
+	rows, err := qtx.RevokeSession(ctx, pgstore.RevokeSessionParams{
+		ID:     sessionID,
+		UserID: userID,
+	})
+	if err != nil {
+		return fmt.Errorf("service.revokeSession: %v", err)
+	}
+
+	if rows == 0 {
+		return ErrSessionNotFound
+	}
+
+	if err := qtx.RevokeRefreshTokenFamily(ctx, sessionID); err != nil {
+		return fmt.Errorf("service.revokeSession: %v", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("service.revokeSession: %v", err)
+	}
+
+	return nil
+}
+
+func (s AuthService) RevokeAllSessions(ctx context.Context, userID uuid.UUID) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("service.revokeAllSessions: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := s.revokeAllSessions(ctx, s.queries.WithTx(tx), userID); err != nil {
+		return fmt.Errorf("service.revokeAllSessions: %v", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("service.revokeAllSessions: %v", err)
+	}
+
+	return nil
+}
+
+func (s AuthService) revokeSession(ctx context.Context, q *pgstore.Queries, userID, sessionID uuid.UUID) error {
+	_, err := q.RevokeSession(ctx, pgstore.RevokeSessionParams{
+		ID:     sessionID,
+		UserID: userID,
+	})
+	if err != nil {
+		return err
+	}
+
+	return q.RevokeRefreshTokenFamily(ctx, sessionID)
+}
+
+func (s AuthService) revokeAllSessions(ctx context.Context, q *pgstore.Queries, userID uuid.UUID) error {
+	if err := q.RevokeUserSessions(ctx, userID); err != nil {
+		return err
+	}
+
+	if err := q.RevokeUserRefreshTokens(ctx, userID); err != nil {
+		return err
+	}
+
+	return q.InvalidateUserAccessTokens(ctx, userID)
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return strings.ToValidUTF8(s[:n], "")
+}
+
+func (s AuthService) createRefreshToken(ctx context.Context, q *pgstore.Queries, userID, familyID uuid.UUID) (string, error) {
+	refreshToken, err := newOpaqueToken()
+	if err != nil {
+		return "", err
+	}
+
+	err = q.CreateRefreshToken(ctx, pgstore.CreateRefreshTokenParams{
+		UserID:    userID,
+		FamilyID:  familyID,
+		TokenHash: hashToken(refreshToken),
+		ExpiresAt: time.Now().Add(refreshTokenTTL),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return refreshToken, nil
+}
+
+func (s AuthService) SendEmailVerification(ctx context.Context, userID uuid.UUID) error {
+	record, err := s.queries.GetUserByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrNotFound
+		}
+		return fmt.Errorf("service.sendEmailVerification: %v", err)
+	}
+
+	if record.EmailVerifiedAt.Valid {
+		return ErrAlreadyVerified
+	}
+
+	token, err := s.issueUserToken(ctx, userID, purposeEmailVerification, emailVerificationTTL)
+	if err != nil {
+		return fmt.Errorf("service.sendEmailVerification: %v", err)
+	}
+
+	err = s.mailer.Send(ctx, mailer.Message{
+		To:      record.Email,
+		Subject: "Verify your gobid email address",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nUse the token below to verify your email address. It expires in 24 hours.\n\n%s\n",
+			record.Username, token,
+		),
+	})
+	if err != nil {
+		return fmt.Errorf("service.sendEmailVerification: %v", err)
+	}
+
+	return nil
+}
+
+func (s AuthService) VerifyEmail(ctx context.Context, token string) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("service.verifyEmail: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.queries.WithTx(tx)
+
+	userID, err := s.consumeUserToken(ctx, qtx, token, purposeEmailVerification)
+	if err != nil {
+		if errors.Is(err, errInvalidUserToken) {
+			return ErrInvalidVerificationToken
+		}
+		return fmt.Errorf("service.verifyEmail: %v", err)
+	}
+
+	if err := qtx.MarkEmailVerified(ctx, userID); err != nil {
+		return fmt.Errorf("service.verifyEmail: %v", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("service.verifyEmail: %v", err)
+	}
+
+	return nil
+}
+
+func (s AuthService) ForgotPassword(ctx context.Context, email, ip string) error {
+	if err := s.guard.Check(ctx, lockout.IP(ip), lockout.PasswordReset(ip)); err != nil {
+		return err
+	}
+
+	if err := s.guard.Fail(ctx, lockout.PasswordReset(ip)); err != nil {
+		if errors.Is(err, lockout.ErrLocked) {
+			return err
+		}
+		return fmt.Errorf("service.forgotPassword: %v", err)
+	}
+
+	select {
+	case s.resets <- struct{}{}:
+	default:
+		return ErrPasswordResetBusy
+	}
+
+	go func() {
+		defer func() { <-s.resets }()
+
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), passwordResetJobTimeout)
+		defer cancel()
+
+		s.forgotPassword(ctx, email)
+	}()
+
 	return nil
 }
+
+func (s AuthService) forgotPassword(ctx context.Context, email string) {
+	record, err := s.queries.GetUserByEmail(ctx, email)
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			logrus.WithField("err", err.Error()).Error("AuthService.forgotPassword")
+		}
+		return
+	}
+
+	if err := s.sendPasswordReset(ctx, record, "If you did not request a password reset you can ignore this email."); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"err":     err.Error(),
+			"user_id": record.ID,
+		}).Error("AuthService.forgotPassword")
+	}
+}
+
+func (s AuthService) ForcePasswordReset(ctx context.Context, userID uuid.UUID) error {
+	record, err := s.queries.GetUserByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrNotFound
+		}
+		return fmt.Errorf("service.forcePasswordReset: %v", err)
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("service.forcePasswordReset: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.queries.WithTx(tx)
+
+	if err := qtx.RequirePasswordReset(ctx, userID); err != nil {
+		return fmt.Errorf("service.forcePasswordReset: %v", err)
+	}
+
+	if err := s.revokeAllSessions(ctx, qtx, userID); err != nil {
+		return fmt.Errorf("service.forcePasswordReset: %v", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("service.forcePasswordReset: %v", err)
+	}
+
+	if err := s.sendPasswordReset(ctx, record, "Our support team has required a password reset for your account, and you have been signed out everywhere."); err != nil {
+		return fmt.Errorf("service.forcePasswordReset: %v", err)
+	}
+
+	return nil
+}
+
+func (s AuthService) sendPasswordReset(ctx context.Context, record *pgstore.User, note string) error {
+	token, err := s.issueUserToken(ctx, record.ID, purposePasswordReset, passwordResetTTL)
+	if err != nil {
+		return err
+	}
+
+	return s.mailer.Send(ctx, mailer.Message{
+		To:      record.Email,
+		Subject: "Reset your gobid password",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nUse the token below to reset your password. It expires in 1 hour and can only be used once.\n\n%s\n\n%s\n",
+			record.Username, token, note,
+		),
+	})
+}
+
+func (s AuthService) ResetPassword(ctx context.Context, token, newPassword string) error {
+	passwordHash, err := s.hasher.Hash(newPassword)
+	if err != nil {
+		return fmt.Errorf("service.resetPassword: %v", err)
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("service.resetPassword: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.queries.WithTx(tx)
+
+	userID, err := s.consumeUserToken(ctx, qtx, token, purposePasswordReset)
+	if err != nil {
+		if errors.Is(err, errInvalidUserToken) {
+			return ErrInvalidResetToken
+		}
+		return fmt.Errorf("service.resetPassword: %v", err)
+	}
+
+	err = qtx.ChangePassword(ctx, pgstore.ChangePasswordParams{
+		ID:           userID,
+		PasswordHash: passwordHash,
+	})
+	if err != nil {
+		return fmt.Errorf("service.resetPassword: %v", err)
+	}
+
+	if err := qtx.MarkEmailVerified(ctx, userID); err != nil {
+		return fmt.Errorf("service.resetPassword: %v", err)
+	}
+
+	if err := s.revokeAllSessions(ctx, qtx, userID); err != nil {
+		return fmt.Errorf("service.resetPassword: %v", err)
+	}
+
+	err = s.audit.WithTx(tx).Record(ctx, audit.Event{
+		ActorID:    userID,
+		Action:     audit.ActionPasswordReset,
+		TargetType: audit.TargetUser,
+		TargetID:   userID,
+	})
+	if err != nil {
+		return fmt.Errorf("service.resetPassword: %v", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("service.resetPassword: %v", err)
+	}
+
+	return nil
+}
+
+func (s AuthService) TwoFactorEnabled(ctx context.Context, userID uuid.UUID) (bool, error) {
+	enabled, err := s.queries.IsTwoFactorEnabled(ctx, userID)
+	if err != nil {
+		return false, fmt.Errorf("service.twoFactorEnabled: %v", err)
+	}
+
+	return enabled, nil
+}
+
+func (s AuthService) EnrollTwoFactor(ctx context.Context, userID uuid.UUID) (string, string, error) {
+	record, err := s.queries.GetUserByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", "", ErrNotFound
+		}
+		return "", "", fmt.Errorf("service.enrollTwoFactor: %v", err)
+	}
+
+	enabled, err := s.queries.IsTwoFactorEnabled(ctx, userID)
+	if err != nil {
+		return "", "", fmt.Errorf("service.enrollTwoFactor: %v", err)
+	}
+
+	if enabled {
+		return "", "", ErrTwoFactorEnabled
+	}
+
+	key, err := totp.Generate(totp.GenerateOpts{
+		Issuer:      totpIssuer,
+		AccountName: record.Email,
+		Period:      totpPeriod,
+	})
+	if err != nil {
+		return "", "", fmt.Errorf("service.enrollTwoFactor: %v", err)
+	}
+
+	err = s.queries.UpsertPendingTOTP(ctx, pgstore.UpsertPendingTOTPParams{
+		UserID: userID,
+		Secret: key.Secret(),
+	})
+	if err != nil {
+		return "", "", fmt.Errorf("service.enrollTwoFactor: %v", err)
+	}
+
+	return key.Secret(), key.URL(), nil
+}
+
+func (s AuthService) ConfirmTwoFactor(ctx context.Context, userID uuid.UUID, code string) ([]string, error) {
+	record, err := s.queries.GetTOTPByUserID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrTwoFactorNotEnrolled
+		}
+		return nil, fmt.Errorf("service.confirmTwoFactor: %v", err)
+	}
+
+	if record.ConfirmedAt.Valid {
+		return nil, ErrTwoFactorEnabled
+	}
+
+	step, ok := matchTOTPStep(record.Secret, code, time.Now())
+	if !ok {
+		return nil, ErrInvalidTwoFactorCode
+	}
+
+	codes := make([]string, recoveryCodeCount)
+	for i := range codes {
+		code, err := newRecoveryCode()
+		if err != nil {
+			return nil, fmt.Errorf("service.confirmTwoFactor: %v", err)
+		}
+		codes[i] = code
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("service.confirmTwoFactor: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.queries.WithTx(tx)
+
+	rows, err := qtx.ConfirmTOTP(ctx, pgstore.ConfirmTOTPParams{
+		UserID:       userID,
+		LastUsedStep: step,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("service.confirmTwoFactor: %v", err)
+	}
+
+	if rows == 0 {
+		return nil, ErrTwoFactorEnabled
+	}
+
+	if err := qtx.DeleteRecoveryCodes(ctx, userID); err != nil {
+		return nil, fmt.Errorf("service.confirmTwoFactor: %v", err)
+	}
+
+	for _, code := range codes {
+		err := qtx.CreateRecoveryCode(ctx, pgstore.CreateRecoveryCodeParams{
+			UserID:   userID,
+			CodeHash: hashRecoveryCode(code),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("service.confirmTwoFactor: %v", err)
+		}
+	}
+
+	err = s.audit.WithTx(tx).Record(ctx, audit.Event{
+		ActorID:    userID,
+		Action:     audit.ActionTwoFactorEnable,
+		TargetType: audit.TargetUser,
+		TargetID:   userID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("service.confirmTwoFactor: %v", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("service.confirmTwoFactor: %v", err)
+	}
+
+	return codes, nil
+}
+
+func (s AuthService) DisableTwoFactor(ctx context.Context, userID uuid.UUID, password string) error {
+	record, err := s.queries.GetUserByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrNotFound
+		}
+		return fmt.Errorf("service.disableTwoFactor: %v", err)
+	}
+
+	isMatched, _, err := s.hasher.Verify(record.PasswordHash, password)
+	if err != nil {
+		return fmt.Errorf("service.disableTwoFactor: %v", err)
+	}
+
+	if !isMatched {
+		return ErrInvalidCredentials
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("service.disableTwoFactor: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.queries.WithTx(tx)
+
+	if err := qtx.DeleteTOTP(ctx, userID); err != nil {
+		return fmt.Errorf("service.disableTwoFactor: %v", err)
+	}
+
+	if err := qtx.DeleteRecoveryCodes(ctx, userID); err != nil {
+		return fmt.Errorf("service.disableTwoFactor: %v", err)
+	}
+
+	err = s.audit.WithTx(tx).Record(ctx, audit.Event{
+		ActorID:    userID,
+		Action:     audit.ActionTwoFactorDisable,
+		TargetType: audit.TargetUser,
+		TargetID:   userID,
+	})
+	if err != nil {
+		return fmt.Errorf("service.disableTwoFactor: %v", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("service.disableTwoFactor: %v", err)
+	}
+
+	return nil
+}
+
+func (s AuthService) VerifyTwoFactor(ctx context.Context, userID uuid.UUID, code string) error {
+	attempt := lockout.TwoFactor(userID)
+
+	if err := s.guard.Check(ctx, attempt); err != nil {
+		return err
+	}
+
+	err := s.verifyTwoFactorCode(ctx, userID, code)
+	if errors.Is(err, ErrInvalidTwoFactorCode) {
+		s.recordLogin(ctx, audit.ActionLoginFailed, userID, "invalid two-factor code")
+		if err := s.guard.Fail(ctx, attempt); err != nil {
+			if errors.Is(err, lockout.ErrLocked) {
+				return err
+			}
+			return fmt.Errorf("service.verifyTwoFactor: %v", err)
+		}
+		return ErrInvalidTwoFactorCode
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if err := s.guard.Reset(ctx, attempt); err != nil {
+		return fmt.Errorf("service.verifyTwoFactor: %v", err)
+	}
+
+	return nil
+}
+
+func (s AuthService) verifyTwoFactorCode(ctx context.Context, userID uuid.UUID, code string) error {
+	record, err := s.queries.GetTOTPByUserID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrTwoFactorNotEnrolled
+		}
+		return fmt.Errorf("service.verifyTwoFactor: %v", err)
+	}
+
+	if !record.ConfirmedAt.Valid {
+		return ErrTwoFactorNotEnrolled
+	}
+
+	if step, ok := matchTOTPStep(record.Secret, code, time.Now()); ok {
+		rows, err := s.queries.UseTOTPStep(ctx, pgstore.UseTOTPStepParams{
+			UserID:       userID,
+			LastUsedStep: step,
+		})
+		if err != nil {
+			return fmt.Errorf("service.verifyTwoFactor: %v", err)
+		}
+
+		if rows == 0 {
+			return ErrInvalidTwoFactorCode
+		}
+
+		return nil
+	}
+
+	rows, err := s.queries.UseRecoveryCode(ctx, pgstore.UseRecoveryCodeParams{
+		UserID:   userID,
+		CodeHash: hashRecoveryCode(code),
+	})
+	if err != nil {
+		return fmt.Errorf("service.verifyTwoFactor: %v", err)
+	}
+
+	if rows == 0 {
+		return ErrInvalidTwoFactorCode
+	}
+
+	return nil
+}
+
+func matchTOTPStep(secret, code string, now time.Time) (int64, bool) {
+	code = strings.TrimSpace(code)
+	if len(code) != int(otp.DigitsSix) {
+		return 0, false
+	}
+
+	for _, skew := range []int64{0, -1, 1} {
+		at := now.Add(time.Duration(skew*totpPeriod) * time.Second)
+
+		expected, err := totp.GenerateCodeCustom(secret, at, totp.ValidateOpts{
+			Period:    totpPeriod,
+			Digits:    otp.DigitsSix,
+			Algorithm: otp.AlgorithmSHA1,
+		})
+		if err != nil {
+			return 0, false
+		}
+
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(code)) == 1 {
+			return at.Unix() / totpPeriod, true
+		}
+	}
+
+	return 0, false
+}
+
+func newRecoveryCode() (string, error) {
+	buf := make([]byte, 5)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	code := hex.EncodeToString(buf)
+	return code[:5] + "-" + code[5:], nil
+}
+
+func hashRecoveryCode(code string) string {
+	normalized := strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+	return hashToken(normalized)
+}
+
+func (s AuthService) OIDCProviders() []string {
+	return s.oidc.Providers()
+}
+
+type oidcStateStore interface {
+	DeleteExpiredOIDCLoginStates(ctx context.Context) error
+	CreateOIDCLoginState(ctx context.Context, arg pgstore.CreateOIDCLoginStateParams) error
+	ConsumeOIDCLoginState(ctx context.Context, arg pgstore.ConsumeOIDCLoginStateParams) (*pgstore.OidcLoginState, error)
+}
+
+type identityStore interface {
+	GetUserIdentity(ctx context.Context, arg pgstore.GetUserIdentityParams) (*pgstore.UserIdentity, error)
+	TouchUserIdentity(ctx context.Context, arg pgstore.TouchUserIdentityParams) error
+	CreateUserIdentity(ctx context.Context, arg pgstore.CreateUserIdentityParams) error
+	GetUserByEmail(ctx context.Context, email string) (*pgstore.User, error)
+	CreateUser(ctx context.Context, arg pgstore.CreateUserParams) (uuid.UUID, error)
+	MarkEmailVerified(ctx context.Context, id uuid.UUID) error
+	AssignDefaultRoles(ctx context.Context, userID uuid.UUID) error
+	GetUserByID(ctx context.Context, id uuid.UUID) (*pgstore.User, error)
+}
+
+func (s AuthService) BeginOIDCLogin(ctx context.Context, provider string) (string, error) {
+	return s.beginOIDCLogin(ctx, s.queries, provider)
+}
+
+func (s AuthService) beginOIDCLogin(ctx context.Context, states oidcStateStore, provider string) (string, error) {
+	state, err := newOpaqueToken()
+	if err != nil {
+		return "", fmt.Errorf("service.beginOIDCLogin: %v", err)
+	}
+
+	nonce, err := newOpaqueToken()
+	if err != nil {
+		return "", fmt.Errorf("service.beginOIDCLogin: %v", err)
+	}
+
+	verifier := oauth2.GenerateVerifier()
+
+	authURL, err := s.oidc.AuthCodeURL(ctx, provider, state, nonce, verifier)
+	if err != nil {
+		if errors.Is(err, oidc.ErrUnknownProvider) {
+			return "", ErrUnknownProvider
+		}
+		return "", fmt.Errorf("service.beginOIDCLogin: %v", err)
+	}
+
+	if err := states.DeleteExpiredOIDCLoginStates(ctx); err != nil {
+		logrus.WithField("err", err.Error()).Error("BeginOIDCLogin.cleanup")
+	}
+
+	err = states.CreateOIDCLoginState(ctx, pgstore.CreateOIDCLoginStateParams{
+		StateHash:    hashToken(state),
+		Provider:     provider,
+		Nonce:        nonce,
+		CodeVerifier: verifier,
+		ExpiresAt:    time.Now().Add(oidcStateTTL),
+	})
+	if err != nil {
+		return "", fmt.Errorf("service.beginOIDCLogin: %v", err)
+	}
+
+	return authURL, nil
+}
+
+func (s AuthService) CompleteOIDCLogin(ctx context.Context, provider, state, code string) (uuid.UUID, error) {
+	identity, err := s.exchangeOIDCLogin(ctx, s.queries, provider, state, code)
+	if err != nil {
+		return uuid.UUID{}, err
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return uuid.UUID{}, fmt.Errorf("service.completeOIDCLogin: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.queries.WithTx(tx)
+
+	userID, err := s.signInIdentity(ctx, qtx, identity)
+	if err != nil {
+		return uuid.UUID{}, err
+	}
+
+	err = s.audit.WithTx(tx).Record(ctx, audit.Event{
+		ActorID:    userID,
+		Action:     audit.ActionOIDCLogin,
+		TargetType: audit.TargetUser,
+		TargetID:   userID,
+		After:      map[string]any{"provider": provider},
+	})
+	if err != nil {
+		return uuid.UUID{}, fmt.Errorf("service.completeOIDCLogin: %v", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return uuid.UUID{}, fmt.Errorf("service.completeOIDCLogin: %v", err)
+	}
+
+	return userID, nil
+}
+
+func (s AuthService) signInIdentity(ctx context.Context, q identityStore, identity *oidc.Identity) (uuid.UUID, error) {
+	userID, err := s.resolveIdentity(ctx, q, identity)
+	if err != nil {
+		return uuid.UUID{}, err
+	}
+
+	record, err := q.GetUserByID(ctx, userID)
+	if err != nil {
+		return uuid.UUID{}, fmt.Errorf("service.signInIdentity: %v", err)
+	}
+
+	if err := accountStatus(record); err != nil {
+		return uuid.UUID{}, err
+	}
+
+	return userID, nil
+}
+
+func (s AuthService) exchangeOIDCLogin(ctx context.Context, states oidcStateStore, provider, state, code string) (*oidc.Identity, error) {
+	loginState, err := states.ConsumeOIDCLoginState(ctx, pgstore.ConsumeOIDCLoginStateParams{
+		StateHash: hashToken(state),
+		Provider:  provider,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrInvalidOIDCState
+		}
+		return nil, fmt.Errorf("service.completeOIDCLogin: %v", err)
+	}
+
+	identity, err := s.oidc.Exchange(ctx, provider, code, loginState.Nonce, loginState.CodeVerifier)
+	if err != nil {
+		switch {
+		case errors.Is(err, oidc.ErrUnknownProvider):
+			return nil, ErrUnknownProvider
+		case errors.Is(err, oidc.ErrExchangeFailed), errors.Is(err, oidc.ErrInvalidIDToken):
+			logrus.WithField("err", err.Error()).Warn("CompleteOIDCLogin")
+			return nil, ErrOIDCLoginFailed
+		}
+		return nil, fmt.Errorf("service.completeOIDCLogin: %v", err)
+	}
+
+	return identity, nil
+}
+
+func (s AuthService) resolveIdentity(ctx context.Context, q identityStore, identity *oidc.Identity) (uuid.UUID, error) {
+	linked, err := q.GetUserIdentity(ctx, pgstore.GetUserIdentityParams{
+		Provider: identity.Provider,
+		Subject:  identity.Subject,
+	})
+	if err == nil {
+		err = q.TouchUserIdentity(ctx, pgstore.TouchUserIdentityParams{
+			ID:    linked.ID,
+			Email: identity.Email,
+		})
+		if err != nil {
+			return uuid.UUID{}, fmt.Errorf("service.resolveIdentity: %v", err)
+		}
+		return linked.UserID, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return uuid.UUID{}, fmt.Errorf("service.resolveIdentity: %v", err)
+	}
+
+	if identity.Email == "" || !identity.EmailVerified {
+		return uuid.UUID{}, ErrOIDCEmailNotVerified
+	}
+
+	var userID uuid.UUID
+
+	record, err := q.GetUserByEmail(ctx, identity.Email)
+	switch {
+	case err == nil:
+		if !record.EmailVerifiedAt.Valid {
+			return uuid.UUID{}, ErrOIDCAccountConflict
+		}
+		userID = record.ID
+	case errors.Is(err, pgx.ErrNoRows):
+		userID, err = q.CreateUser(ctx, pgstore.CreateUserParams{
+			Username: oidcUsername(identity),
+			Email:    identity.Email,
+			Bio:      "",
+		})
+		if err != nil {
+			return uuid.UUID{}, fmt.Errorf("service.resolveIdentity: %v", err)
+		}
+
+		if err := q.MarkEmailVerified(ctx, userID); err != nil {
+			return uuid.UUID{}, fmt.Errorf("service.resolveIdentity: %v", err)
+		}
+
+		if err := q.AssignDefaultRoles(ctx, userID); err != nil {
+			return uuid.UUID{}, fmt.Errorf("service.resolveIdentity: %v", err)
+		}
+	default:
+		return uuid.UUID{}, fmt.Errorf("service.resolveIdentity: %v", err)
+	}
+
+	err = q.CreateUserIdentity(ctx, pgstore.CreateUserIdentityParams{
+		UserID:   userID,
+		Provider: identity.Provider,
+		Subject:  identity.Subject,
+		Email:    identity.Email,
+	})
+	if err != nil {
+		return uuid.UUID{}, fmt.Errorf("service.resolveIdentity: %v", err)
+	}
+
+	return userID, nil
+}
+
+func oidcUsername(identity *oidc.Identity) string {
+	for _, candidate := range []string{identity.PreferredUsername, identity.Name} {
+		if candidate = strings.TrimSpace(candidate); len(candidate) >= 3 {
+			return truncate(candidate, 64)
+		}
+	}
+
+	local, _, _ := strings.Cut(identity.Email, "@")
+	if len(local) < 3 {
+		return "user-" + uuid.NewString()[:8]
+	}
+
+	return truncate(local, 64)
+}
+
+var errInvalidUserToken = errors.New("invalid user token")
+
+func (s AuthService) issueUserToken(ctx context.Context, userID uuid.UUID, purpose string, ttl time.Duration) (string, error) {
+	token, err := newOpaqueToken()
+	if err != nil {
+		return "", err
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.queries.WithTx(tx)
+
+	err = qtx.InvalidateUserTokens(ctx, pgstore.InvalidateUserTokensParams{
+		UserID:  userID,
+		Purpose: purpose,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	err = qtx.CreateUserToken(ctx, pgstore.CreateUserTokenParams{
+		UserID:    userID,
+		Purpose:   purpose,
+		TokenHash: hashToken(token),
+		ExpiresAt: time.Now().Add(ttl),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return "", err
+	}
+
+	return token, nil
+}
+
+func (s AuthService) consumeUserToken(ctx context.Context, q *pgstore.Queries, token, purpose string) (uuid.UUID, error) {
+	record, err := q.GetUserTokenByHash(ctx, pgstore.GetUserTokenByHashParams{
+		TokenHash: hashToken(token),
+		Purpose:   purpose,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return uuid.UUID{}, errInvalidUserToken
+		}
+		return uuid.UUID{}, err
+	}
+
+	if record.UsedAt.Valid || time.Now().After(record.ExpiresAt) {
+		return uuid.UUID{}, errInvalidUserToken
+	}
+
+	rows, err := q.MarkUserTokenUsed(ctx, record.ID)
+	if err != nil {
+		return uuid.UUID{}, err
+	}
+
+	if rows == 0 {
+		return uuid.UUID{}, errInvalidUserToken
+	}
+
+	return record.UserID, nil
+}
+
+func newOpaqueToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
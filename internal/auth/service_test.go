@@ -0,0 +1,74 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/EduardoMark/gobid/internal/lockout"
+	"github.com/google/uuid"
+)
+
+type fakeGuard struct {
+	locked   error
+	failures []lockout.Attempt
+}
+
+func (g *fakeGuard) Check(ctx context.Context, attempts ...lockout.Attempt) error {
+	return g.locked
+}
+
+func (g *fakeGuard) Fail(ctx context.Context, attempts ...lockout.Attempt) error {
+	g.failures = append(g.failures, attempts...)
+	return nil
+}
+
+func (g *fakeGuard) Reset(ctx context.Context, attempts ...lockout.Attempt) error {
+	return nil
+}
+
+func (g *fakeGuard) Unlock(ctx context.Context, userID, adminID uuid.UUID, attempts ...lockout.Attempt) error {
+	return nil
+}
+
+func TestForgotPasswordThrottle(t *testing.T) {
+	tests := []struct {
+		name      string
+		locked    error
+		wantErr   error
+		wantFails int
+	}{
+		{
+			name:    "locked out address is rejected",
+			locked:  &lockout.LockedError{RetryAfter: time.Minute},
+			wantErr: lockout.ErrLocked,
+		},
+		{
+			name:      "request is counted and refused while every worker is busy",
+			wantErr:   ErrPasswordResetBusy,
+			wantFails: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			guard := &fakeGuard{locked: tt.locked}
+			resets := make(chan struct{}, 1)
+			resets <- struct{}{}
+
+			s := AuthService{guard: guard, resets: resets}
+
+			err := s.ForgotPassword(context.Background(), "alice@example.com", "203.0.113.7")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ForgotPassword() error = %v, want %v", err, tt.wantErr)
+			}
+			if len(guard.failures) != tt.wantFails {
+				t.Fatalf("ForgotPassword() recorded %d attempts, want %d", len(guard.failures), tt.wantFails)
+			}
+			if tt.wantFails > 0 && guard.failures[0] != lockout.PasswordReset("203.0.113.7") {
+				t.Fatalf("ForgotPassword() recorded %+v, want the password reset scope", guard.failures[0])
+			}
+		})
+	}
+}
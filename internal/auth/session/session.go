@@ -0,0 +1,126 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/EduardoMark/gobid/internal/auth/token"
+	"github.com/EduardoMark/gobid/internal/store/pgstore"
+	"github.com/EduardoMark/gobid/internal/suspension"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var ErrUnknownSubject = errors.New("token subject does not exist")
+
+type Checker interface {
+	Revoke(ctx context.Context, claims *token.Claims) error
+	IsRevoked(ctx context.Context, claims *token.Claims) (bool, error)
+	Touch(ctx context.Context, claims *token.Claims) error
+	CheckAccount(ctx context.Context, claims *token.Claims) error
+	PurgeExpired(ctx context.Context) error
+}
+
+type checker struct {
+	q *pgstore.Queries
+}
+
+func NewChecker(pool *pgxpool.Pool) Checker {
+	return &checker{
+		q: pgstore.New(pool),
+	}
+}
+
+func (c *checker) Revoke(ctx context.Context, claims *token.Claims) error {
+	if claims.ID == "" || claims.ExpiresAt == nil {
+		return nil
+	}
+
+	err := c.q.RevokeToken(ctx, pgstore.RevokeTokenParams{
+		Jti:       claims.ID,
+		ExpiresAt: claims.ExpiresAt.Time,
+	})
+	if err != nil {
+		return fmt.Errorf("checker.revoke: %v", err)
+	}
+
+	return nil
+}
+
+func (c *checker) IsRevoked(ctx context.Context, claims *token.Claims) (bool, error) {
+	userID, err := uuid.Parse(claims.UserID)
+	if err != nil {
+		return true, nil
+	}
+
+	var issuedAt pgtype.Timestamptz
+	if claims.IssuedAt != nil {
+		issuedAt = pgtype.Timestamptz{Time: claims.IssuedAt.Time, Valid: true}
+	}
+
+	revoked, err := c.q.IsTokenRevoked(ctx, pgstore.IsTokenRevokedParams{
+		Jti:                 claims.ID,
+		ID:                  userID,
+		TokensInvalidBefore: issuedAt,
+		ID_2:                sessionID(claims),
+	})
+	if err != nil {
+		return false, fmt.Errorf("checker.isRevoked: %v", err)
+	}
+
+	return revoked, nil
+}
+
+func (c *checker) Touch(ctx context.Context, claims *token.Claims) error {
+	id := sessionID(claims)
+	if id == uuid.Nil {
+		return nil
+	}
+
+	if err := c.q.TouchSession(ctx, id); err != nil {
+		return fmt.Errorf("checker.touch: %v", err)
+	}
+
+	return nil
+}
+
+func (c *checker) CheckAccount(ctx context.Context, claims *token.Claims) error {
+	userID, err := uuid.Parse(claims.UserID)
+	if err != nil {
+		return ErrUnknownSubject
+	}
+
+	record, err := c.q.GetUserByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrUnknownSubject
+		}
+		return fmt.Errorf("checker.checkAccount: %v", err)
+	}
+
+	if record.DeletedAt.Valid {
+		return ErrUnknownSubject
+	}
+
+	return suspension.Check(record, time.Now())
+}
+
+func (c *checker) PurgeExpired(ctx context.Context) error {
+	if err := c.q.DeleteExpiredRevokedTokens(ctx); err != nil {
+		return fmt.Errorf("checker.purgeExpired: %v", err)
+	}
+
+	return nil
+}
+
+func sessionID(claims *token.Claims) uuid.UUID {
+	id, err := uuid.Parse(claims.SessionID)
+	if err != nil {
+		return uuid.Nil
+	}
+	return id
+}
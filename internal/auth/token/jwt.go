@@ -1,47 +1,148 @@
 package token
 
 import (
+	"errors"
 	"fmt"
+	"os"
+	"slices"
+	"strings"
 	"time"
 
-	"github.com/dgrijalva/jwt-go"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+const AccessTokenTTL = time.Minute * 15
+const ChallengeTokenTTL = time.Minute * 5
+
+var (
+	ErrExpired           = errors.New("token is expired")
+	ErrMalformed         = errors.New("token is malformed")
+	ErrBadSignature      = errors.New("token signature is invalid")
+	ErrNotValidYet       = errors.New("token is not valid yet")
+	ErrInvalidClaims     = errors.New("token has invalid claims")
+	ErrUnknownKey        = errors.New("token signed with unknown key")
+	ErrUnexpectedSigning = errors.New("unexpected signing method")
 )
 
 type JwtService interface {
-	GenerateToken(userId string) (string, error)
+	GenerateToken(userId, sessionId string, access Access) (string, error)
 	ValidateToken(encodedToken string) (*Claims, error)
+	GenerateChallengeToken(userId string) (string, error)
+	ValidateChallengeToken(encodedToken string) (*Claims, error)
+	JWKS() JWKS
+}
+
+type Config struct {
+	Keys     KeySet
+	Issuer   string
+	Audience string
+	Leeway   time.Duration
 }
 
 type jwtService struct {
-	secretkey string
-	issure    string
+	cfg Config
 }
 
-func NewJwtService() JwtService {
+func LoadConfig() (Config, error) {
+	keys, err := LoadKeySet()
+	if err != nil {
+		return Config{}, err
+	}
+
+	cfg := Config{
+		Keys:     keys,
+		Issuer:   "gobid",
+		Audience: "gobid-api",
+		Leeway:   time.Second * 30,
+	}
+
+	if issuer := os.Getenv("GOBID_JWT_ISSUER"); issuer != "" {
+		cfg.Issuer = issuer
+	}
+
+	if audience := os.Getenv("GOBID_JWT_AUDIENCE"); audience != "" {
+		cfg.Audience = audience
+	}
+
+	if raw := os.Getenv("GOBID_JWT_LEEWAY"); raw != "" {
+		leeway, err := time.ParseDuration(raw)
+		if err != nil || leeway < 0 {
+			return Config{}, fmt.Errorf("invalid GOBID_JWT_LEEWAY: %q", raw)
+		}
+		cfg.Leeway = leeway
+	}
+
+	return cfg, nil
+}
+
+func NewJwtService(cfg Config) JwtService {
 	return &jwtService{
-		secretkey: "secret-key",
-		issure:    "gobid",
+		cfg: cfg,
 	}
 }
 
+type Access struct {
+	Roles       []string
+	Permissions []string
+}
+
 type Claims struct {
-	UserID string `json:"user_id"`
-	jwt.StandardClaims
+	UserID      string   `json:"user_id"`
+	SessionID   string   `json:"sid,omitempty"`
+	Roles       []string `json:"roles,omitempty"`
+	Permissions []string `json:"perms,omitempty"`
+	jwt.RegisteredClaims
+}
+
+func (c *Claims) HasPermission(permission string) bool {
+	return slices.Contains(c.Permissions, permission)
+}
+
+func (s *jwtService) GenerateToken(userId, sessionId string, access Access) (string, error) {
+	return s.generate(userId, sessionId, access, s.cfg.Audience, AccessTokenTTL)
 }
 
-func (s *jwtService) GenerateToken(userId string) (string, error) {
+func (s *jwtService) ValidateToken(encodedToken string) (*Claims, error) {
+	return s.validate(encodedToken, s.cfg.Audience)
+}
+
+func (s *jwtService) GenerateChallengeToken(userId string) (string, error) {
+	return s.generate(userId, "", Access{}, s.challengeAudience(), ChallengeTokenTTL)
+}
+
+func (s *jwtService) ValidateChallengeToken(encodedToken string) (*Claims, error) {
+	return s.validate(encodedToken, s.challengeAudience())
+}
+
+func (s *jwtService) challengeAudience() string {
+	return s.cfg.Audience + "/2fa"
+}
+
+func (s *jwtService) generate(userId, sessionId string, access Access, audience string, ttl time.Duration) (string, error) {
+	now := time.Now()
+
 	claim := Claims{
-		UserID: userId,
-		StandardClaims: jwt.StandardClaims{
-			ExpiresAt: time.Now().Add(time.Hour * 2).Unix(),
-			Issuer:    s.issure,
-			IssuedAt:  time.Now().Unix(),
+		UserID:      userId,
+		SessionID:   sessionId,
+		Roles:       access.Roles,
+		Permissions: access.Permissions,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
+			Subject:   userId,
+			Issuer:    s.cfg.Issuer,
+			Audience:  jwt.ClaimStrings{audience},
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+			NotBefore: jwt.NewNumericDate(now),
+			IssuedAt:  jwt.NewNumericDate(now),
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claim)
+	key := s.cfg.Keys.Active
+	token := jwt.NewWithClaims(jwt.GetSigningMethod(key.Algorithm), claim)
+	token.Header["kid"] = key.ID
 
-	t, err := token.SignedString([]byte(s.secretkey))
+	t, err := token.SignedString(key.signingKey())
 	if err != nil {
 		return "", err
 	}
@@ -49,22 +150,59 @@ func (s *jwtService) GenerateToken(userId string) (string, error) {
 	return t, nil
 }
 
-func (s *jwtService) ValidateToken(encodedToken string) (*Claims, error) {
-	token, err := jwt.ParseWithClaims(encodedToken, &Claims{}, func(t *jwt.Token) (interface{}, error) {
-		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method")
-		}
-		return []byte(s.secretkey), nil
-	})
+func (s *jwtService) validate(encodedToken, audience string) (*Claims, error) {
+	claims := &Claims{}
 
-	if err != nil || !token.Valid {
-		return nil, err
+	_, err := jwt.ParseWithClaims(
+		encodedToken,
+		claims,
+		s.keyFunc,
+		jwt.WithValidMethods([]string{AlgHS256, AlgRS256, AlgEdDSA}),
+		jwt.WithIssuer(s.cfg.Issuer),
+		jwt.WithAudience(audience),
+		jwt.WithLeeway(s.cfg.Leeway),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return nil, classify(err)
 	}
 
-	claims, ok := token.Claims.(*Claims)
+	return claims, nil
+}
+
+func (s *jwtService) keyFunc(t *jwt.Token) (any, error) {
+	kid, _ := t.Header["kid"].(string)
+
+	key, ok := s.cfg.Keys.Lookup(kid, time.Now())
 	if !ok {
-		return nil, fmt.Errorf("invalid token claims")
+		return nil, ErrUnknownKey
 	}
 
-	return claims, nil
+	if t.Method.Alg() != key.Algorithm {
+		return nil, ErrUnexpectedSigning
+	}
+
+	return key.verificationKey(), nil
+}
+
+func classify(err error) error {
+	switch {
+	case errors.Is(err, jwt.ErrTokenExpired):
+		return ErrExpired
+	case errors.Is(err, jwt.ErrTokenMalformed):
+		return ErrMalformed
+	case errors.Is(err, jwt.ErrTokenSignatureInvalid),
+		errors.Is(err, ErrUnknownKey),
+		errors.Is(err, ErrUnexpectedSigning):
+		return ErrBadSignature
+	case errors.Is(err, jwt.ErrTokenNotValidYet):
+		return ErrNotValidYet
+	default:
+		detail := strings.TrimPrefix(err.Error(), jwt.ErrTokenInvalidClaims.Error()+": ")
+		return fmt.Errorf("%w: %s", ErrInvalidClaims, detail)
+	}
+}
+
+func (s *jwtService) JWKS() JWKS {
+	return s.cfg.Keys.JWKS(time.Now())
 }
@@ -0,0 +1,267 @@
+package token
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	testIssuer   = "gobid-test"
+	testAudience = "gobid-test-api"
+	testLeeway   = 30 * time.Second
+)
+
+func testService(t *testing.T) (*jwtService, *Key, *Key) {
+	t.Helper()
+
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	active := &Key{ID: "active", Algorithm: AlgHS256, Secret: []byte("active-secret-for-tests-only")}
+	previous := &Key{ID: "previous", Algorithm: AlgEdDSA, PrivateKey: edKey, ValidUntil: time.Now().Add(time.Hour)}
+
+	svc := &jwtService{cfg: Config{
+		Keys:     KeySet{Active: active, Previous: previous},
+		Issuer:   testIssuer,
+		Audience: testAudience,
+		Leeway:   testLeeway,
+	}}
+
+	return svc, active, previous
+}
+
+func sign(t *testing.T, method jwt.SigningMethod, kid string, key any, claims jwt.Claims) string {
+	t.Helper()
+
+	tok := jwt.NewWithClaims(method, claims)
+	if kid != "" {
+		tok.Header["kid"] = kid
+	}
+
+	signed, err := tok.SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return signed
+}
+
+func claimsAt(now time.Time, mutate func(*Claims)) *Claims {
+	c := &Claims{
+		UserID: "user-1",
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        "jti-1",
+			Subject:   "user-1",
+			Issuer:    testIssuer,
+			Audience:  jwt.ClaimStrings{testAudience},
+			ExpiresAt: jwt.NewNumericDate(now.Add(AccessTokenTTL)),
+			NotBefore: jwt.NewNumericDate(now),
+			IssuedAt:  jwt.NewNumericDate(now),
+		},
+	}
+	if mutate != nil {
+		mutate(c)
+	}
+	return c
+}
+
+func TestValidateToken(t *testing.T) {
+	svc, active, previous := testService(t)
+	now := time.Now()
+
+	_, strangerKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hs := func(mutate func(*Claims)) string {
+		return sign(t, jwt.SigningMethodHS256, active.ID, active.Secret, claimsAt(now, mutate))
+	}
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr error
+	}{
+		{
+			name:  "valid active key",
+			token: hs(nil),
+		},
+		{
+			name:  "valid previous key before rotation deadline",
+			token: sign(t, jwt.SigningMethodEdDSA, previous.ID, previous.PrivateKey, claimsAt(now, nil)),
+		},
+		{
+			name:    "wrong issuer",
+			token:   hs(func(c *Claims) { c.Issuer = "someone-else" }),
+			wantErr: ErrInvalidClaims,
+		},
+		{
+			name:    "wrong audience",
+			token:   hs(func(c *Claims) { c.Audience = jwt.ClaimStrings{"other-api"} }),
+			wantErr: ErrInvalidClaims,
+		},
+		{
+			name:    "challenge audience is not an access token",
+			token:   hs(func(c *Claims) { c.Audience = jwt.ClaimStrings{svc.challengeAudience()} }),
+			wantErr: ErrInvalidClaims,
+		},
+		{
+			name:    "missing expiry",
+			token:   hs(func(c *Claims) { c.ExpiresAt = nil }),
+			wantErr: ErrInvalidClaims,
+		},
+		{
+			name:    "expired beyond leeway",
+			token:   hs(func(c *Claims) { c.ExpiresAt = jwt.NewNumericDate(now.Add(-2 * testLeeway)) }),
+			wantErr: ErrExpired,
+		},
+		{
+			name:  "expired within leeway",
+			token: hs(func(c *Claims) { c.ExpiresAt = jwt.NewNumericDate(now.Add(-testLeeway / 2)) }),
+		},
+		{
+			name:    "not before in the future beyond leeway",
+			token:   hs(func(c *Claims) { c.NotBefore = jwt.NewNumericDate(now.Add(2 * testLeeway)) }),
+			wantErr: ErrNotValidYet,
+		},
+		{
+			name:  "not before in the future within leeway",
+			token: hs(func(c *Claims) { c.NotBefore = jwt.NewNumericDate(now.Add(testLeeway / 2)) }),
+		},
+		{
+			name:    "alg does not match the key id",
+			token:   sign(t, jwt.SigningMethodEdDSA, active.ID, previous.PrivateKey, claimsAt(now, nil)),
+			wantErr: ErrBadSignature,
+		},
+		{
+			name:    "alg none",
+			token:   sign(t, jwt.SigningMethodNone, active.ID, jwt.UnsafeAllowNoneSignatureType, claimsAt(now, nil)),
+			wantErr: ErrBadSignature,
+		},
+		{
+			name:    "unknown kid",
+			token:   sign(t, jwt.SigningMethodHS256, "retired", active.Secret, claimsAt(now, nil)),
+			wantErr: ErrBadSignature,
+		},
+		{
+			name:    "missing kid",
+			token:   sign(t, jwt.SigningMethodHS256, "", active.Secret, claimsAt(now, nil)),
+			wantErr: ErrBadSignature,
+		},
+		{
+			name:    "signed with the wrong secret",
+			token:   sign(t, jwt.SigningMethodHS256, active.ID, []byte("not-the-active-secret"), claimsAt(now, nil)),
+			wantErr: ErrBadSignature,
+		},
+		{
+			name:    "signed with a foreign key under a known kid",
+			token:   sign(t, jwt.SigningMethodEdDSA, previous.ID, strangerKey, claimsAt(now, nil)),
+			wantErr: ErrBadSignature,
+		},
+		{
+			name:    "malformed",
+			token:   "not.a.jwt",
+			wantErr: ErrMalformed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, err := svc.ValidateToken(tt.token)
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Fatalf("ValidateToken() unexpected error: %v", err)
+				}
+				if claims.UserID != "user-1" {
+					t.Fatalf("ValidateToken() user = %q, want %q", claims.UserID, "user-1")
+				}
+				return
+			}
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ValidateToken() error = %v, want %v", err, tt.wantErr)
+			}
+			if claims != nil {
+				t.Fatalf("ValidateToken() returned claims alongside error %v", err)
+			}
+		})
+	}
+}
+
+func TestValidateTokenRejectsRetiredPreviousKey(t *testing.T) {
+	svc, _, previous := testService(t)
+	token := sign(t, jwt.SigningMethodEdDSA, previous.ID, previous.PrivateKey, claimsAt(time.Now(), nil))
+
+	previous.ValidUntil = time.Now().Add(-time.Second)
+
+	if _, err := svc.ValidateToken(token); !errors.Is(err, ErrBadSignature) {
+		t.Fatalf("ValidateToken() error = %v, want %v", err, ErrBadSignature)
+	}
+}
+
+func TestGeneratedTokensRoundTrip(t *testing.T) {
+	svc, _, _ := testService(t)
+
+	access, err := svc.GenerateToken("user-1", "session-1", Access{Roles: []string{"admin"}, Permissions: []string{"users:read"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	claims, err := svc.ValidateToken(access)
+	if err != nil {
+		t.Fatalf("ValidateToken() unexpected error: %v", err)
+	}
+	if claims.SessionID != "session-1" || !claims.HasPermission("users:read") {
+		t.Fatalf("ValidateToken() claims = %+v", claims)
+	}
+
+	if _, err := svc.ValidateChallengeToken(access); !errors.Is(err, ErrInvalidClaims) {
+		t.Fatalf("ValidateChallengeToken(access) error = %v, want %v", err, ErrInvalidClaims)
+	}
+
+	challenge, err := svc.GenerateChallengeToken("user-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := svc.ValidateChallengeToken(challenge); err != nil {
+		t.Fatalf("ValidateChallengeToken() unexpected error: %v", err)
+	}
+
+	if _, err := svc.ValidateToken(challenge); !errors.Is(err, ErrInvalidClaims) {
+		t.Fatalf("ValidateToken(challenge) error = %v, want %v", err, ErrInvalidClaims)
+	}
+}
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{name: "expired", err: jwt.ErrTokenExpired, want: ErrExpired},
+		{name: "malformed", err: jwt.ErrTokenMalformed, want: ErrMalformed},
+		{name: "signature", err: jwt.ErrTokenSignatureInvalid, want: ErrBadSignature},
+		{name: "unknown key", err: ErrUnknownKey, want: ErrBadSignature},
+		{name: "unexpected signing", err: ErrUnexpectedSigning, want: ErrBadSignature},
+		{name: "not valid yet", err: jwt.ErrTokenNotValidYet, want: ErrNotValidYet},
+		{name: "invalid issuer", err: jwt.ErrTokenInvalidIssuer, want: ErrInvalidClaims},
+		{name: "invalid audience", err: jwt.ErrTokenInvalidAudience, want: ErrInvalidClaims},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := classify(tt.err); !errors.Is(got, tt.want) {
+				t.Fatalf("classify(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,235 @@
+package token
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"time"
+)
+
+const (
+	AlgHS256 = "HS256"
+	AlgRS256 = "RS256"
+	AlgEdDSA = "EdDSA"
+)
+
+type Key struct {
+	ID         string
+	Algorithm  string
+	Secret     []byte
+	PrivateKey crypto.Signer
+	ValidUntil time.Time
+}
+
+type KeySet struct {
+	Active   *Key
+	Previous *Key
+}
+
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+}
+
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+func LoadKeySet() (KeySet, error) {
+	active, err := loadKey("GOBID_JWT_")
+	if err != nil {
+		return KeySet{}, err
+	}
+
+	if active == nil {
+		return KeySet{}, errors.New("missing GOBID_JWT_KID and GOBID_JWT_KEY or GOBID_JWT_KEY_FILE")
+	}
+
+	previous, err := loadKey("GOBID_JWT_PREVIOUS_")
+	if err != nil {
+		return KeySet{}, err
+	}
+
+	if previous != nil {
+		raw := os.Getenv("GOBID_JWT_PREVIOUS_VALID_UNTIL")
+		validUntil, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return KeySet{}, fmt.Errorf("invalid GOBID_JWT_PREVIOUS_VALID_UNTIL: %q", raw)
+		}
+		previous.ValidUntil = validUntil
+
+		if previous.ID == active.ID {
+			return KeySet{}, fmt.Errorf("previous key id must differ from active key id %q", active.ID)
+		}
+	}
+
+	return KeySet{Active: active, Previous: previous}, nil
+}
+
+func (ks KeySet) Lookup(kid string, now time.Time) (*Key, bool) {
+	if ks.Active != nil && ks.Active.ID == kid {
+		return ks.Active, true
+	}
+
+	if ks.Previous != nil && ks.Previous.ID == kid && now.Before(ks.Previous.ValidUntil) {
+		return ks.Previous, true
+	}
+
+	return nil, false
+}
+
+func (ks KeySet) JWKS(now time.Time) JWKS {
+	set := JWKS{Keys: []JWK{}}
+
+	for _, key := range []*Key{ks.Active, ks.Previous} {
+		if key == nil || key.PrivateKey == nil {
+			continue
+		}
+
+		if key != ks.Active && !now.Before(key.ValidUntil) {
+			continue
+		}
+
+		if jwk, ok := key.jwk(); ok {
+			set.Keys = append(set.Keys, jwk)
+		}
+	}
+
+	return set
+}
+
+func (k *Key) signingKey() any {
+	if k.Algorithm == AlgHS256 {
+		return k.Secret
+	}
+	return k.PrivateKey
+}
+
+func (k *Key) verificationKey() any {
+	if k.Algorithm == AlgHS256 {
+		return k.Secret
+	}
+	return k.PrivateKey.Public()
+}
+
+func (k *Key) jwk() (JWK, bool) {
+	switch pub := k.PrivateKey.Public().(type) {
+	case *rsa.PublicKey:
+		return JWK{
+			Kty: "RSA",
+			Kid: k.ID,
+			Alg: k.Algorithm,
+			Use: "sig",
+			N:   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+		}, true
+	case ed25519.PublicKey:
+		return JWK{
+			Kty: "OKP",
+			Kid: k.ID,
+			Alg: k.Algorithm,
+			Use: "sig",
+			Crv: "Ed25519",
+			X:   base64.RawURLEncoding.EncodeToString(pub),
+		}, true
+	default:
+		return JWK{}, false
+	}
+}
+
+func loadKey(prefix string) (*Key, error) {
+	kid := os.Getenv(prefix + "KID")
+	secret := os.Getenv(prefix + "KEY")
+	file := os.Getenv(prefix + "KEY_FILE")
+
+	if kid == "" && secret == "" && file == "" {
+		return nil, nil
+	}
+
+	if kid == "" {
+		return nil, fmt.Errorf("missing %sKID", prefix)
+	}
+
+	alg := os.Getenv(prefix + "ALG")
+	if alg == "" {
+		alg = AlgHS256
+	}
+
+	key := &Key{ID: kid, Algorithm: alg}
+
+	switch alg {
+	case AlgHS256:
+		if len(secret) < 32 {
+			return nil, fmt.Errorf("%sKEY must be at least 32 bytes for %s", prefix, alg)
+		}
+		key.Secret = []byte(secret)
+	case AlgRS256, AlgEdDSA:
+		if file == "" {
+			return nil, fmt.Errorf("missing %sKEY_FILE for %s", prefix, alg)
+		}
+
+		signer, err := readPrivateKey(file)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %sKEY_FILE: %v", prefix, err)
+		}
+
+		switch signer.(type) {
+		case *rsa.PrivateKey:
+			if alg != AlgRS256 {
+				return nil, fmt.Errorf("%sKEY_FILE holds an RSA key but %sALG is %s", prefix, prefix, alg)
+			}
+		case ed25519.PrivateKey:
+			if alg != AlgEdDSA {
+				return nil, fmt.Errorf("%sKEY_FILE holds an Ed25519 key but %sALG is %s", prefix, prefix, alg)
+			}
+		}
+		key.PrivateKey = signer
+	default:
+		return nil, fmt.Errorf("unsupported %sALG: %q", prefix, alg)
+	}
+
+	return key, nil
+}
+
+func readPrivateKey(path string) (crypto.Signer, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	switch key := parsed.(type) {
+	case *rsa.PrivateKey:
+		return key, nil
+	case ed25519.PrivateKey:
+		return key, nil
+	default:
+		return nil, fmt.Errorf("unsupported private key type %T", parsed)
+	}
+}
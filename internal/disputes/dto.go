@@ -0,0 +1,96 @@
+package disputes
+
+import (
+	"context"
+	"time"
+
+	"github.com/EduardoMark/gobid/internal/money"
+	"github.com/EduardoMark/gobid/internal/validator"
+	"github.com/google/uuid"
+)
+
+type OpenDisputeReq struct {
+	OrderID  string `json:"order_id"`
+	Reason   string `json:"reason"`
+	Evidence string `json:"evidence"`
+}
+
+var validReasons = map[string]bool{
+	"not_as_described": true,
+	"not_received":     true,
+	"other":            true,
+}
+
+func (r *OpenDisputeReq) Valid(ctx context.Context) validator.Evaluator {
+	var eval validator.Evaluator
+
+	_, err := uuid.Parse(r.OrderID)
+	eval.CheckField(err == nil, "order_id", "this field must be a valid uuid")
+	eval.CheckField(validReasons[r.Reason], "reason", "must be one of not_as_described, not_received, other")
+	eval.CheckField(
+		validator.MinChars(r.Evidence, 10) && validator.MaxChars(r.Evidence, 2000),
+		"evidence", "this field must have a length between 10 and 2000",
+	)
+
+	return eval
+}
+
+type RespondReq struct {
+	Message string `json:"message"`
+}
+
+func (r *RespondReq) Valid(ctx context.Context) validator.Evaluator {
+	var eval validator.Evaluator
+
+	eval.CheckField(
+		validator.MinChars(r.Message, 10) && validator.MaxChars(r.Message, 2000),
+		"message", "this field must have a length between 10 and 2000",
+	)
+
+	return eval
+}
+
+type ResolveReq struct {
+	Resolution string       `json:"resolution"`
+	Amount     money.Amount `json:"amount"`
+	Note       string       `json:"note"`
+}
+
+func (r *ResolveReq) Valid(ctx context.Context) validator.Evaluator {
+	var eval validator.Evaluator
+
+	eval.CheckField(
+		r.Resolution == ResolutionFullRefund || r.Resolution == ResolutionPartialRefund || r.Resolution == ResolutionRejected,
+		"resolution", "must be one of full_refund, partial_refund, rejected",
+	)
+	if r.Resolution == ResolutionPartialRefund {
+		eval.CheckField(r.Amount > 0, "amount", "this field must be greater than 0")
+	}
+	eval.CheckField(validator.MaxChars(r.Note, 2000), "note", "this field must have at most 2000 characters")
+
+	return eval
+}
+
+type DisputeEventResponse struct {
+	ID        uuid.UUID `json:"id"`
+	ActorID   uuid.UUID `json:"actor_id"`
+	Kind      string    `json:"kind"`
+	Body      string    `json:"body"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type DisputeResponse struct {
+	ID           uuid.UUID              `json:"id"`
+	OrderID      uuid.UUID              `json:"order_id"`
+	BuyerID      uuid.UUID              `json:"buyer_id"`
+	SellerID     uuid.UUID              `json:"seller_id"`
+	Reason       string                 `json:"reason"`
+	Evidence     string                 `json:"evidence"`
+	Status       string                 `json:"status"`
+	Resolution   *string                `json:"resolution"`
+	RefundAmount money.Amount           `json:"refund_amount"`
+	ResolvedAt   *time.Time             `json:"resolved_at"`
+	CreatedAt    time.Time              `json:"created_at"`
+	UpdatedAt    time.Time              `json:"updated_at"`
+	Timeline     []DisputeEventResponse `json:"timeline,omitempty"`
+}
@@ -0,0 +1,327 @@
+package disputes
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/EduardoMark/gobid/internal/api/middlewares"
+	"github.com/EduardoMark/gobid/internal/auth/session"
+	"github.com/EduardoMark/gobid/internal/auth/token"
+	"github.com/EduardoMark/gobid/internal/jsonutils"
+	"github.com/EduardoMark/gobid/internal/rbac"
+	"github.com/EduardoMark/gobid/internal/store/pgstore"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+type DisputeHandler struct {
+	svc        Service
+	jwtService token.JwtService
+	sessions   session.Checker
+}
+
+func NewDisputeHandler(svc Service, jwtService token.JwtService, sessions session.Checker) DisputeHandler {
+	return DisputeHandler{
+		svc:        svc,
+		jwtService: jwtService,
+		sessions:   sessions,
+	}
+}
+
+func (m *DisputeHandler) RegisterDisputeRoutes(r chi.Router) {
+	r.Route("/disputes", func(r chi.Router) {
+		r.Group(func(r chi.Router) {
+			r.Use(middlewares.AuthToken(m.jwtService, m.sessions))
+
+			r.Post("/", m.Open)
+			r.Get("/{id}", m.GetOne)
+			r.Post("/{id}/response", m.Respond)
+
+			r.With(middlewares.RequirePermission(rbac.PermDisputesResolve)).Post("/{id}/resolve", m.Resolve)
+		})
+	})
+}
+
+func (m *DisputeHandler) Open(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, ok := ctx.Value(middlewares.UserIDKey).(string)
+	if !ok {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "user ID not found in context",
+		})
+		return
+	}
+
+	buyerID, err := uuid.Parse(id)
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "invalid user ID format",
+		})
+		return
+	}
+
+	data, problems, err := jsonutils.DecodeValidJson[*OpenDisputeReq](r)
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+
+	disputeID, err := m.svc.Open(ctx, buyerID, uuid.MustParse(data.OrderID), data.Reason, data.Evidence)
+	if err != nil {
+		if errors.Is(err, ErrOrderNotFound) {
+			jsonutils.EncodeJson(w, r, http.StatusNotFound, map[string]any{
+				"error": "order not found",
+			})
+			return
+		}
+
+		if errors.Is(err, ErrOrderNotPaid) {
+			jsonutils.EncodeJson(w, r, http.StatusConflict, map[string]any{
+				"error": "only paid orders can be disputed",
+			})
+			return
+		}
+
+		if errors.Is(err, ErrAlreadyExists) {
+			jsonutils.EncodeJson(w, r, http.StatusConflict, map[string]any{
+				"error": "a dispute already exists for this order",
+			})
+			return
+		}
+
+		logrus.WithField("err", err.Error()).Error("Handler.Open")
+
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "unexpected internal server error",
+		})
+		return
+	}
+
+	jsonutils.EncodeJson(w, r, http.StatusCreated, map[string]any{
+		"id": disputeID,
+	})
+}
+
+func (m *DisputeHandler) GetOne(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, ok := ctx.Value(middlewares.UserIDKey).(string)
+	if !ok {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "user ID not found in context",
+		})
+		return
+	}
+
+	parsedUserID, err := uuid.Parse(userID)
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "invalid user ID format",
+		})
+		return
+	}
+
+	parsedID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "invalid dispute ID format",
+		})
+		return
+	}
+
+	record, events, err := m.svc.GetDispute(ctx, parsedID)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			jsonutils.EncodeJson(w, r, http.StatusNotFound, map[string]any{
+				"error": "dispute not found",
+			})
+			return
+		}
+
+		logrus.WithField("err", err.Error()).Error("Handler.GetOne")
+
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "unexpected internal server error",
+		})
+		return
+	}
+
+	if record.BuyerID != parsedUserID && record.SellerID != parsedUserID &&
+		!middlewares.HasPermission(r, rbac.PermDisputesViewAny) {
+		jsonutils.EncodeJson(w, r, http.StatusNotFound, map[string]any{
+			"error": "dispute not found",
+		})
+		return
+	}
+
+	res := toDisputeResponse(record)
+	res.Timeline = make([]DisputeEventResponse, len(events))
+	for i, event := range events {
+		res.Timeline[i] = DisputeEventResponse{
+			ID:        event.ID,
+			ActorID:   event.ActorID,
+			Kind:      event.Kind,
+			Body:      event.Body,
+			CreatedAt: event.CreatedAt,
+		}
+	}
+
+	jsonutils.EncodeJson(w, r, http.StatusOK, map[string]any{
+		"dispute": res,
+	})
+}
+
+func (m *DisputeHandler) Respond(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, ok := ctx.Value(middlewares.UserIDKey).(string)
+	if !ok {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "user ID not found in context",
+		})
+		return
+	}
+
+	sellerID, err := uuid.Parse(id)
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "invalid user ID format",
+		})
+		return
+	}
+
+	parsedID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "invalid dispute ID format",
+		})
+		return
+	}
+
+	data, problems, err := jsonutils.DecodeValidJson[*RespondReq](r)
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+
+	if err := m.svc.Respond(ctx, parsedID, sellerID, data.Message); err != nil {
+		if errors.Is(err, ErrNotFound) || errors.Is(err, ErrForbidden) {
+			jsonutils.EncodeJson(w, r, http.StatusNotFound, map[string]any{
+				"error": "dispute not found",
+			})
+			return
+		}
+
+		if errors.Is(err, ErrAlreadyResolved) {
+			jsonutils.EncodeJson(w, r, http.StatusConflict, map[string]any{
+				"error": "dispute already resolved",
+			})
+			return
+		}
+
+		logrus.WithField("err", err.Error()).Error("Handler.Respond")
+
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "unexpected internal server error",
+		})
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (m *DisputeHandler) Resolve(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, ok := ctx.Value(middlewares.UserIDKey).(string)
+	if !ok {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "user ID not found in context",
+		})
+		return
+	}
+
+	adminID, err := uuid.Parse(id)
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "invalid user ID format",
+		})
+		return
+	}
+
+	parsedID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "invalid dispute ID format",
+		})
+		return
+	}
+
+	data, problems, err := jsonutils.DecodeValidJson[*ResolveReq](r)
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+
+	record, err := m.svc.Resolve(ctx, parsedID, adminID, data.Resolution, data.Amount, data.Note)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			jsonutils.EncodeJson(w, r, http.StatusNotFound, map[string]any{
+				"error": "dispute not found",
+			})
+			return
+		}
+
+		if errors.Is(err, ErrAlreadyResolved) {
+			jsonutils.EncodeJson(w, r, http.StatusConflict, map[string]any{
+				"error": "dispute already resolved",
+			})
+			return
+		}
+
+		if errors.Is(err, ErrInvalidRefundAmount) {
+			jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+				"error": "refund amount exceeds the refundable order amount",
+			})
+			return
+		}
+
+		logrus.WithField("err", err.Error()).Error("Handler.Resolve")
+
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "unexpected internal server error",
+		})
+		return
+	}
+
+	jsonutils.EncodeJson(w, r, http.StatusOK, map[string]any{
+		"dispute": toDisputeResponse(record),
+	})
+}
+
+func toDisputeResponse(record *pgstore.Dispute) DisputeResponse {
+	res := DisputeResponse{
+		ID:           record.ID,
+		OrderID:      record.OrderID,
+		BuyerID:      record.BuyerID,
+		SellerID:     record.SellerID,
+		Reason:       record.Reason,
+		Evidence:     record.Evidence,
+		Status:       record.Status,
+		RefundAmount: record.RefundAmount,
+		CreatedAt:    record.CreatedAt,
+		UpdatedAt:    record.UpdatedAt,
+	}
+
+	if record.Resolution.Valid {
+		res.Resolution = &record.Resolution.String
+	}
+
+	if record.ResolvedAt.Valid {
+		res.ResolvedAt = &record.ResolvedAt.Time
+	}
+
+	return res
+}
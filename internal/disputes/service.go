@@ -0,0 +1,412 @@
+package disputes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/EduardoMark/gobid/internal/audit"
+	"github.com/EduardoMark/gobid/internal/money"
+	"github.com/EduardoMark/gobid/internal/payments"
+	"github.com/EduardoMark/gobid/internal/store/pgstore"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/sirupsen/logrus"
+)
+
+type Service interface {
+	Open(ctx context.Context, buyerID, orderID uuid.UUID, reason, evidence string) (uuid.UUID, error)
+	GetDispute(ctx context.Context, id uuid.UUID) (*pgstore.Dispute, []*pgstore.DisputeEvent, error)
+	Respond(ctx context.Context, id, sellerID uuid.UUID, message string) error
+	Resolve(ctx context.Context, id, adminID uuid.UUID, resolution string, amount money.Amount, note string) (*pgstore.Dispute, error)
+	ReconcileRefunds(ctx context.Context) (int, error)
+}
+
+type disputeService struct {
+	pool     *pgxpool.Pool
+	q        *pgstore.Queries
+	payments payments.Provider
+	audit    audit.Recorder
+}
+
+var ErrNotFound = errors.New("not found")
+var ErrOrderNotFound = errors.New("order not found")
+var ErrOrderNotPaid = errors.New("order not paid")
+var ErrForbidden = errors.New("forbidden")
+var ErrAlreadyExists = errors.New("dispute already exists")
+var ErrAlreadyResolved = errors.New("dispute already resolved")
+var ErrInvalidRefundAmount = errors.New("invalid refund amount")
+
+const (
+	ResolutionFullRefund    = "full_refund"
+	ResolutionPartialRefund = "partial_refund"
+	ResolutionRejected      = "rejected"
+)
+
+func NewDisputeService(pool *pgxpool.Pool, provider payments.Provider, recorder audit.Recorder) Service {
+	return &disputeService{
+		pool:     pool,
+		q:        pgstore.New(pool),
+		payments: provider,
+		audit:    recorder,
+	}
+}
+
+func (s *disputeService) Open(ctx context.Context, buyerID, orderID uuid.UUID, reason, evidence string) (uuid.UUID, error) {
+	order, err := s.q.GetOrderByID(ctx, orderID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return uuid.UUID{}, ErrOrderNotFound
+		}
+		return uuid.UUID{}, fmt.Errorf("service.open: %v", err)
+	}
+
+	if order.BuyerID != buyerID {
+		return uuid.UUID{}, ErrOrderNotFound
+	}
+
+	if order.Status != "paid" {
+		return uuid.UUID{}, ErrOrderNotPaid
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return uuid.UUID{}, fmt.Errorf("service.open: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.q.WithTx(tx)
+
+	id, err := qtx.CreateDispute(ctx, pgstore.CreateDisputeParams{
+		OrderID:  order.ID,
+		BuyerID:  order.BuyerID,
+		SellerID: order.SellerID,
+		Reason:   reason,
+		Evidence: evidence,
+	})
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return uuid.UUID{}, ErrAlreadyExists
+		}
+		return uuid.UUID{}, fmt.Errorf("service.open: %v", err)
+	}
+
+	err = qtx.CreateDisputeEvent(ctx, pgstore.CreateDisputeEventParams{
+		DisputeID: id,
+		ActorID:   buyerID,
+		Kind:      "opened",
+		Body:      evidence,
+	})
+	if err != nil {
+		return uuid.UUID{}, fmt.Errorf("service.open: %v", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return uuid.UUID{}, fmt.Errorf("service.open: %v", err)
+	}
+
+	return id, nil
+}
+
+func (s *disputeService) GetDispute(ctx context.Context, id uuid.UUID) (*pgstore.Dispute, []*pgstore.DisputeEvent, error) {
+	record, err := s.q.GetDisputeByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil, ErrNotFound
+		}
+		return nil, nil, fmt.Errorf("service.getDispute: %v", err)
+	}
+
+	events, err := s.q.ListDisputeEvents(ctx, id)
+	if err != nil {
+		return nil, nil, fmt.Errorf("service.getDispute: %v", err)
+	}
+
+	return record, events, nil
+}
+
+func (s *disputeService) Respond(ctx context.Context, id, sellerID uuid.UUID, message string) error {
+	record, err := s.q.GetDisputeByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrNotFound
+		}
+		return fmt.Errorf("service.respond: %v", err)
+	}
+
+	if record.SellerID != sellerID {
+		return ErrForbidden
+	}
+
+	if record.Status == "resolved" {
+		return ErrAlreadyResolved
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("service.respond: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.q.WithTx(tx)
+
+	err = qtx.CreateDisputeEvent(ctx, pgstore.CreateDisputeEventParams{
+		DisputeID: id,
+		ActorID:   sellerID,
+		Kind:      "seller_response",
+		Body:      message,
+	})
+	if err != nil {
+		return fmt.Errorf("service.respond: %v", err)
+	}
+
+	if err := qtx.MarkDisputeResponded(ctx, id); err != nil {
+		return fmt.Errorf("service.respond: %v", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("service.respond: %v", err)
+	}
+
+	return nil
+}
+
+func (s *disputeService) Resolve(ctx context.Context, id, adminID uuid.UUID, resolution string, amount money.Amount, note string) (*pgstore.Dispute, error) {
+	record, err := s.q.GetDisputeByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("service.resolve: %v", err)
+	}
+
+	if record.Status == "resolved" {
+		return nil, ErrAlreadyResolved
+	}
+
+	order, err := s.q.GetOrderByID(ctx, record.OrderID)
+	if err != nil {
+		return nil, fmt.Errorf("service.resolve: %v", err)
+	}
+
+	refundable := order.TotalAmount - order.RefundedAmount
+
+	var refund money.Amount
+	switch resolution {
+	case ResolutionFullRefund:
+		refund = refundable
+	case ResolutionPartialRefund:
+		if amount <= 0 || amount > refundable {
+			return nil, ErrInvalidRefundAmount
+		}
+		refund = amount
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("service.resolve: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.q.WithTx(tx)
+
+	resolved, err := qtx.ResolveDispute(ctx, pgstore.ResolveDisputeParams{
+		ID:           id,
+		Resolution:   pgtype.Text{String: resolution, Valid: true},
+		RefundAmount: refund,
+		ResolvedBy:   pgtype.UUID{Bytes: adminID, Valid: true},
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrAlreadyResolved
+		}
+		return nil, fmt.Errorf("service.resolve: %v", err)
+	}
+
+	err = qtx.CreateDisputeEvent(ctx, pgstore.CreateDisputeEventParams{
+		DisputeID: id,
+		ActorID:   adminID,
+		Kind:      "resolved",
+		Body:      note,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("service.resolve: %v", err)
+	}
+
+	err = s.audit.WithTx(tx).Record(ctx, audit.Event{
+		ActorID:    adminID,
+		Action:     audit.ActionDisputeResolve,
+		TargetType: audit.TargetDispute,
+		TargetID:   id,
+		Before:     map[string]any{"status": record.Status, "refund_amount": record.RefundAmount},
+		After:      map[string]any{"status": resolved.Status, "resolution": resolution, "refund_amount": resolved.RefundAmount},
+		Reason:     note,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("service.resolve: %v", err)
+	}
+
+	var pending *pgstore.Refund
+	if refund > 0 {
+		split := splitRefund(order, refund)
+
+		pending, err = qtx.CreateRefund(ctx, pgstore.CreateRefundParams{
+			DisputeID:      id,
+			OrderID:        order.ID,
+			Amount:         refund,
+			NetAmount:      split.Net,
+			TaxAmount:      split.Tax,
+			ShippingAmount: split.Shipping,
+			FeeReversal:    split.FeeReversal,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("service.resolve: %v", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("service.resolve: %v", err)
+	}
+
+	if pending != nil {
+		if err := s.issueRefund(ctx, pending); err != nil {
+			logrus.WithFields(logrus.Fields{
+				"err":        err.Error(),
+				"dispute_id": id,
+				"refund":     refund,
+			}).Error("Resolve: refund left pending for reconciliation")
+		}
+	}
+
+	return resolved, nil
+}
+
+func (s *disputeService) ReconcileRefunds(ctx context.Context) (int, error) {
+	records, err := s.q.ListPendingRefunds(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("service.reconcileRefunds: %v", err)
+	}
+
+	issued := 0
+	var failures []error
+	for _, record := range records {
+		if err := s.issueRefund(ctx, record); err != nil {
+			failures = append(failures, fmt.Errorf("dispute %s: %v", record.DisputeID, err))
+			continue
+		}
+		issued++
+	}
+
+	if len(failures) > 0 {
+		return issued, fmt.Errorf("service.reconcileRefunds: %w", errors.Join(failures...))
+	}
+
+	return issued, nil
+}
+
+func (s *disputeService) issueRefund(ctx context.Context, record *pgstore.Refund) error {
+	reference, err := s.payments.Refund(ctx, record.DisputeID.String(), record.OrderID, record.Amount)
+	if err != nil {
+		return fmt.Errorf("service.issueRefund: %v", err)
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("service.issueRefund: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.q.WithTx(tx)
+
+	_, err = qtx.CompleteRefund(ctx, pgstore.CompleteRefundParams{
+		ID:        record.ID,
+		Reference: reference,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		return fmt.Errorf("service.issueRefund: %v", err)
+	}
+
+	order, err := qtx.GetOrderByID(ctx, record.OrderID)
+	if err != nil {
+		return fmt.Errorf("service.issueRefund: %v", err)
+	}
+
+	sellerDebit := record.NetAmount + record.ShippingAmount
+
+	entries := []pgstore.CreateLedgerEntryParams{
+		{OrderID: record.OrderID, Account: "buyer", Kind: "refund", Amount: record.Amount, Reference: reference},
+		{OrderID: record.OrderID, Account: "seller", Kind: "refund", Amount: -sellerDebit, Reference: reference},
+	}
+
+	if record.FeeReversal > 0 {
+		entries = append(entries,
+			pgstore.CreateLedgerEntryParams{OrderID: record.OrderID, Account: "seller", Kind: "fee_reversal", Amount: record.FeeReversal, Reference: reference},
+			pgstore.CreateLedgerEntryParams{OrderID: record.OrderID, Account: "platform", Kind: "fee_reversal", Amount: -record.FeeReversal, Reference: reference},
+		)
+	}
+
+	if record.TaxAmount > 0 {
+		entries = append(entries,
+			pgstore.CreateLedgerEntryParams{OrderID: record.OrderID, Account: "platform", Kind: "tax_refund", Amount: -record.TaxAmount, Reference: reference},
+		)
+	}
+
+	for _, entry := range entries {
+		if err := qtx.CreateLedgerEntry(ctx, entry); err != nil {
+			return fmt.Errorf("service.issueRefund: %v", err)
+		}
+	}
+
+	err = qtx.CreateSellerAdjustment(ctx, pgstore.CreateSellerAdjustmentParams{
+		SellerID: order.SellerID,
+		OrderID:  pgtype.UUID{Bytes: order.ID, Valid: true},
+		Kind:     "refund",
+		Amount:   record.FeeReversal - sellerDebit,
+	})
+	if err != nil {
+		return fmt.Errorf("service.issueRefund: %v", err)
+	}
+
+	err = qtx.AddOrderRefund(ctx, pgstore.AddOrderRefundParams{
+		ID:             record.OrderID,
+		RefundedAmount: record.Amount,
+	})
+	if err != nil {
+		return fmt.Errorf("service.issueRefund: %v", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("service.issueRefund: %v", err)
+	}
+
+	return nil
+}
+
+type refundSplit struct {
+	Net         money.Amount
+	Tax         money.Amount
+	Shipping    money.Amount
+	FeeReversal money.Amount
+}
+
+func splitRefund(order *pgstore.Order, refund money.Amount) refundSplit {
+	itemNet := order.TotalAmount - order.TaxAmount - order.ShippingCost
+
+	tax := order.TaxAmount.Prorate(refund, order.TotalAmount)
+	shipping := order.ShippingCost.Prorate(refund, order.TotalAmount)
+	net := refund - tax - shipping
+
+	return refundSplit{
+		Net:         net,
+		Tax:         tax,
+		Shipping:    shipping,
+		FeeReversal: min(order.FinalValueFee.Prorate(net, itemNet), order.FinalValueFee),
+	}
+}
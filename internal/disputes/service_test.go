@@ -0,0 +1,62 @@
+package disputes
+
+import (
+	"testing"
+
+	"github.com/EduardoMark/gobid/internal/money"
+	"github.com/EduardoMark/gobid/internal/store/pgstore"
+)
+
+func TestSplitRefund(t *testing.T) {
+	order := &pgstore.Order{
+		TotalAmount:   12500,
+		TaxAmount:     2000,
+		ShippingCost:  500,
+		ListingFee:    50,
+		FinalValueFee: 1000,
+	}
+
+	tests := []struct {
+		name   string
+		order  *pgstore.Order
+		refund money.Amount
+		want   refundSplit
+	}{
+		{
+			name:   "full refund returns every component and the whole final value fee",
+			order:  order,
+			refund: 12500,
+			want:   refundSplit{Net: 10000, Tax: 2000, Shipping: 500, FeeReversal: 1000},
+		},
+		{
+			name:   "partial refund is prorated",
+			order:  order,
+			refund: 6250,
+			want:   refundSplit{Net: 5000, Tax: 1000, Shipping: 250, FeeReversal: 500},
+		},
+		{
+			name:   "rounding keeps the parts summing to the refund",
+			order:  order,
+			refund: 1,
+			want:   refundSplit{Net: 1, Tax: 0, Shipping: 0, FeeReversal: 0},
+		},
+		{
+			name:   "untaxed pickup order",
+			order:  &pgstore.Order{TotalAmount: 2000, FinalValueFee: 200},
+			refund: 2000,
+			want:   refundSplit{Net: 2000, FeeReversal: 200},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := splitRefund(tt.order, tt.refund)
+			if got != tt.want {
+				t.Fatalf("splitRefund(%d) = %+v, want %+v", tt.refund, got, tt.want)
+			}
+			if got.Net+got.Tax+got.Shipping != tt.refund {
+				t.Fatalf("splitRefund(%d) = %+v does not add up to the refund", tt.refund, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package exports
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/EduardoMark/gobid/internal/store/pgstore"
+	"github.com/google/uuid"
+)
+
+type ExportResponse struct {
+	ID          uuid.UUID  `json:"id"`
+	Status      string     `json:"status"`
+	ExpiresAt   *time.Time `json:"expires_at"`
+	CompletedAt *time.Time `json:"completed_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+type ProfileExport struct {
+	ID                  uuid.UUID  `json:"id"`
+	Username            string     `json:"username"`
+	Email               string     `json:"email"`
+	Bio                 string     `json:"bio"`
+	EmailVerifiedAt     *time.Time `json:"email_verified_at"`
+	SuspendedAt         *time.Time `json:"suspended_at"`
+	SuspendedUntil      *time.Time `json:"suspended_until"`
+	SuspensionReason    string     `json:"suspension_reason,omitempty"`
+	Banned              bool       `json:"banned"`
+	DeletionRequestedAt *time.Time `json:"deletion_requested_at"`
+	ShowEmail           bool       `json:"show_email"`
+	ShowEmailVerified   bool       `json:"show_email_verified"`
+	ShowSalesHistory    bool       `json:"show_sales_history"`
+	Roles               []string   `json:"roles"`
+	CreatedAt           time.Time  `json:"created_at"`
+	UpdatedAt           time.Time  `json:"updated_at"`
+}
+
+type OrderExport struct {
+	*pgstore.Order
+	ShippingAddress json.RawMessage `json:"shipping_address"`
+}
+
+type AuditEventExport struct {
+	ID         uuid.UUID       `json:"id"`
+	ActorID    *uuid.UUID      `json:"actor_id"`
+	Action     string          `json:"action"`
+	TargetType string          `json:"target_type"`
+	TargetID   *uuid.UUID      `json:"target_id"`
+	Before     json.RawMessage `json:"before"`
+	After      json.RawMessage `json:"after"`
+	Reason     string          `json:"reason"`
+	IP         string          `json:"ip"`
+	RequestID  string          `json:"request_id"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
@@ -0,0 +1,190 @@
+package exports
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/EduardoMark/gobid/internal/api/middlewares"
+	"github.com/EduardoMark/gobid/internal/auth/session"
+	"github.com/EduardoMark/gobid/internal/auth/token"
+	"github.com/EduardoMark/gobid/internal/jsonutils"
+	"github.com/EduardoMark/gobid/internal/store/pgstore"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+type ExportHandler struct {
+	svc        Service
+	jwtService token.JwtService
+	sessions   session.Checker
+}
+
+func NewExportHandler(svc Service, jwtService token.JwtService, sessions session.Checker) ExportHandler {
+	return ExportHandler{
+		svc:        svc,
+		jwtService: jwtService,
+		sessions:   sessions,
+	}
+}
+
+func (m *ExportHandler) RegisterExportRoutes(r chi.Router) {
+	r.Group(func(r chi.Router) {
+		r.Use(middlewares.AuthToken(m.jwtService, m.sessions))
+
+		r.Post("/users/me/export", m.Request)
+		r.Get("/users/me/exports/{id}", m.Get)
+	})
+
+	r.Get("/exports/{id}/download", m.Download)
+}
+
+func (m *ExportHandler) Request(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, ok := currentUserID(w, r)
+	if !ok {
+		return
+	}
+
+	record, err := m.svc.Request(ctx, userID)
+	if err != nil {
+		if errors.Is(err, ErrExportInProgress) {
+			jsonutils.EncodeJson(w, r, http.StatusConflict, map[string]any{
+				"error": "data export already in progress",
+			})
+			return
+		}
+
+		logrus.WithField("err", err.Error()).Error("Handler.Request")
+
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "unexpected internal server error",
+		})
+		return
+	}
+
+	jsonutils.EncodeJson(w, r, http.StatusAccepted, map[string]any{
+		"export": toExportResponse(record),
+	})
+}
+
+func (m *ExportHandler) Get(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, ok := currentUserID(w, r)
+	if !ok {
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "invalid uuid type",
+		})
+		return
+	}
+
+	record, err := m.svc.Get(ctx, userID, id)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			jsonutils.EncodeJson(w, r, http.StatusNotFound, map[string]any{
+				"error": "data export not found",
+			})
+			return
+		}
+
+		logrus.WithField("err", err.Error()).Error("Handler.Get")
+
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "unexpected internal server error",
+		})
+		return
+	}
+
+	jsonutils.EncodeJson(w, r, http.StatusOK, map[string]any{
+		"export": toExportResponse(record),
+	})
+}
+
+func (m *ExportHandler) Download(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "invalid uuid type",
+		})
+		return
+	}
+
+	archive, err := m.svc.Download(ctx, id, r.URL.Query().Get("token"))
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrNotFound), errors.Is(err, ErrInvalidToken):
+			jsonutils.EncodeJson(w, r, http.StatusNotFound, map[string]any{
+				"error": "data export not found",
+			})
+		case errors.Is(err, ErrExportExpired):
+			jsonutils.EncodeJson(w, r, http.StatusGone, map[string]any{
+				"error": "download link expired",
+			})
+		case errors.Is(err, ErrExportNotReady):
+			jsonutils.EncodeJson(w, r, http.StatusConflict, map[string]any{
+				"error": "data export not ready",
+			})
+		default:
+			logrus.WithField("err", err.Error()).Error("Handler.Download")
+
+			jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+				"error": "unexpected internal server error",
+			})
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="gobid-export-%s.zip"`, id))
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+	w.Write(archive)
+}
+
+func currentUserID(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	id, ok := r.Context().Value(middlewares.UserIDKey).(string)
+	if !ok {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "user ID not found in context",
+		})
+		return uuid.UUID{}, false
+	}
+
+	userID, err := uuid.Parse(id)
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "invalid user ID format",
+		})
+		return uuid.UUID{}, false
+	}
+
+	return userID, true
+}
+
+func toExportResponse(record *pgstore.DataExport) ExportResponse {
+	res := ExportResponse{
+		ID:        record.ID,
+		Status:    record.Status,
+		CreatedAt: record.CreatedAt,
+	}
+
+	if record.ExpiresAt.Valid {
+		res.ExpiresAt = &record.ExpiresAt.Time
+	}
+
+	if record.CompletedAt.Valid {
+		res.CompletedAt = &record.CompletedAt.Time
+	}
+
+	return res
+}
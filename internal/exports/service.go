@@ -0,0 +1,394 @@
+package exports
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/EduardoMark/gobid/internal/mailer"
+	"github.com/EduardoMark/gobid/internal/store/pgstore"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	StatusPending = "pending"
+	StatusReady   = "ready"
+	StatusFailed  = "failed"
+	StatusExpired = "expired"
+)
+
+const (
+	downloadTTL  = 24 * time.Hour
+	buildTimeout = 15 * time.Minute
+)
+
+type Service interface {
+	Request(ctx context.Context, userID uuid.UUID) (*pgstore.DataExport, error)
+	Get(ctx context.Context, userID, id uuid.UUID) (*pgstore.DataExport, error)
+	Download(ctx context.Context, id uuid.UUID, token string) ([]byte, error)
+	Sweep(ctx context.Context) (int, error)
+}
+
+type exportService struct {
+	pool   *pgxpool.Pool
+	q      *pgstore.Queries
+	mailer mailer.Mailer
+	url    *url.URL
+}
+
+var ErrNotFound = errors.New("not found")
+var ErrExportInProgress = errors.New("data export already in progress")
+var ErrExportNotReady = errors.New("data export not ready")
+var ErrExportExpired = errors.New("data export expired")
+var ErrInvalidToken = errors.New("invalid download token")
+
+func NewExportService(pool *pgxpool.Pool, mailer mailer.Mailer, publicURL *url.URL) Service {
+	return &exportService{
+		pool:   pool,
+		q:      pgstore.New(pool),
+		mailer: mailer,
+		url:    publicURL,
+	}
+}
+
+func (s *exportService) Request(ctx context.Context, userID uuid.UUID) (*pgstore.DataExport, error) {
+	if _, err := s.Sweep(ctx); err != nil {
+		return nil, fmt.Errorf("service.request: %v", err)
+	}
+
+	pending, err := s.q.HasPendingDataExport(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("service.request: %v", err)
+	}
+
+	if pending {
+		return nil, ErrExportInProgress
+	}
+
+	record, err := s.q.CreateDataExport(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("service.request: %v", err)
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), buildTimeout)
+		defer cancel()
+
+		s.run(ctx, record.ID, userID)
+	}()
+
+	return record, nil
+}
+
+func (s *exportService) Get(ctx context.Context, userID, id uuid.UUID) (*pgstore.DataExport, error) {
+	record, err := s.q.GetDataExport(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("service.get: %v", err)
+	}
+
+	if record.UserID != userID {
+		return nil, ErrNotFound
+	}
+
+	return record, nil
+}
+
+func (s *exportService) Download(ctx context.Context, id uuid.UUID, token string) ([]byte, error) {
+	record, err := s.q.GetDataExport(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("service.download: %v", err)
+	}
+
+	if subtle.ConstantTimeCompare([]byte(hashToken(token)), []byte(record.TokenHash)) != 1 {
+		return nil, ErrInvalidToken
+	}
+
+	if record.Status == StatusExpired || (record.ExpiresAt.Valid && !time.Now().Before(record.ExpiresAt.Time)) {
+		return nil, ErrExportExpired
+	}
+
+	if record.Status != StatusReady {
+		return nil, ErrExportNotReady
+	}
+
+	return record.Archive, nil
+}
+
+func (s *exportService) Sweep(ctx context.Context) (int, error) {
+	if err := s.q.ExpireDataExports(ctx); err != nil {
+		return 0, fmt.Errorf("service.sweep: %v", err)
+	}
+
+	failed, err := s.q.FailStaleDataExports(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("service.sweep: %v", err)
+	}
+
+	return int(failed), nil
+}
+
+func (s *exportService) run(ctx context.Context, id, userID uuid.UUID) {
+	log := logrus.WithFields(logrus.Fields{
+		"export_id": id,
+		"user_id":   userID,
+	})
+
+	user, err := s.q.GetUserByID(ctx, userID)
+	if err != nil {
+		log.WithField("err", err.Error()).Error("exportService.run")
+		s.fail(ctx, id)
+		return
+	}
+
+	archive, err := s.build(ctx, user)
+	if err != nil {
+		log.WithField("err", err.Error()).Error("exportService.run")
+		s.fail(ctx, id)
+		return
+	}
+
+	token, err := newToken()
+	if err != nil {
+		log.WithField("err", err.Error()).Error("exportService.run")
+		s.fail(ctx, id)
+		return
+	}
+
+	expiresAt := time.Now().Add(downloadTTL)
+
+	completed, err := s.q.CompleteDataExport(ctx, pgstore.CompleteDataExportParams{
+		ID:        id,
+		Archive:   archive,
+		TokenHash: hashToken(token),
+		ExpiresAt: pgtype.Timestamptz{Time: expiresAt, Valid: true},
+	})
+	if err != nil {
+		log.WithField("err", err.Error()).Error("exportService.run")
+		s.fail(ctx, id)
+		return
+	}
+
+	if completed == 0 {
+		log.Warn("exportService.run: export was no longer pending")
+		return
+	}
+
+	err = s.mailer.Send(ctx, mailer.Message{
+		To:      user.Email,
+		Subject: "Your gobid data export is ready",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nThe copy of your gobid data you requested is ready. Download it from the link below before %s; after that the link expires and you will need to request a new export.\n\n%s\n",
+			user.Username, expiresAt.UTC().Format(time.RFC1123), s.downloadURL(id, token),
+		),
+	})
+	if err != nil {
+		log.WithField("err", err.Error()).Error("exportService.run")
+	}
+}
+
+func (s *exportService) downloadURL(id uuid.UUID, token string) string {
+	link := s.url.JoinPath("api/v1/exports", id.String(), "download")
+	link.RawQuery = url.Values{"token": {token}}.Encode()
+
+	return link.String()
+}
+
+func (s *exportService) fail(ctx context.Context, id uuid.UUID) {
+	if err := s.q.FailDataExport(context.WithoutCancel(ctx), id); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"err":       err.Error(),
+			"export_id": id,
+		}).Error("exportService.fail")
+	}
+}
+
+func (s *exportService) build(ctx context.Context, user *pgstore.User) ([]byte, error) {
+	roles, err := s.q.ListUserRoles(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	addresses, err := s.q.ListAddressesByUserID(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	listings, err := s.q.ExportUserProducts(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	orders, err := s.q.ExportUserOrders(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	disputes, err := s.q.ExportUserDisputes(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	messages, err := s.q.ExportUserDisputeEvents(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions, err := s.q.ExportUserSessions(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	identities, err := s.q.ExportUserIdentities(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	audit, err := s.q.ExportUserAuditEvents(ctx, pgtype.UUID{Bytes: user.ID, Valid: true})
+	if err != nil {
+		return nil, err
+	}
+
+	orderExports := make([]OrderExport, len(orders))
+	for i, order := range orders {
+		orderExports[i] = OrderExport{Order: order, ShippingAddress: order.ShippingAddress}
+		if len(order.ShippingAddress) == 0 {
+			orderExports[i].ShippingAddress = json.RawMessage("null")
+		}
+	}
+
+	auditExports := make([]AuditEventExport, len(audit))
+	for i, event := range audit {
+		auditExports[i] = toAuditEventExport(event, user.ID)
+	}
+
+	files := []struct {
+		name string
+		data any
+	}{
+		{"profile.json", toProfileExport(user, roles)},
+		{"addresses.json", addresses},
+		{"listings.json", listings},
+		{"orders.json", orderExports},
+		{"disputes.json", disputes},
+		{"messages.json", messages},
+		{"sessions.json", sessions},
+		{"identities.json", identities},
+		{"audit.json", auditExports},
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+
+	for _, file := range files {
+		w, err := zw.Create(file.name)
+		if err != nil {
+			return nil, err
+		}
+
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(file.data); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func toProfileExport(user *pgstore.User, roles []string) ProfileExport {
+	res := ProfileExport{
+		ID:                user.ID,
+		Username:          user.Username,
+		Email:             user.Email,
+		Bio:               user.Bio,
+		SuspensionReason:  user.SuspensionReason,
+		Banned:            user.Banned,
+		ShowEmail:         user.ShowEmail,
+		ShowEmailVerified: user.ShowEmailVerified,
+		ShowSalesHistory:  user.ShowSalesHistory,
+		Roles:             roles,
+		CreatedAt:         user.CreatedAt,
+		UpdatedAt:         user.UpdatedAt,
+	}
+
+	if user.EmailVerifiedAt.Valid {
+		res.EmailVerifiedAt = &user.EmailVerifiedAt.Time
+	}
+
+	if user.SuspendedAt.Valid {
+		res.SuspendedAt = &user.SuspendedAt.Time
+	}
+
+	if user.SuspendedUntil.Valid {
+		res.SuspendedUntil = &user.SuspendedUntil.Time
+	}
+
+	if user.DeletionRequestedAt.Valid {
+		res.DeletionRequestedAt = &user.DeletionRequestedAt.Time
+	}
+
+	return res
+}
+
+func toAuditEventExport(event *pgstore.AuditEvent, userID uuid.UUID) AuditEventExport {
+	res := AuditEventExport{
+		ID:         event.ID,
+		Action:     event.Action,
+		TargetType: event.TargetType,
+		Before:     event.Before,
+		After:      event.After,
+		Reason:     event.Reason,
+		CreatedAt:  event.CreatedAt,
+	}
+
+	if event.ActorID.Valid && uuid.UUID(event.ActorID.Bytes) == userID {
+		res.ActorID = &userID
+		res.IP = event.Ip
+		res.RequestID = event.RequestID
+	}
+
+	if event.TargetID.Valid {
+		targetID := uuid.UUID(event.TargetID.Bytes)
+		res.TargetID = &targetID
+	}
+
+	return res
+}
+
+func newToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,117 @@
+package exports
+
+import (
+	"encoding/json"
+	"net/url"
+	"testing"
+
+	"github.com/EduardoMark/gobid/internal/store/pgstore"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func TestDownloadURL(t *testing.T) {
+	id := uuid.MustParse("0b7e4a3c-5a52-4d4f-9a47-3c1f8f2d9e10")
+
+	tests := []struct {
+		name string
+		base string
+		want string
+	}{
+		{
+			name: "host only",
+			base: "https://gobid.example",
+			want: "https://gobid.example/api/v1/exports/0b7e4a3c-5a52-4d4f-9a47-3c1f8f2d9e10/download?token=abc-_123",
+		},
+		{
+			name: "path prefix with trailing slash",
+			base: "https://example.com/gobid/",
+			want: "https://example.com/gobid/api/v1/exports/0b7e4a3c-5a52-4d4f-9a47-3c1f8f2d9e10/download?token=abc-_123",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base, err := url.Parse(tt.base)
+			if err != nil {
+				t.Fatalf("url.Parse() unexpected error: %v", err)
+			}
+
+			s := &exportService{url: base}
+			if got := s.downloadURL(id, "abc-_123"); got != tt.want {
+				t.Fatalf("downloadURL() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestToAuditEventExport(t *testing.T) {
+	userID := uuid.New()
+	adminID := uuid.New()
+
+	tests := []struct {
+		name      string
+		actor     pgtype.UUID
+		wantActor bool
+	}{
+		{
+			name:      "own action keeps actor details",
+			actor:     pgtype.UUID{Bytes: userID, Valid: true},
+			wantActor: true,
+		},
+		{
+			name:  "another actor is redacted",
+			actor: pgtype.UUID{Bytes: adminID, Valid: true},
+		},
+		{
+			name:  "system action has no actor",
+			actor: pgtype.UUID{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event := &pgstore.AuditEvent{
+				ID:         uuid.New(),
+				ActorID:    tt.actor,
+				Action:     "user.suspend",
+				TargetType: "user",
+				TargetID:   pgtype.UUID{Bytes: userID, Valid: true},
+				Before:     []byte(`{"banned":false}`),
+				After:      []byte(`{"banned":true}`),
+				Ip:         "203.0.113.7",
+				RequestID:  "req-1",
+			}
+
+			data, err := json.Marshal(toAuditEventExport(event, userID))
+			if err != nil {
+				t.Fatalf("json.Marshal() unexpected error: %v", err)
+			}
+
+			var got map[string]any
+			if err := json.Unmarshal(data, &got); err != nil {
+				t.Fatalf("json.Unmarshal() unexpected error: %v", err)
+			}
+
+			if before, ok := got["before"].(map[string]any); !ok || before["banned"] != false {
+				t.Fatalf("before = %v, want a JSON object", got["before"])
+			}
+			if after, ok := got["after"].(map[string]any); !ok || after["banned"] != true {
+				t.Fatalf("after = %v, want a JSON object", got["after"])
+			}
+			if got["target_id"] != userID.String() {
+				t.Fatalf("target_id = %v, want %s", got["target_id"], userID)
+			}
+
+			if tt.wantActor {
+				if got["actor_id"] != userID.String() || got["ip"] != "203.0.113.7" || got["request_id"] != "req-1" {
+					t.Fatalf("own action lost actor details: %s", data)
+				}
+				return
+			}
+			if got["actor_id"] != nil || got["ip"] != "" || got["request_id"] != "" {
+				t.Fatalf("another actor leaked into the export: %s", data)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package fees
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/EduardoMark/gobid/internal/money"
+)
+
+type Schedule struct {
+	ListingFee        money.Amount
+	FinalValuePercent float64
+	FinalValueCap     money.Amount
+}
+
+type Breakdown struct {
+	ListingFee    money.Amount
+	FinalValueFee money.Amount
+	SellerNet     money.Amount
+}
+
+var DefaultSchedule = Schedule{
+	ListingFee:        0,
+	FinalValuePercent: 10,
+	FinalValueCap:     0,
+}
+
+func LoadSchedule() (Schedule, error) {
+	s := DefaultSchedule
+
+	amounts := []struct {
+		key string
+		dst *money.Amount
+	}{
+		{"GOBID_FEE_LISTING", &s.ListingFee},
+		{"GOBID_FEE_FINAL_VALUE_CAP", &s.FinalValueCap},
+	}
+
+	for _, v := range amounts {
+		raw := os.Getenv(v.key)
+		if raw == "" {
+			continue
+		}
+
+		value, err := money.Parse(raw)
+		if err != nil || value < 0 {
+			return Schedule{}, fmt.Errorf("invalid %s: %q", v.key, raw)
+		}
+		*v.dst = value
+	}
+
+	if raw := os.Getenv("GOBID_FEE_FINAL_VALUE_PERCENT"); raw != "" {
+		value, err := strconv.ParseFloat(raw, 64)
+		if err != nil || value < 0 {
+			return Schedule{}, fmt.Errorf("invalid GOBID_FEE_FINAL_VALUE_PERCENT: %q", raw)
+		}
+		s.FinalValuePercent = value
+	}
+
+	if s.FinalValuePercent > 100 {
+		return Schedule{}, fmt.Errorf("invalid GOBID_FEE_FINAL_VALUE_PERCENT: %v", s.FinalValuePercent)
+	}
+
+	return s, nil
+}
+
+func (s Schedule) Apply(amount money.Amount) Breakdown {
+	amount = max(amount, 0)
+
+	finalValueFee := amount.Percent(s.FinalValuePercent)
+	if s.FinalValueCap > 0 && finalValueFee > s.FinalValueCap {
+		finalValueFee = s.FinalValueCap
+	}
+
+	listingFee := min(s.ListingFee, amount)
+	finalValueFee = min(finalValueFee, amount-listingFee)
+
+	return Breakdown{
+		ListingFee:    listingFee,
+		FinalValueFee: finalValueFee,
+		SellerNet:     amount - listingFee - finalValueFee,
+	}
+}
@@ -0,0 +1,132 @@
+package fees
+
+import (
+	"testing"
+
+	"github.com/EduardoMark/gobid/internal/money"
+)
+
+func TestApply(t *testing.T) {
+	tests := []struct {
+		name     string
+		schedule Schedule
+		amount   money.Amount
+		want     Breakdown
+	}{
+		{
+			name:     "default percentage",
+			schedule: DefaultSchedule,
+			amount:   10000,
+			want:     Breakdown{FinalValueFee: 1000, SellerNet: 9000},
+		},
+		{
+			name:     "percentage rounds half a cent up",
+			schedule: Schedule{FinalValuePercent: 10},
+			amount:   5,
+			want:     Breakdown{FinalValueFee: 1, SellerNet: 4},
+		},
+		{
+			name:     "listing fee and percentage",
+			schedule: Schedule{ListingFee: 50, FinalValuePercent: 5},
+			amount:   2000,
+			want:     Breakdown{ListingFee: 50, FinalValueFee: 100, SellerNet: 1850},
+		},
+		{
+			name:     "final value fee is capped",
+			schedule: Schedule{FinalValuePercent: 10, FinalValueCap: 2500},
+			amount:   100000,
+			want:     Breakdown{FinalValueFee: 2500, SellerNet: 97500},
+		},
+		{
+			name:     "cap above the fee has no effect",
+			schedule: Schedule{FinalValuePercent: 10, FinalValueCap: 2500},
+			amount:   10000,
+			want:     Breakdown{FinalValueFee: 1000, SellerNet: 9000},
+		},
+		{
+			name:     "listing fee is clamped to the amount",
+			schedule: Schedule{ListingFee: 500, FinalValuePercent: 10},
+			amount:   300,
+			want:     Breakdown{ListingFee: 300, FinalValueFee: 0, SellerNet: 0},
+		},
+		{
+			name:     "final value fee is clamped to what the listing fee leaves",
+			schedule: Schedule{ListingFee: 90, FinalValuePercent: 50},
+			amount:   100,
+			want:     Breakdown{ListingFee: 90, FinalValueFee: 10, SellerNet: 0},
+		},
+		{
+			name:     "zero amount",
+			schedule: Schedule{ListingFee: 50, FinalValuePercent: 10},
+			amount:   0,
+			want:     Breakdown{},
+		},
+		{
+			name:     "negative amount is treated as zero",
+			schedule: Schedule{ListingFee: 50, FinalValuePercent: 10},
+			amount:   -100,
+			want:     Breakdown{},
+		},
+		{
+			name:     "no fees",
+			schedule: Schedule{},
+			amount:   1999,
+			want:     Breakdown{SellerNet: 1999},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.schedule.Apply(tt.amount)
+			if got != tt.want {
+				t.Fatalf("Apply(%d) = %+v, want %+v", tt.amount, got, tt.want)
+			}
+			if got.ListingFee+got.FinalValueFee+got.SellerNet != max(tt.amount, 0) {
+				t.Fatalf("Apply(%d) = %+v does not add up to the amount", tt.amount, got)
+			}
+		})
+	}
+}
+
+func TestLoadSchedule(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     map[string]string
+		want    Schedule
+		wantErr bool
+	}{
+		{name: "defaults", want: DefaultSchedule},
+		{
+			name: "overrides",
+			env: map[string]string{
+				"GOBID_FEE_LISTING":             "0.30",
+				"GOBID_FEE_FINAL_VALUE_PERCENT": "12.5",
+				"GOBID_FEE_FINAL_VALUE_CAP":     "250",
+			},
+			want: Schedule{ListingFee: 30, FinalValuePercent: 12.5, FinalValueCap: 25000},
+		},
+		{name: "negative listing fee", env: map[string]string{"GOBID_FEE_LISTING": "-1"}, wantErr: true},
+		{name: "sub-cent cap", env: map[string]string{"GOBID_FEE_FINAL_VALUE_CAP": "1.005"}, wantErr: true},
+		{name: "percent above 100", env: map[string]string{"GOBID_FEE_FINAL_VALUE_PERCENT": "101"}, wantErr: true},
+		{name: "percent not a number", env: map[string]string{"GOBID_FEE_FINAL_VALUE_PERCENT": "ten"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, key := range []string{"GOBID_FEE_LISTING", "GOBID_FEE_FINAL_VALUE_PERCENT", "GOBID_FEE_FINAL_VALUE_CAP"} {
+				t.Setenv(key, tt.env[key])
+			}
+
+			got, err := LoadSchedule()
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("LoadSchedule() = %+v, want an error", got)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Fatalf("LoadSchedule() = %+v, %v, want %+v", got, err, tt.want)
+			}
+		})
+	}
+}
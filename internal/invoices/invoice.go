@@ -0,0 +1,192 @@
+package invoices
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"fmt"
+	"html/template"
+	"strconv"
+	"time"
+
+	"github.com/EduardoMark/gobid/internal/money"
+	"github.com/EduardoMark/gobid/internal/store/pgstore"
+	"github.com/go-pdf/fpdf"
+	"github.com/google/uuid"
+)
+
+type Party struct {
+	Name  string
+	Email string
+}
+
+type Invoice struct {
+	Number        int64
+	IssuedAt      time.Time
+	OrderID       uuid.UUID
+	Seller        Party
+	Buyer         Party
+	Item          string
+	Amount        money.Amount
+	Shipping      money.Amount
+	TaxName       string
+	TaxRate       float64
+	Tax           money.Amount
+	ListingFee    money.Amount
+	FinalValueFee money.Amount
+}
+
+func (inv Invoice) FormattedNumber() string {
+	return fmt.Sprintf("INV-%08d", inv.Number)
+}
+
+func (inv Invoice) TaxLabel() string {
+	if inv.TaxName == "" {
+		return "Tax"
+	}
+
+	return fmt.Sprintf("%s (%s%%)", inv.TaxName, strconv.FormatFloat(inv.TaxRate, 'f', -1, 64))
+}
+
+func (inv Invoice) Total() money.Amount {
+	return inv.Amount + inv.Shipping + inv.Tax
+}
+
+//go:embed invoice.html
+var htmlSource string
+
+var htmlTemplate = template.Must(template.New("invoice").Funcs(template.FuncMap{
+	"money": formatMoney,
+}).Parse(htmlSource))
+
+func RenderHTML(inv Invoice) (string, error) {
+	var buf bytes.Buffer
+	if err := htmlTemplate.Execute(&buf, inv); err != nil {
+		return "", fmt.Errorf("render html: %v", err)
+	}
+
+	return buf.String(), nil
+}
+
+func RenderPDF(inv Invoice) ([]byte, error) {
+	pdf := fpdf.New("P", "mm", "A4", "")
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+	pdf.AddPage()
+
+	pdf.SetFont("Helvetica", "B", 18)
+	pdf.Cell(0, 10, "Invoice "+inv.FormattedNumber())
+	pdf.Ln(8)
+
+	pdf.SetFont("Helvetica", "", 9)
+	pdf.Cell(0, 6, fmt.Sprintf("Issued %s - Order %s", inv.IssuedAt.Format("2006-01-02"), inv.OrderID))
+	pdf.Ln(12)
+
+	pdf.SetFont("Helvetica", "B", 11)
+	pdf.Cell(95, 6, "Seller")
+	pdf.Cell(95, 6, "Buyer")
+	pdf.Ln(6)
+	pdf.SetFont("Helvetica", "", 11)
+	pdf.Cell(95, 6, tr(inv.Seller.Name))
+	pdf.Cell(95, 6, tr(inv.Buyer.Name))
+	pdf.Ln(6)
+	pdf.Cell(95, 6, inv.Seller.Email)
+	pdf.Cell(95, 6, inv.Buyer.Email)
+	pdf.Ln(14)
+
+	row := func(label, amount string, bold bool) {
+		style := ""
+		if bold {
+			style = "B"
+		}
+		pdf.SetFont("Helvetica", style, 11)
+		pdf.CellFormat(140, 8, label, "B", 0, "L", false, 0, "")
+		pdf.CellFormat(50, 8, amount, "B", 1, "R", false, 0, "")
+	}
+
+	row("Description", "Amount", true)
+	row(tr(inv.Item), formatMoney(inv.Amount), false)
+	row("Shipping (not taxed)", formatMoney(inv.Shipping), false)
+	row(tr(inv.TaxLabel()), formatMoney(inv.Tax), false)
+	row("Total", formatMoney(inv.Total()), true)
+	pdf.Ln(8)
+
+	row("Platform fees (charged to seller)", "Amount", true)
+	row("Listing fee", formatMoney(inv.ListingFee), false)
+	row("Final value fee", formatMoney(inv.FinalValueFee), false)
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, fmt.Errorf("render pdf: %v", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+func Issue(ctx context.Context, q *pgstore.Queries, order *pgstore.Order) error {
+	seller, err := q.GetUserByID(ctx, order.SellerID)
+	if err != nil {
+		return fmt.Errorf("invoices.issue: %v", err)
+	}
+
+	buyer, err := q.GetUserByID(ctx, order.BuyerID)
+	if err != nil {
+		return fmt.Errorf("invoices.issue: %v", err)
+	}
+
+	product, err := q.GetOneProductByID(ctx, order.ProductID)
+	if err != nil {
+		return fmt.Errorf("invoices.issue: %v", err)
+	}
+
+	last, err := q.LockInvoiceCounter(ctx)
+	if err != nil {
+		return fmt.Errorf("invoices.issue: %v", err)
+	}
+
+	number := last + 1
+	if err := q.SetInvoiceCounter(ctx, number); err != nil {
+		return fmt.Errorf("invoices.issue: %v", err)
+	}
+
+	inv := Invoice{
+		Number:        number,
+		IssuedAt:      order.PaidAt.Time,
+		OrderID:       order.ID,
+		Seller:        Party{Name: seller.Username, Email: seller.Email},
+		Buyer:         Party{Name: buyer.Username, Email: buyer.Email},
+		Item:          product.Name,
+		Amount:        order.TotalAmount - order.TaxAmount - order.ShippingCost,
+		Shipping:      order.ShippingCost,
+		TaxName:       order.TaxName,
+		TaxRate:       order.TaxRate,
+		Tax:           order.TaxAmount,
+		ListingFee:    order.ListingFee,
+		FinalValueFee: order.FinalValueFee,
+	}
+
+	html, err := RenderHTML(inv)
+	if err != nil {
+		return fmt.Errorf("invoices.issue: %v", err)
+	}
+
+	pdf, err := RenderPDF(inv)
+	if err != nil {
+		return fmt.Errorf("invoices.issue: %v", err)
+	}
+
+	err = q.CreateInvoice(ctx, pgstore.CreateInvoiceParams{
+		OrderID: order.ID,
+		Number:  number,
+		Html:    html,
+		Pdf:     pdf,
+	})
+	if err != nil {
+		return fmt.Errorf("invoices.issue: %v", err)
+	}
+
+	return nil
+}
+
+func formatMoney(v money.Amount) string {
+	return v.String()
+}
@@ -0,0 +1,256 @@
+package lockout
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/EduardoMark/gobid/internal/store/pgstore"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	ScopeAccount       = "account"
+	ScopeIP            = "ip"
+	ScopeTwoFactor     = "two_factor"
+	ScopePasswordReset = "password_reset"
+)
+
+type Policy struct {
+	Threshold int32
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+}
+
+var Policies = map[string]Policy{
+	ScopeAccount:       {Threshold: 5, BaseDelay: time.Second * 30, MaxDelay: time.Hour},
+	ScopeIP:            {Threshold: 20, BaseDelay: time.Minute, MaxDelay: time.Hour},
+	ScopeTwoFactor:     {Threshold: 5, BaseDelay: time.Second * 30, MaxDelay: time.Hour},
+	ScopePasswordReset: {Threshold: 10, BaseDelay: time.Minute * 15, MaxDelay: time.Hour * 24},
+}
+
+func (p Policy) LockFor(failures int32) time.Duration {
+	if failures < p.Threshold {
+		return 0
+	}
+
+	exp := float64(failures - p.Threshold)
+	delay := time.Duration(float64(p.BaseDelay) * math.Pow(2, exp))
+	if delay <= 0 || delay > p.MaxDelay {
+		return p.MaxDelay
+	}
+
+	return delay
+}
+
+var ErrLocked = errors.New("too many failed attempts")
+
+type LockedError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LockedError) Error() string {
+	return fmt.Sprintf("%v, retry after %v", ErrLocked, e.RetryAfter)
+}
+
+func (e *LockedError) Unwrap() error {
+	return ErrLocked
+}
+
+type Attempt struct {
+	Scope   string
+	Subject string
+	UserID  uuid.UUID
+}
+
+func Account(email string, userID uuid.UUID) Attempt {
+	return Attempt{Scope: ScopeAccount, Subject: strings.ToLower(email), UserID: userID}
+}
+
+func IP(addr string) Attempt {
+	return Attempt{Scope: ScopeIP, Subject: addr}
+}
+
+func TwoFactor(userID uuid.UUID) Attempt {
+	return Attempt{Scope: ScopeTwoFactor, Subject: userID.String(), UserID: userID}
+}
+
+func PasswordReset(addr string) Attempt {
+	return Attempt{Scope: ScopePasswordReset, Subject: addr}
+}
+
+func (a Attempt) Key() string {
+	return a.Scope + ":" + a.Subject
+}
+
+type Guard interface {
+	Check(ctx context.Context, attempts ...Attempt) error
+	Fail(ctx context.Context, attempts ...Attempt) error
+	Reset(ctx context.Context, attempts ...Attempt) error
+	Unlock(ctx context.Context, userID, adminID uuid.UUID, attempts ...Attempt) error
+}
+
+type guard struct {
+	pool *pgxpool.Pool
+	q    *pgstore.Queries
+}
+
+func NewGuard(pool *pgxpool.Pool) Guard {
+	return &guard{
+		pool: pool,
+		q:    pgstore.New(pool),
+	}
+}
+
+func (g *guard) Check(ctx context.Context, attempts ...Attempt) error {
+	var retryAfter time.Duration
+
+	for _, attempt := range attempts {
+		if attempt.Subject == "" {
+			continue
+		}
+
+		record, err := g.q.GetLoginAttempt(ctx, attempt.Key())
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				continue
+			}
+			return fmt.Errorf("guard.check: %v", err)
+		}
+
+		if record.LockedUntil.Valid {
+			if wait := time.Until(record.LockedUntil.Time); wait > retryAfter {
+				retryAfter = wait
+			}
+		}
+	}
+
+	if retryAfter > 0 {
+		return &LockedError{RetryAfter: retryAfter}
+	}
+
+	return nil
+}
+
+func (g *guard) Fail(ctx context.Context, attempts ...Attempt) error {
+	var retryAfter time.Duration
+
+	for _, attempt := range attempts {
+		if attempt.Subject == "" {
+			continue
+		}
+
+		policy := Policies[attempt.Scope]
+
+		record, err := g.q.RecordLoginFailure(ctx, attempt.Key())
+		if err != nil {
+			return fmt.Errorf("guard.fail: %v", err)
+		}
+
+		delay := policy.LockFor(record.Failures)
+		if delay == 0 {
+			continue
+		}
+
+		lockedUntil := time.Now().Add(delay)
+
+		if err := g.lock(ctx, attempt, record.Failures, lockedUntil); err != nil {
+			return fmt.Errorf("guard.fail: %v", err)
+		}
+
+		logrus.WithFields(logrus.Fields{
+			"scope":        attempt.Scope,
+			"subject":      attempt.Subject,
+			"failures":     record.Failures,
+			"locked_until": lockedUntil,
+		}).Warn("login locked out")
+
+		if delay > retryAfter {
+			retryAfter = delay
+		}
+	}
+
+	if retryAfter > 0 {
+		return &LockedError{RetryAfter: retryAfter}
+	}
+
+	return nil
+}
+
+func (g *guard) lock(ctx context.Context, attempt Attempt, failures int32, lockedUntil time.Time) error {
+	tx, err := g.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := g.q.WithTx(tx)
+
+	err = qtx.LockLoginAttempt(ctx, pgstore.LockLoginAttemptParams{
+		Key:         attempt.Key(),
+		LockedUntil: pgtype.Timestamptz{Time: lockedUntil, Valid: true},
+	})
+	if err != nil {
+		return err
+	}
+
+	err = qtx.CreateAccountLockout(ctx, pgstore.CreateAccountLockoutParams{
+		Scope:       attempt.Scope,
+		Subject:     attempt.Subject,
+		UserID:      pgtype.UUID{Bytes: attempt.UserID, Valid: attempt.UserID != uuid.Nil},
+		Failures:    failures,
+		LockedUntil: lockedUntil,
+	})
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (g *guard) Reset(ctx context.Context, attempts ...Attempt) error {
+	for _, attempt := range attempts {
+		if err := g.q.ClearLoginAttempts(ctx, attempt.Key()); err != nil {
+			return fmt.Errorf("guard.reset: %v", err)
+		}
+	}
+
+	return nil
+}
+
+func (g *guard) Unlock(ctx context.Context, userID, adminID uuid.UUID, attempts ...Attempt) error {
+	tx, err := g.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("guard.unlock: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := g.q.WithTx(tx)
+
+	for _, attempt := range attempts {
+		if err := qtx.ClearLoginAttempts(ctx, attempt.Key()); err != nil {
+			return fmt.Errorf("guard.unlock: %v", err)
+		}
+	}
+
+	err = qtx.UnlockAccountLockouts(ctx, pgstore.UnlockAccountLockoutsParams{
+		UserID:     pgtype.UUID{Bytes: userID, Valid: true},
+		UnlockedBy: pgtype.UUID{Bytes: adminID, Valid: true},
+	})
+	if err != nil {
+		return fmt.Errorf("guard.unlock: %v", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("guard.unlock: %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,79 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+func Load() (Mailer, error) {
+	dir := os.Getenv("GOBID_MAIL_DIR")
+	if dir == "" {
+		return NewLogMailer(), nil
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("invalid GOBID_MAIL_DIR: %v", err)
+	}
+
+	return NewFileMailer(dir), nil
+}
+
+type logMailer struct{}
+
+func NewLogMailer() Mailer {
+	return logMailer{}
+}
+
+func (logMailer) Send(ctx context.Context, msg Message) error {
+	logrus.WithFields(logrus.Fields{
+		"to":      msg.To,
+		"subject": msg.Subject,
+		"body":    msg.Body,
+	}).Info("mail sent")
+
+	return nil
+}
+
+type fileMailer struct {
+	dir string
+}
+
+func NewFileMailer(dir string) Mailer {
+	return fileMailer{dir: dir}
+}
+
+func (m fileMailer) Send(ctx context.Context, msg Message) error {
+	now := time.Now().UTC()
+	name := fmt.Sprintf("%s_%s.eml", now.Format("20060102T150405"), uuid.NewString())
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Date: %s\r\n", now.Format(time.RFC1123Z))
+	fmt.Fprintf(&b, "From: gobid <no-reply@gobid.local>\r\n")
+	fmt.Fprintf(&b, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&b, "Subject: %s\r\n", msg.Subject)
+	fmt.Fprintf(&b, "Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	b.WriteString(msg.Body)
+
+	if err := os.WriteFile(filepath.Join(m.dir, name), []byte(b.String()), 0o644); err != nil {
+		return fmt.Errorf("fileMailer.send: %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,171 @@
+package media
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"path"
+
+	"github.com/EduardoMark/gobid/internal/jsonutils"
+	"github.com/EduardoMark/gobid/internal/storage"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+const urlPrefix = "/api/v1/media/"
+
+var (
+	ErrMissingFile   = errors.New("missing upload file")
+	ErrInvalidUpload = errors.New("invalid multipart upload")
+)
+
+var contentTypes = map[string]string{
+	".jpg": "image/jpeg",
+	".png": "image/png",
+}
+
+type MediaHandler struct {
+	store storage.BlobStore
+}
+
+func NewMediaHandler(store storage.BlobStore) MediaHandler {
+	return MediaHandler{store: store}
+}
+
+func (m *MediaHandler) RegisterMediaRoutes(r chi.Router) {
+	r.Get("/media/*", m.Serve)
+}
+
+func (m *MediaHandler) Serve(w http.ResponseWriter, r *http.Request) {
+	key := chi.URLParam(r, "*")
+
+	contentType, ok := contentTypes[path.Ext(key)]
+	if !ok {
+		jsonutils.EncodeJson(w, r, http.StatusNotFound, map[string]any{
+			"error": "not found",
+		})
+		return
+	}
+
+	blob, err := m.store.Get(r.Context(), key)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) || errors.Is(err, storage.ErrInvalidKey) {
+			jsonutils.EncodeJson(w, r, http.StatusNotFound, map[string]any{
+				"error": "not found",
+			})
+			return
+		}
+
+		logrus.WithField("err", err.Error()).Error("Handler.Serve")
+
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "unexpected internal server error",
+		})
+		return
+	}
+	defer blob.Close()
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(http.StatusOK)
+	io.Copy(w, blob)
+}
+
+func URL(key string) string {
+	if key == "" {
+		return ""
+	}
+
+	return urlPrefix + key
+}
+
+func Save(ctx context.Context, store storage.BlobStore, prefix string, img *Image) (string, string, error) {
+	name := uuid.NewString()
+	key := prefix + "/" + name + img.Extension
+	thumbKey := prefix + "/" + name + "_thumb" + img.Extension
+
+	if err := store.Put(ctx, key, bytes.NewReader(img.Data)); err != nil {
+		return "", "", err
+	}
+
+	if err := store.Put(ctx, thumbKey, bytes.NewReader(img.Thumbnail)); err != nil {
+		store.Delete(ctx, key)
+		return "", "", err
+	}
+
+	return key, thumbKey, nil
+}
+
+func Remove(ctx context.Context, store storage.BlobStore, keys ...string) {
+	for _, key := range keys {
+		if key == "" {
+			continue
+		}
+
+		if err := store.Delete(ctx, key); err != nil {
+			logrus.WithFields(logrus.Fields{
+				"err": err.Error(),
+				"key": key,
+			}).Error("media.Remove")
+		}
+	}
+}
+
+func ReadUpload(w http.ResponseWriter, r *http.Request, field string) (*Image, error) {
+	r.Body = http.MaxBytesReader(w, r.Body, MaxUploadSize+1<<20)
+
+	if err := r.ParseMultipartForm(1 << 20); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			return nil, ErrTooLarge
+		}
+		return nil, ErrInvalidUpload
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	file, header, err := r.FormFile(field)
+	if err != nil {
+		return nil, ErrMissingFile
+	}
+	defer file.Close()
+
+	if header.Size > MaxUploadSize {
+		return nil, ErrTooLarge
+	}
+
+	data, err := io.ReadAll(io.LimitReader(file, MaxUploadSize+1))
+	if err != nil {
+		return nil, ErrInvalidUpload
+	}
+
+	return Process(data)
+}
+
+func WriteUploadError(w http.ResponseWriter, r *http.Request, err error) bool {
+	switch {
+	case errors.Is(err, ErrTooLarge):
+		jsonutils.EncodeJson(w, r, http.StatusRequestEntityTooLarge, map[string]any{
+			"error": "image exceeds the upload size limit",
+		})
+	case errors.Is(err, ErrUnsupportedType):
+		jsonutils.EncodeJson(w, r, http.StatusUnsupportedMediaType, map[string]any{
+			"error": "image must be a jpeg, png, gif or webp file",
+		})
+	case errors.Is(err, ErrInvalidImage):
+		jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]any{
+			"error": "image could not be decoded",
+		})
+	case errors.Is(err, ErrMissingFile), errors.Is(err, ErrInvalidUpload):
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "expected a multipart upload with a file field",
+		})
+	default:
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,148 @@
+package money
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type Amount int64
+
+var ErrInvalid = errors.New("invalid money amount")
+
+func Parse(s string) (Amount, error) {
+	raw := s
+	negative := strings.HasPrefix(s, "-")
+	if negative {
+		s = s[1:]
+	}
+
+	whole, frac, hasFrac := strings.Cut(s, ".")
+	if whole == "" || (hasFrac && (frac == "" || len(frac) > 2)) {
+		return 0, fmt.Errorf("%w: %q", ErrInvalid, raw)
+	}
+
+	for len(frac) < 2 {
+		frac += "0"
+	}
+
+	if !digits(whole) || !digits(frac) {
+		return 0, fmt.Errorf("%w: %q", ErrInvalid, raw)
+	}
+
+	cents, err := strconv.ParseInt(whole+frac, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %q", ErrInvalid, raw)
+	}
+
+	if negative {
+		cents = -cents
+	}
+
+	return Amount(cents), nil
+}
+
+func digits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func (a Amount) String() string {
+	sign := ""
+	cents := int64(a)
+	if cents < 0 {
+		sign = "-"
+		cents = -cents
+	}
+
+	return fmt.Sprintf("%s%d.%02d", sign, cents/100, cents%100)
+}
+
+func (a Amount) Percent(rate float64) Amount {
+	r := new(big.Rat).Mul(big.NewRat(int64(a), 100), decimal(rate))
+	return roundRat(r)
+}
+
+func (a Amount) ExcludingPercent(rate float64) Amount {
+	den := new(big.Rat).Add(big.NewRat(100, 1), decimal(rate))
+	r := new(big.Rat).Quo(big.NewRat(int64(a)*100, 1), den)
+	return roundRat(r)
+}
+
+func (a Amount) Prorate(part, whole Amount) Amount {
+	if whole == 0 {
+		return 0
+	}
+
+	num := new(big.Int).Mul(big.NewInt(int64(a)), big.NewInt(int64(part)))
+	return roundRat(new(big.Rat).SetFrac(num, big.NewInt(int64(whole))))
+}
+
+func decimal(rate float64) *big.Rat {
+	r, _ := new(big.Rat).SetString(strconv.FormatFloat(rate, 'f', -1, 64))
+	return r
+}
+
+func roundRat(r *big.Rat) Amount {
+	num := new(big.Int).Abs(r.Num())
+	q, m := new(big.Int).QuoRem(num, r.Denom(), new(big.Int))
+	if m.Lsh(m, 1).Cmp(r.Denom()) >= 0 {
+		q.Add(q, big.NewInt(1))
+	}
+
+	if r.Sign() < 0 {
+		q.Neg(q)
+	}
+
+	return Amount(q.Int64())
+}
+
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	v, err := Parse(strings.Trim(string(data), `"`))
+	if err != nil {
+		return err
+	}
+
+	*a = v
+	return nil
+}
+
+func (a *Amount) ScanNumeric(n pgtype.Numeric) error {
+	if !n.Valid || n.NaN || n.InfinityModifier != pgtype.Finite {
+		return fmt.Errorf("%w: %v", ErrInvalid, n)
+	}
+
+	cents := new(big.Int).Set(n.Int)
+	exp := n.Exp + 2
+	switch {
+	case exp > 0:
+		cents.Mul(cents, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(exp)), nil))
+	case exp < 0:
+		scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(-exp)), nil)
+		*a = roundRat(new(big.Rat).SetFrac(cents, scale))
+		return nil
+	}
+
+	if !cents.IsInt64() {
+		return fmt.Errorf("%w: out of range", ErrInvalid)
+	}
+
+	*a = Amount(cents.Int64())
+	return nil
+}
+
+func (a Amount) NumericValue() (pgtype.Numeric, error) {
+	return pgtype.Numeric{Int: big.NewInt(int64(a)), Exp: -2, Valid: true}, nil
+}
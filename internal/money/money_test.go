@@ -0,0 +1,200 @@
+package money
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Amount
+		wantErr bool
+	}{
+		{in: "0", want: 0},
+		{in: "12", want: 1200},
+		{in: "12.5", want: 1250},
+		{in: "12.50", want: 1250},
+		{in: "0.01", want: 1},
+		{in: "-3.20", want: -320},
+		{in: "0.1", want: 10},
+		{in: "12.505", wantErr: true},
+		{in: "12.", wantErr: true},
+		{in: ".5", wantErr: true},
+		{in: "1e3", wantErr: true},
+		{in: "", wantErr: true},
+		{in: "abc", wantErr: true},
+		{in: "99999999999999999999", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := Parse(tt.in)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalid) {
+					t.Fatalf("Parse(%q) error = %v, want ErrInvalid", tt.in, err)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Fatalf("Parse(%q) = %d, %v, want %d", tt.in, got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestString(t *testing.T) {
+	tests := []struct {
+		in   Amount
+		want string
+	}{
+		{in: 0, want: "0.00"},
+		{in: 1, want: "0.01"},
+		{in: 1250, want: "12.50"},
+		{in: -5, want: "-0.05"},
+		{in: -1234, want: "-12.34"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.in.String(); got != tt.want {
+			t.Errorf("Amount(%d).String() = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestPercent(t *testing.T) {
+	tests := []struct {
+		name   string
+		amount Amount
+		rate   float64
+		want   Amount
+	}{
+		{name: "whole percent", amount: 10000, rate: 10, want: 1000},
+		{name: "half a cent rounds up", amount: 5, rate: 10, want: 1},
+		{name: "below half a cent rounds down", amount: 4, rate: 10, want: 0},
+		{name: "decimal rate is exact", amount: 1000, rate: 0.1, want: 1},
+		{name: "three decimal rate", amount: 1000, rate: 8.875, want: 89},
+		{name: "negative rounds away from zero", amount: -5, rate: 10, want: -1},
+		{name: "zero rate", amount: 1999, rate: 0, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.amount.Percent(tt.rate); got != tt.want {
+				t.Fatalf("Amount(%d).Percent(%v) = %d, want %d", tt.amount, tt.rate, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExcludingPercent(t *testing.T) {
+	tests := []struct {
+		amount Amount
+		rate   float64
+		want   Amount
+	}{
+		{amount: 12000, rate: 20, want: 10000},
+		{amount: 1000, rate: 21, want: 826},
+		{amount: 1060, rate: 6, want: 1000},
+		{amount: 1999, rate: 0, want: 1999},
+	}
+
+	for _, tt := range tests {
+		if got := tt.amount.ExcludingPercent(tt.rate); got != tt.want {
+			t.Errorf("Amount(%d).ExcludingPercent(%v) = %d, want %d", tt.amount, tt.rate, got, tt.want)
+		}
+	}
+}
+
+func TestJSON(t *testing.T) {
+	var body struct {
+		Price Amount `json:"price"`
+	}
+
+	if err := json.Unmarshal([]byte(`{"price": 19.9}`), &body); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if body.Price != 1990 {
+		t.Fatalf("Price = %d, want 1990", body.Price)
+	}
+
+	out, err := json.Marshal(body)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	if string(out) != `{"price":19.90}` {
+		t.Fatalf("Marshal() = %s", out)
+	}
+
+	if err := json.Unmarshal([]byte(`{"price": 0.001}`), &body); err == nil {
+		t.Fatal("Unmarshal() accepted sub-cent precision")
+	}
+}
+
+func TestNumeric(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      pgtype.Numeric
+		want    Amount
+		wantErr bool
+	}{
+		{name: "scale two", in: pgtype.Numeric{Int: big.NewInt(1250), Exp: -2, Valid: true}, want: 1250},
+		{name: "scale zero", in: pgtype.Numeric{Int: big.NewInt(12), Exp: 0, Valid: true}, want: 1200},
+		{name: "positive exponent", in: pgtype.Numeric{Int: big.NewInt(3), Exp: 2, Valid: true}, want: 30000},
+		{name: "extra scale rounds", in: pgtype.Numeric{Int: big.NewInt(12345), Exp: -3, Valid: true}, want: 1235},
+		{name: "negative", in: pgtype.Numeric{Int: big.NewInt(-5), Exp: -1, Valid: true}, want: -50},
+		{name: "null", in: pgtype.Numeric{}, wantErr: true},
+		{name: "nan", in: pgtype.Numeric{NaN: true, Valid: true}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got Amount
+			err := got.ScanNumeric(tt.in)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ScanNumeric() = %d, want an error", got)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Fatalf("ScanNumeric() = %d, %v, want %d", got, err, tt.want)
+			}
+
+			n, err := got.NumericValue()
+			if err != nil {
+				t.Fatalf("NumericValue() error = %v", err)
+			}
+
+			var back Amount
+			if err := back.ScanNumeric(n); err != nil || back != got {
+				t.Fatalf("round trip = %d, %v, want %d", back, err, got)
+			}
+		})
+	}
+}
+
+func TestProrate(t *testing.T) {
+	tests := []struct {
+		name                      string
+		amount, part, whole, want Amount
+	}{
+		{name: "whole", amount: 1000, part: 5000, whole: 5000, want: 1000},
+		{name: "half", amount: 1000, part: 2500, whole: 5000, want: 500},
+		{name: "rounds half up", amount: 1, part: 1, whole: 2, want: 1},
+		{name: "rounds down", amount: 1, part: 1, whole: 3, want: 0},
+		{name: "zero whole", amount: 1000, part: 10, whole: 0, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.amount.Prorate(tt.part, tt.whole); got != tt.want {
+				t.Fatalf("Amount(%d).Prorate(%d, %d) = %d, want %d", tt.amount, tt.part, tt.whole, got, tt.want)
+			}
+		})
+	}
+}
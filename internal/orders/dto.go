@@ -0,0 +1,111 @@
+package orders
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/EduardoMark/gobid/internal/money"
+	"github.com/EduardoMark/gobid/internal/validator"
+	"github.com/google/uuid"
+)
+
+type CreateOrderReq struct {
+	ProductID        string `json:"product_id"`
+	ShippingOptionID string `json:"shipping_option_id"`
+	AddressID        string `json:"address_id"`
+	Region           string `json:"region"`
+}
+
+func (r *CreateOrderReq) Valid(ctx context.Context) validator.Evaluator {
+	var eval validator.Evaluator
+
+	_, err := uuid.Parse(r.ProductID)
+	eval.CheckField(err == nil, "product_id", "this field must be a valid uuid")
+
+	_, err = uuid.Parse(r.ShippingOptionID)
+	eval.CheckField(err == nil, "shipping_option_id", "this field must be a valid uuid")
+
+	if r.AddressID != "" {
+		_, err = uuid.Parse(r.AddressID)
+		eval.CheckField(err == nil, "address_id", "this field must be a valid uuid")
+	}
+
+	eval.CheckField(validator.MaxChars(r.Region, 10), "region", "this field must have at most 10 characters")
+
+	return eval
+}
+
+type OrderResponse struct {
+	ID              uuid.UUID       `json:"id"`
+	ProductID       uuid.UUID       `json:"product_id"`
+	BuyerID         uuid.UUID       `json:"buyer_id"`
+	SellerID        uuid.UUID       `json:"seller_id"`
+	Amount          money.Amount    `json:"amount"`
+	TaxRegion       string          `json:"tax_region"`
+	TaxName         string          `json:"tax_name"`
+	TaxRate         float64         `json:"tax_rate"`
+	TaxInclusive    bool            `json:"tax_inclusive"`
+	TaxAmount       money.Amount    `json:"tax_amount"`
+	TotalAmount     money.Amount    `json:"total_amount"`
+	ShippingKind    string          `json:"shipping_kind"`
+	ShippingCost    money.Amount    `json:"shipping_cost"`
+	ShippingAddress json.RawMessage `json:"shipping_address,omitempty"`
+	Status          string          `json:"status"`
+	ListingFee      money.Amount    `json:"listing_fee"`
+	FinalValueFee   money.Amount    `json:"final_value_fee"`
+	SellerNet       money.Amount    `json:"seller_net"`
+	PaidAt          *time.Time      `json:"paid_at"`
+	CreatedAt       time.Time       `json:"created_at"`
+	UpdatedAt       time.Time       `json:"updated_at"`
+}
+
+type AddShipmentReq struct {
+	Carrier        string `json:"carrier"`
+	TrackingNumber string `json:"tracking_number"`
+}
+
+func (r *AddShipmentReq) Valid(ctx context.Context) validator.Evaluator {
+	var eval validator.Evaluator
+
+	eval.CheckField(validator.NotBlank(r.Carrier), "carrier", "this field cannot be blank")
+	eval.CheckField(validator.MaxChars(r.Carrier, 100), "carrier", "this field must have at most 100 characters")
+	eval.CheckField(validator.NotBlank(r.TrackingNumber), "tracking_number", "this field cannot be blank")
+	eval.CheckField(validator.MaxChars(r.TrackingNumber, 100), "tracking_number", "this field must have at most 100 characters")
+
+	return eval
+}
+
+type UpdateShipmentReq struct {
+	Status string `json:"status"`
+	Note   string `json:"note"`
+}
+
+func (r *UpdateShipmentReq) Valid(ctx context.Context) validator.Evaluator {
+	var eval validator.Evaluator
+
+	eval.CheckField(
+		r.Status == "in_transit" || r.Status == "delivered" || r.Status == "returned",
+		"status", "must be one of in_transit, delivered, returned",
+	)
+	eval.CheckField(validator.MaxChars(r.Note, 255), "note", "this field must have at most 255 characters")
+
+	return eval
+}
+
+type ShipmentEventResponse struct {
+	Status    string    `json:"status"`
+	Note      string    `json:"note"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type ShipmentResponse struct {
+	ID             uuid.UUID               `json:"id"`
+	OrderID        uuid.UUID               `json:"order_id"`
+	Carrier        string                  `json:"carrier"`
+	TrackingNumber string                  `json:"tracking_number"`
+	Status         string                  `json:"status"`
+	CreatedAt      time.Time               `json:"created_at"`
+	UpdatedAt      time.Time               `json:"updated_at"`
+	Events         []ShipmentEventResponse `json:"events,omitempty"`
+}
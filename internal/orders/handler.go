@@ -0,0 +1,582 @@
+package orders
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/EduardoMark/gobid/internal/api/middlewares"
+	"github.com/EduardoMark/gobid/internal/auth/session"
+	"github.com/EduardoMark/gobid/internal/auth/token"
+	"github.com/EduardoMark/gobid/internal/jsonutils"
+	"github.com/EduardoMark/gobid/internal/rbac"
+	"github.com/EduardoMark/gobid/internal/store/pgstore"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+type OrderHandler struct {
+	svc        Service
+	jwtService token.JwtService
+	sessions   session.Checker
+	verified   middlewares.EmailVerificationChecker
+}
+
+func NewOrderHandler(svc Service, jwtService token.JwtService, sessions session.Checker, verified middlewares.EmailVerificationChecker) OrderHandler {
+	return OrderHandler{
+		svc:        svc,
+		jwtService: jwtService,
+		sessions:   sessions,
+		verified:   verified,
+	}
+}
+
+func (m *OrderHandler) RegisterOrderRoutes(r chi.Router) {
+	r.Route("/orders", func(r chi.Router) {
+		r.Group(func(r chi.Router) {
+			r.Use(middlewares.AuthToken(m.jwtService, m.sessions))
+
+			r.With(middlewares.RequirePermission(rbac.PermOrdersCreate), middlewares.RequireVerifiedEmail(m.verified)).Post("/", m.Create)
+			r.Get("/{id}", m.GetOne)
+			r.Post("/{id}/pay", m.Pay)
+			r.Get("/{id}/invoice", m.GetInvoice)
+			r.Get("/{id}/shipment", m.GetShipment)
+			r.Post("/{id}/shipment", m.AddShipment)
+			r.Patch("/{id}/shipment", m.UpdateShipment)
+		})
+	})
+}
+
+func (m *OrderHandler) Create(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, ok := ctx.Value(middlewares.UserIDKey).(string)
+	if !ok {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "user ID not found in context",
+		})
+		return
+	}
+
+	buyerID, err := uuid.Parse(id)
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "invalid user ID format",
+		})
+		return
+	}
+
+	data, problems, err := jsonutils.DecodeValidJson[*CreateOrderReq](r)
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+
+	var addressID uuid.UUID
+	if data.AddressID != "" {
+		addressID = uuid.MustParse(data.AddressID)
+	}
+
+	orderID, err := m.svc.Create(
+		ctx,
+		buyerID,
+		uuid.MustParse(data.ProductID),
+		uuid.MustParse(data.ShippingOptionID),
+		addressID,
+		strings.ToUpper(data.Region),
+	)
+	if err != nil {
+		if errors.Is(err, ErrProductNotFound) {
+			jsonutils.EncodeJson(w, r, http.StatusNotFound, map[string]any{
+				"error": "product not found",
+			})
+			return
+		}
+
+		if errors.Is(err, ErrProductUnavailable) {
+			jsonutils.EncodeJson(w, r, http.StatusConflict, map[string]any{
+				"error": "product is no longer available",
+			})
+			return
+		}
+
+		if errors.Is(err, ErrOwnProduct) {
+			jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+				"error": "you cannot buy your own product",
+			})
+			return
+		}
+
+		if errors.Is(err, ErrInvalidShippingOption) {
+			jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+				"error": "shipping option does not belong to this product",
+			})
+			return
+		}
+
+		if errors.Is(err, ErrAddressRequired) {
+			jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+				"error": "a shipping address is required for this shipping option",
+			})
+			return
+		}
+
+		if errors.Is(err, ErrAddressNotFound) {
+			jsonutils.EncodeJson(w, r, http.StatusNotFound, map[string]any{
+				"error": "address not found",
+			})
+			return
+		}
+
+		if errors.Is(err, ErrRegionRequired) {
+			jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+				"error": "region is required for pickup orders without an address",
+			})
+			return
+		}
+
+		if errors.Is(err, ErrShippingUnavailable) {
+			jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+				"error": "shipping option is not available for this region",
+			})
+			return
+		}
+
+		if errors.Is(err, ErrTaxUnavailable) {
+			jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]any{
+				"error": "orders are not available for this region yet",
+			})
+			return
+		}
+
+		logrus.WithField("err", err.Error()).Error("Handler.Create")
+
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "unexpected internal server error",
+		})
+		return
+	}
+
+	jsonutils.EncodeJson(w, r, http.StatusCreated, map[string]any{
+		"id": orderID,
+	})
+}
+
+func (m *OrderHandler) GetOne(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, ok := ctx.Value(middlewares.UserIDKey).(string)
+	if !ok {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "user ID not found in context",
+		})
+		return
+	}
+
+	parsedID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "invalid order ID format",
+		})
+		return
+	}
+
+	record, err := m.svc.GetOrderByID(ctx, parsedID)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			jsonutils.EncodeJson(w, r, http.StatusNotFound, map[string]any{
+				"error": "order not found",
+			})
+			return
+		}
+
+		logrus.WithField("err", err.Error()).Error("Handler.GetOne")
+
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "unexpected internal server error",
+		})
+		return
+	}
+
+	if record.BuyerID.String() != userID && record.SellerID.String() != userID {
+		jsonutils.EncodeJson(w, r, http.StatusNotFound, map[string]any{
+			"error": "order not found",
+		})
+		return
+	}
+
+	jsonutils.EncodeJson(w, r, http.StatusOK, map[string]any{
+		"order": toOrderResponse(record),
+	})
+}
+
+func (m *OrderHandler) Pay(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, ok := ctx.Value(middlewares.UserIDKey).(string)
+	if !ok {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "user ID not found in context",
+		})
+		return
+	}
+
+	buyerID, err := uuid.Parse(id)
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "invalid user ID format",
+		})
+		return
+	}
+
+	parsedID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "invalid order ID format",
+		})
+		return
+	}
+
+	record, err := m.svc.Pay(ctx, parsedID, buyerID)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) || errors.Is(err, ErrForbidden) {
+			jsonutils.EncodeJson(w, r, http.StatusNotFound, map[string]any{
+				"error": "order not found",
+			})
+			return
+		}
+
+		if errors.Is(err, ErrNotPending) {
+			jsonutils.EncodeJson(w, r, http.StatusConflict, map[string]any{
+				"error": "order is not pending payment",
+			})
+			return
+		}
+
+		if errors.Is(err, ErrProductUnavailable) {
+			jsonutils.EncodeJson(w, r, http.StatusConflict, map[string]any{
+				"error": "product is no longer available",
+			})
+			return
+		}
+
+		logrus.WithField("err", err.Error()).Error("Handler.Pay")
+
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "unexpected internal server error",
+		})
+		return
+	}
+
+	jsonutils.EncodeJson(w, r, http.StatusOK, map[string]any{
+		"order": toOrderResponse(record),
+	})
+}
+
+func (m *OrderHandler) GetInvoice(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, ok := ctx.Value(middlewares.UserIDKey).(string)
+	if !ok {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "user ID not found in context",
+		})
+		return
+	}
+
+	parsedID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "invalid order ID format",
+		})
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = "pdf"
+	}
+
+	if format != "pdf" && format != "html" {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "format must be pdf or html",
+		})
+		return
+	}
+
+	order, err := m.svc.GetOrderByID(ctx, parsedID)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		logrus.WithField("err", err.Error()).Error("Handler.GetInvoice")
+
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "unexpected internal server error",
+		})
+		return
+	}
+
+	if err != nil || (order.BuyerID.String() != userID && order.SellerID.String() != userID) {
+		jsonutils.EncodeJson(w, r, http.StatusNotFound, map[string]any{
+			"error": "order not found",
+		})
+		return
+	}
+
+	invoice, err := m.svc.GetInvoice(ctx, parsedID)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			jsonutils.EncodeJson(w, r, http.StatusNotFound, map[string]any{
+				"error": "invoice not found",
+			})
+			return
+		}
+
+		logrus.WithField("err", err.Error()).Error("Handler.GetInvoice")
+
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "unexpected internal server error",
+		})
+		return
+	}
+
+	filename := fmt.Sprintf("invoice-%08d.%s", invoice.Number, format)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+
+	if format == "html" {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(invoice.Html))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/pdf")
+	w.WriteHeader(http.StatusOK)
+	w.Write(invoice.Pdf)
+}
+
+func (m *OrderHandler) AddShipment(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, ok := ctx.Value(middlewares.UserIDKey).(string)
+	if !ok {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "user ID not found in context",
+		})
+		return
+	}
+
+	sellerID, err := uuid.Parse(id)
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "invalid user ID format",
+		})
+		return
+	}
+
+	parsedID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "invalid order ID format",
+		})
+		return
+	}
+
+	data, problems, err := jsonutils.DecodeValidJson[*AddShipmentReq](r)
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+
+	record, err := m.svc.AddShipment(ctx, parsedID, sellerID, data.Carrier, data.TrackingNumber)
+	if err != nil {
+		m.writeShipmentError(w, r, err, "Handler.AddShipment")
+		return
+	}
+
+	jsonutils.EncodeJson(w, r, http.StatusCreated, map[string]any{
+		"shipment": toShipmentResponse(record),
+	})
+}
+
+func (m *OrderHandler) UpdateShipment(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, ok := ctx.Value(middlewares.UserIDKey).(string)
+	if !ok {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "user ID not found in context",
+		})
+		return
+	}
+
+	sellerID, err := uuid.Parse(id)
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "invalid user ID format",
+		})
+		return
+	}
+
+	parsedID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "invalid order ID format",
+		})
+		return
+	}
+
+	data, problems, err := jsonutils.DecodeValidJson[*UpdateShipmentReq](r)
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+
+	record, err := m.svc.UpdateShipmentStatus(ctx, parsedID, sellerID, data.Status, data.Note)
+	if err != nil {
+		m.writeShipmentError(w, r, err, "Handler.UpdateShipment")
+		return
+	}
+
+	jsonutils.EncodeJson(w, r, http.StatusOK, map[string]any{
+		"shipment": toShipmentResponse(record),
+	})
+}
+
+func (m *OrderHandler) GetShipment(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, ok := ctx.Value(middlewares.UserIDKey).(string)
+	if !ok {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "user ID not found in context",
+		})
+		return
+	}
+
+	parsedID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "invalid order ID format",
+		})
+		return
+	}
+
+	order, err := m.svc.GetOrderByID(ctx, parsedID)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		logrus.WithField("err", err.Error()).Error("Handler.GetShipment")
+
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "unexpected internal server error",
+		})
+		return
+	}
+
+	if err != nil || (order.BuyerID.String() != userID && order.SellerID.String() != userID) {
+		jsonutils.EncodeJson(w, r, http.StatusNotFound, map[string]any{
+			"error": "order not found",
+		})
+		return
+	}
+
+	record, events, err := m.svc.GetShipment(ctx, parsedID)
+	if err != nil {
+		m.writeShipmentError(w, r, err, "Handler.GetShipment")
+		return
+	}
+
+	res := toShipmentResponse(record)
+	res.Events = make([]ShipmentEventResponse, len(events))
+	for i, event := range events {
+		res.Events[i] = ShipmentEventResponse{
+			Status:    event.Status,
+			Note:      event.Note,
+			CreatedAt: event.CreatedAt,
+		}
+	}
+
+	jsonutils.EncodeJson(w, r, http.StatusOK, map[string]any{
+		"shipment": res,
+	})
+}
+
+func (m *OrderHandler) writeShipmentError(w http.ResponseWriter, r *http.Request, err error, op string) {
+	switch {
+	case errors.Is(err, ErrNotFound), errors.Is(err, ErrForbidden):
+		jsonutils.EncodeJson(w, r, http.StatusNotFound, map[string]any{
+			"error": "order not found",
+		})
+	case errors.Is(err, ErrShipmentNotFound):
+		jsonutils.EncodeJson(w, r, http.StatusNotFound, map[string]any{
+			"error": "shipment not found",
+		})
+	case errors.Is(err, ErrNotPaid):
+		jsonutils.EncodeJson(w, r, http.StatusConflict, map[string]any{
+			"error": "order has not been paid",
+		})
+	case errors.Is(err, ErrPickupOrder):
+		jsonutils.EncodeJson(w, r, http.StatusConflict, map[string]any{
+			"error": "local pickup orders are not shipped",
+		})
+	case errors.Is(err, ErrShipmentExists):
+		jsonutils.EncodeJson(w, r, http.StatusConflict, map[string]any{
+			"error": "shipment already exists for this order",
+		})
+	case errors.Is(err, ErrShipmentClosed):
+		jsonutils.EncodeJson(w, r, http.StatusConflict, map[string]any{
+			"error": "shipment is already delivered or returned",
+		})
+	default:
+		logrus.WithField("err", err.Error()).Error(op)
+
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "unexpected internal server error",
+		})
+	}
+}
+
+func toShipmentResponse(record *pgstore.Shipment) ShipmentResponse {
+	return ShipmentResponse{
+		ID:             record.ID,
+		OrderID:        record.OrderID,
+		Carrier:        record.Carrier,
+		TrackingNumber: record.TrackingNumber,
+		Status:         record.Status,
+		CreatedAt:      record.CreatedAt,
+		UpdatedAt:      record.UpdatedAt,
+	}
+}
+
+func toOrderResponse(record *pgstore.Order) OrderResponse {
+	res := OrderResponse{
+		ID:            record.ID,
+		ProductID:     record.ProductID,
+		BuyerID:       record.BuyerID,
+		SellerID:      record.SellerID,
+		Amount:        record.Amount,
+		TaxRegion:     record.TaxRegion,
+		TaxName:       record.TaxName,
+		TaxRate:       record.TaxRate,
+		TaxInclusive:  record.TaxInclusive,
+		TaxAmount:     record.TaxAmount,
+		TotalAmount:   record.TotalAmount,
+		ShippingKind:  record.ShippingKind,
+		ShippingCost:  record.ShippingCost,
+		Status:        record.Status,
+		ListingFee:    record.ListingFee,
+		FinalValueFee: record.FinalValueFee,
+		SellerNet:     record.SellerNet,
+		CreatedAt:     record.CreatedAt,
+		UpdatedAt:     record.UpdatedAt,
+	}
+
+	if record.PaidAt.Valid {
+		res.PaidAt = &record.PaidAt.Time
+	}
+
+	if record.ShippingAddress != nil {
+		res.ShippingAddress = record.ShippingAddress
+	}
+
+	return res
+}
@@ -0,0 +1,419 @@
+package orders
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/EduardoMark/gobid/internal/audit"
+	"github.com/EduardoMark/gobid/internal/fees"
+	"github.com/EduardoMark/gobid/internal/invoices"
+	"github.com/EduardoMark/gobid/internal/store/pgstore"
+	"github.com/EduardoMark/gobid/internal/tax"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/sirupsen/logrus"
+)
+
+type Service interface {
+	Create(ctx context.Context, buyerID, productID, shippingOptionID, addressID uuid.UUID, region string) (uuid.UUID, error)
+	GetOrderByID(ctx context.Context, id uuid.UUID) (*pgstore.Order, error)
+	Pay(ctx context.Context, id, buyerID uuid.UUID) (*pgstore.Order, error)
+	GetInvoice(ctx context.Context, orderID uuid.UUID) (*pgstore.Invoice, error)
+	AddShipment(ctx context.Context, orderID, sellerID uuid.UUID, carrier, trackingNumber string) (*pgstore.Shipment, error)
+	UpdateShipmentStatus(ctx context.Context, orderID, sellerID uuid.UUID, status, note string) (*pgstore.Shipment, error)
+	GetShipment(ctx context.Context, orderID uuid.UUID) (*pgstore.Shipment, []*pgstore.ShipmentEvent, error)
+}
+
+type ShippingAddress struct {
+	Name       string `json:"name"`
+	Line1      string `json:"line1"`
+	Line2      string `json:"line2"`
+	City       string `json:"city"`
+	PostalCode string `json:"postal_code"`
+	Region     string `json:"region"`
+}
+
+type orderService struct {
+	pool  *pgxpool.Pool
+	q     *pgstore.Queries
+	fees  fees.Schedule
+	tax   tax.Calculator
+	audit audit.Recorder
+}
+
+var ErrNotFound = errors.New("not found")
+var ErrProductNotFound = errors.New("product not found")
+var ErrProductUnavailable = errors.New("product unavailable")
+var ErrOwnProduct = errors.New("cannot buy own product")
+var ErrForbidden = errors.New("forbidden")
+var ErrNotPending = errors.New("order is not pending")
+var ErrNotPaid = errors.New("order is not paid")
+var ErrInvalidShippingOption = errors.New("invalid shipping option")
+var ErrAddressRequired = errors.New("shipping address required")
+var ErrAddressNotFound = errors.New("address not found")
+var ErrRegionRequired = errors.New("region required")
+var ErrShippingUnavailable = errors.New("shipping unavailable for region")
+var ErrTaxUnavailable = errors.New("no tax rule for region")
+var ErrPickupOrder = errors.New("pickup orders are not shipped")
+var ErrShipmentNotFound = errors.New("shipment not found")
+var ErrShipmentExists = errors.New("shipment already exists")
+var ErrShipmentClosed = errors.New("shipment already closed")
+
+func NewOrderService(pool *pgxpool.Pool, schedule fees.Schedule, recorder audit.Recorder) Service {
+	q := pgstore.New(pool)
+
+	return &orderService{
+		pool:  pool,
+		q:     q,
+		fees:  schedule,
+		tax:   tax.NewCalculator(q),
+		audit: recorder,
+	}
+}
+
+func (s *orderService) Create(ctx context.Context, buyerID, productID, shippingOptionID, addressID uuid.UUID, region string) (uuid.UUID, error) {
+	product, err := s.q.GetOneProductByID(ctx, productID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return uuid.UUID{}, ErrProductNotFound
+		}
+		return uuid.UUID{}, fmt.Errorf("service.create: %v", err)
+	}
+
+	if product.IsSold || product.TakenDownAt.Valid || product.FrozenAt.Valid {
+		return uuid.UUID{}, ErrProductUnavailable
+	}
+
+	if product.SellerID == buyerID {
+		return uuid.UUID{}, ErrOwnProduct
+	}
+
+	option, err := s.q.GetShippingOptionByID(ctx, shippingOptionID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return uuid.UUID{}, ErrInvalidShippingOption
+		}
+		return uuid.UUID{}, fmt.Errorf("service.create: %v", err)
+	}
+
+	if option.ProductID != product.ID {
+		return uuid.UUID{}, ErrInvalidShippingOption
+	}
+
+	var shippingAddress []byte
+	if addressID != uuid.Nil {
+		address, err := s.q.GetAddressByID(ctx, addressID)
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return uuid.UUID{}, fmt.Errorf("service.create: %v", err)
+		}
+
+		if err != nil || address.UserID != buyerID {
+			return uuid.UUID{}, ErrAddressNotFound
+		}
+
+		region = address.Region
+		shippingAddress, err = json.Marshal(ShippingAddress{
+			Name:       address.Name,
+			Line1:      address.Line1,
+			Line2:      address.Line2,
+			City:       address.City,
+			PostalCode: address.PostalCode,
+			Region:     address.Region,
+		})
+		if err != nil {
+			return uuid.UUID{}, fmt.Errorf("service.create: %v", err)
+		}
+	} else if option.Kind != "pickup" {
+		return uuid.UUID{}, ErrAddressRequired
+	}
+
+	if region == "" {
+		return uuid.UUID{}, ErrRegionRequired
+	}
+
+	if option.Kind == "region" && option.Region != region {
+		return uuid.UUID{}, ErrShippingUnavailable
+	}
+
+	itemTax, err := s.tax.Calculate(ctx, region, product.Category, product.BasePrice)
+	if err != nil {
+		if errors.Is(err, tax.ErrNoRule) {
+			logrus.WithField("err", err.Error()).Warn("CreateOrder - no tax rule")
+			return uuid.UUID{}, ErrTaxUnavailable
+		}
+		return uuid.UUID{}, fmt.Errorf("service.create: %v", err)
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return uuid.UUID{}, fmt.Errorf("service.create: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	id, err := s.q.WithTx(tx).CreateOrder(ctx, pgstore.CreateOrderParams{
+		ProductID:       product.ID,
+		BuyerID:         buyerID,
+		SellerID:        product.SellerID,
+		Amount:          product.BasePrice,
+		TaxRegion:       region,
+		TaxName:         itemTax.Name,
+		TaxRate:         itemTax.Rate,
+		TaxInclusive:    itemTax.Inclusive,
+		TaxAmount:       itemTax.Tax,
+		TotalAmount:     itemTax.Total + option.Cost,
+		ShippingKind:    option.Kind,
+		ShippingCost:    option.Cost,
+		ShippingAddress: shippingAddress,
+	})
+	if err != nil {
+		logrus.WithField("err", err.Error()).Error("CreateOrder")
+		return uuid.UUID{}, fmt.Errorf("service.create: %v", err)
+	}
+
+	err = s.audit.WithTx(tx).Record(ctx, audit.Event{
+		ActorID:    buyerID,
+		Action:     audit.ActionOrderCreate,
+		TargetType: audit.TargetOrder,
+		TargetID:   id,
+		After: map[string]any{
+			"product_id":   product.ID,
+			"status":       "pending",
+			"total_amount": itemTax.Total + option.Cost,
+		},
+	})
+	if err != nil {
+		return uuid.UUID{}, fmt.Errorf("service.create: %v", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return uuid.UUID{}, fmt.Errorf("service.create: %v", err)
+	}
+
+	return id, nil
+}
+
+func (s *orderService) GetOrderByID(ctx context.Context, id uuid.UUID) (*pgstore.Order, error) {
+	record, err := s.q.GetOrderByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("service.getOrderByID: %v", err)
+	}
+
+	return record, nil
+}
+
+func (s *orderService) Pay(ctx context.Context, id, buyerID uuid.UUID) (*pgstore.Order, error) {
+	order, err := s.GetOrderByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if order.BuyerID != buyerID {
+		return nil, ErrForbidden
+	}
+
+	if order.Status != "pending" {
+		return nil, ErrNotPending
+	}
+
+	breakdown := s.fees.Apply(order.TotalAmount - order.TaxAmount - order.ShippingCost)
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("service.pay: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.q.WithTx(tx)
+
+	if _, err := qtx.MarkProductSold(ctx, order.ProductID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrProductUnavailable
+		}
+		return nil, fmt.Errorf("service.pay: %v", err)
+	}
+
+	record, err := qtx.MarkOrderPaid(ctx, pgstore.MarkOrderPaidParams{
+		ID:            order.ID,
+		ListingFee:    breakdown.ListingFee,
+		FinalValueFee: breakdown.FinalValueFee,
+		SellerNet:     breakdown.SellerNet + order.ShippingCost,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotPending
+		}
+		return nil, fmt.Errorf("service.pay: %v", err)
+	}
+
+	if err := invoices.Issue(ctx, qtx, record); err != nil {
+		return nil, fmt.Errorf("service.pay: %v", err)
+	}
+
+	err = s.audit.WithTx(tx).Record(ctx, audit.Event{
+		ActorID:    buyerID,
+		Action:     audit.ActionOrderPay,
+		TargetType: audit.TargetOrder,
+		TargetID:   order.ID,
+		Before:     map[string]any{"status": order.Status},
+		After: map[string]any{
+			"status":          record.Status,
+			"total_amount":    record.TotalAmount,
+			"listing_fee":     record.ListingFee,
+			"final_value_fee": record.FinalValueFee,
+			"seller_net":      record.SellerNet,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("service.pay: %v", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("service.pay: %v", err)
+	}
+
+	return record, nil
+}
+
+func (s *orderService) GetInvoice(ctx context.Context, orderID uuid.UUID) (*pgstore.Invoice, error) {
+	record, err := s.q.GetInvoiceByOrderID(ctx, orderID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("service.getInvoice: %v", err)
+	}
+
+	return record, nil
+}
+
+func (s *orderService) AddShipment(ctx context.Context, orderID, sellerID uuid.UUID, carrier, trackingNumber string) (*pgstore.Shipment, error) {
+	order, err := s.GetOrderByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	if order.SellerID != sellerID {
+		return nil, ErrForbidden
+	}
+
+	if order.Status != "paid" {
+		return nil, ErrNotPaid
+	}
+
+	if order.ShippingKind == "pickup" {
+		return nil, ErrPickupOrder
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("service.addShipment: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.q.WithTx(tx)
+
+	record, err := qtx.CreateShipment(ctx, pgstore.CreateShipmentParams{
+		OrderID:        orderID,
+		Carrier:        carrier,
+		TrackingNumber: trackingNumber,
+	})
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return nil, ErrShipmentExists
+		}
+		return nil, fmt.Errorf("service.addShipment: %v", err)
+	}
+
+	err = qtx.CreateShipmentEvent(ctx, pgstore.CreateShipmentEventParams{
+		ShipmentID: record.ID,
+		Status:     record.Status,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("service.addShipment: %v", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("service.addShipment: %v", err)
+	}
+
+	return record, nil
+}
+
+func (s *orderService) UpdateShipmentStatus(ctx context.Context, orderID, sellerID uuid.UUID, status, note string) (*pgstore.Shipment, error) {
+	order, err := s.GetOrderByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	if order.SellerID != sellerID {
+		return nil, ErrForbidden
+	}
+
+	shipment, err := s.q.GetShipmentByOrderID(ctx, orderID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrShipmentNotFound
+		}
+		return nil, fmt.Errorf("service.updateShipmentStatus: %v", err)
+	}
+
+	if shipment.Status == "delivered" || shipment.Status == "returned" {
+		return nil, ErrShipmentClosed
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("service.updateShipmentStatus: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.q.WithTx(tx)
+
+	record, err := qtx.UpdateShipmentStatus(ctx, pgstore.UpdateShipmentStatusParams{
+		ID:     shipment.ID,
+		Status: status,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("service.updateShipmentStatus: %v", err)
+	}
+
+	err = qtx.CreateShipmentEvent(ctx, pgstore.CreateShipmentEventParams{
+		ShipmentID: shipment.ID,
+		Status:     status,
+		Note:       note,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("service.updateShipmentStatus: %v", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("service.updateShipmentStatus: %v", err)
+	}
+
+	return record, nil
+}
+
+func (s *orderService) GetShipment(ctx context.Context, orderID uuid.UUID) (*pgstore.Shipment, []*pgstore.ShipmentEvent, error) {
+	record, err := s.q.GetShipmentByOrderID(ctx, orderID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil, ErrShipmentNotFound
+		}
+		return nil, nil, fmt.Errorf("service.getShipment: %v", err)
+	}
+
+	events, err := s.q.ListShipmentEvents(ctx, record.ID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("service.getShipment: %v", err)
+	}
+
+	return record, events, nil
+}
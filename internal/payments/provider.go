@@ -0,0 +1,32 @@
+package payments
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/EduardoMark/gobid/internal/money"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+type Provider interface {
+	Refund(ctx context.Context, idempotencyKey string, orderID uuid.UUID, amount money.Amount) (string, error)
+}
+
+type logProvider struct{}
+
+func NewLogProvider() Provider {
+	return logProvider{}
+}
+
+func (logProvider) Refund(ctx context.Context, idempotencyKey string, orderID uuid.UUID, amount money.Amount) (string, error) {
+	reference := fmt.Sprintf("refund_%s", idempotencyKey)
+
+	logrus.WithFields(logrus.Fields{
+		"order_id":  orderID,
+		"amount":    amount.String(),
+		"reference": reference,
+	}).Info("Refund issued")
+
+	return reference, nil
+}
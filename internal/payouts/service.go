@@ -0,0 +1,197 @@
+package payouts
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/EduardoMark/gobid/internal/store/pgstore"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/sirupsen/logrus"
+)
+
+type Service interface {
+	RunBatch(ctx context.Context, w io.Writer) (int, error)
+}
+
+type payoutService struct {
+	pool *pgxpool.Pool
+	q    *pgstore.Queries
+}
+
+func NewPayoutService(pool *pgxpool.Pool) Service {
+	return &payoutService{
+		pool: pool,
+		q:    pgstore.New(pool),
+	}
+}
+
+var ErrBatchRunning = errors.New("another payout batch is running")
+
+var csvHeader = []string{
+	"payout_id", "seller_id", "username", "email",
+	"orders", "gross", "fees", "adjustments", "net",
+}
+
+func (s *payoutService) RunBatch(ctx context.Context, w io.Writer) (int, error) {
+	conn, err := s.pool.Acquire(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("service.runBatch: %v", err)
+	}
+	defer conn.Release()
+
+	lock := pgstore.New(conn)
+
+	locked, err := lock.TryLockPayoutBatch(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("service.runBatch: %v", err)
+	}
+
+	if !locked {
+		return 0, ErrBatchRunning
+	}
+
+	defer func() {
+		if _, err := lock.UnlockPayoutBatch(context.WithoutCancel(ctx)); err != nil {
+			logrus.WithField("err", err.Error()).Error("RunBatch - failed to release the batch lock")
+		}
+	}()
+
+	return s.runBatch(ctx, w)
+}
+
+func (s *payoutService) runBatch(ctx context.Context, w io.Writer) (int, error) {
+	sellerIDs, err := s.q.ListSellersWithPendingPayouts(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("service.runBatch: %v", err)
+	}
+
+	for _, sellerID := range sellerIDs {
+		if err := s.createPayout(ctx, sellerID); err != nil {
+			return 0, err
+		}
+	}
+
+	payoutIDs, err := s.q.ListUnexportedPayoutIDs(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("service.runBatch: %v", err)
+	}
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return 0, fmt.Errorf("service.runBatch: %v", err)
+	}
+
+	for _, payoutID := range payoutIDs {
+		summary, err := s.q.SummarizePayout(ctx, payoutID)
+		if err != nil {
+			return 0, fmt.Errorf("service.runBatch: %v", err)
+		}
+
+		err = cw.Write([]string{
+			summary.ID.String(),
+			summary.SellerID.String(),
+			summary.Username,
+			summary.Email,
+			strconv.FormatInt(summary.OrderCount, 10),
+			summary.Gross.String(),
+			summary.Fees.String(),
+			summary.Adjustments.String(),
+			summary.Net.String(),
+		})
+		if err != nil {
+			return 0, fmt.Errorf("service.runBatch: %v", err)
+		}
+	}
+
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return 0, fmt.Errorf("service.runBatch: %v", err)
+	}
+
+	if f, ok := w.(interface{ Sync() error }); ok {
+		if err := f.Sync(); err != nil {
+			return 0, fmt.Errorf("service.runBatch: %v", err)
+		}
+	}
+
+	if len(payoutIDs) > 0 {
+		if err := s.q.MarkPayoutsExported(ctx, payoutIDs); err != nil {
+			return 0, fmt.Errorf("service.runBatch: %v", err)
+		}
+	}
+
+	return len(payoutIDs), nil
+}
+
+func (s *payoutService) createPayout(ctx context.Context, sellerID uuid.UUID) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("service.createPayout: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.q.WithTx(tx)
+
+	payoutID, err := qtx.CreatePayout(ctx, sellerID)
+	if err != nil {
+		return fmt.Errorf("service.createPayout: %v", err)
+	}
+
+	if err := qtx.AddPendingOrdersToPayout(ctx, payoutID); err != nil {
+		return fmt.Errorf("service.createPayout: %v", err)
+	}
+
+	if err := qtx.AddPendingAdjustmentsToPayout(ctx, payoutID); err != nil {
+		return fmt.Errorf("service.createPayout: %v", err)
+	}
+
+	summary, err := qtx.SummarizePayout(ctx, payoutID)
+	if err != nil {
+		return fmt.Errorf("service.createPayout: %v", err)
+	}
+
+	if summary.Net < 0 {
+		if err := carryForward(ctx, qtx, summary); err != nil {
+			return fmt.Errorf("service.createPayout: %v", err)
+		}
+		summary.Net = 0
+	}
+
+	err = qtx.SetPayoutAmount(ctx, pgstore.SetPayoutAmountParams{
+		ID:     payoutID,
+		Amount: summary.Net,
+	})
+	if err != nil {
+		return fmt.Errorf("service.createPayout: %v", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("service.createPayout: %v", err)
+	}
+
+	return nil
+}
+
+func carryForward(ctx context.Context, qtx *pgstore.Queries, summary *pgstore.SummarizePayoutRow) error {
+	err := qtx.CreateSellerAdjustment(ctx, pgstore.CreateSellerAdjustmentParams{
+		SellerID: summary.SellerID,
+		Kind:     "carry_forward",
+		Amount:   -summary.Net,
+		PayoutID: pgtype.UUID{Bytes: summary.ID, Valid: true},
+	})
+	if err != nil {
+		return err
+	}
+
+	return qtx.CreateSellerAdjustment(ctx, pgstore.CreateSellerAdjustmentParams{
+		SellerID: summary.SellerID,
+		Kind:     "carry_forward",
+		Amount:   summary.Net,
+	})
+}
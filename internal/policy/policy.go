@@ -0,0 +1,57 @@
+package policy
+
+import (
+	"context"
+	"errors"
+
+	"github.com/EduardoMark/gobid/internal/api/middlewares"
+	"github.com/EduardoMark/gobid/internal/auth/token"
+	"github.com/EduardoMark/gobid/internal/rbac"
+	"github.com/google/uuid"
+)
+
+var ErrUnauthenticated = errors.New("unauthenticated")
+var ErrForbidden = errors.New("forbidden")
+
+type Policy interface {
+	Subject(ctx context.Context) (uuid.UUID, error)
+	AuthorizeOwner(ctx context.Context, owner uuid.UUID) error
+}
+
+type ownership struct{}
+
+func NewOwnershipPolicy() Policy {
+	return ownership{}
+}
+
+func (p ownership) Subject(ctx context.Context) (uuid.UUID, error) {
+	id, ok := ctx.Value(middlewares.UserIDKey).(string)
+	if !ok {
+		return uuid.UUID{}, ErrUnauthenticated
+	}
+
+	parsedID, err := uuid.Parse(id)
+	if err != nil {
+		return uuid.UUID{}, ErrUnauthenticated
+	}
+
+	return parsedID, nil
+}
+
+func (p ownership) AuthorizeOwner(ctx context.Context, owner uuid.UUID) error {
+	subject, err := p.Subject(ctx)
+	if err != nil {
+		return err
+	}
+
+	if subject == owner {
+		return nil
+	}
+
+	claims, ok := ctx.Value(middlewares.ClaimsKey).(*token.Claims)
+	if !ok || !claims.HasPermission(rbac.PermUsersManage) {
+		return ErrForbidden
+	}
+
+	return nil
+}
@@ -0,0 +1,48 @@
+package policy
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/EduardoMark/gobid/internal/api/middlewares"
+	"github.com/EduardoMark/gobid/internal/auth/token"
+	"github.com/EduardoMark/gobid/internal/rbac"
+	"github.com/google/uuid"
+)
+
+func TestAuthorizeOwner(t *testing.T) {
+	owner := uuid.New()
+	stranger := uuid.New()
+
+	tests := []struct {
+		name    string
+		subject string
+		claims  *token.Claims
+		want    error
+	}{
+		{name: "owner", subject: owner.String(), want: nil},
+		{name: "stranger", subject: stranger.String(), want: ErrForbidden},
+		{name: "stranger without manage permission", subject: stranger.String(), claims: &token.Claims{Permissions: []string{rbac.PermListingsCreate}}, want: ErrForbidden},
+		{name: "admin", subject: stranger.String(), claims: &token.Claims{Permissions: []string{rbac.PermUsersManage}}, want: nil},
+		{name: "no subject", want: ErrUnauthenticated},
+		{name: "malformed subject", subject: "not-a-uuid", want: ErrUnauthenticated},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.subject != "" {
+				ctx = context.WithValue(ctx, middlewares.UserIDKey, tt.subject)
+			}
+			if tt.claims != nil {
+				ctx = context.WithValue(ctx, middlewares.ClaimsKey, tt.claims)
+			}
+
+			err := NewOwnershipPolicy().AuthorizeOwner(ctx, owner)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("AuthorizeOwner() = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
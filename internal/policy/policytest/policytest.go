@@ -0,0 +1,153 @@
+package policytest
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/EduardoMark/gobid/internal/auth/session"
+	"github.com/EduardoMark/gobid/internal/auth/token"
+	"github.com/EduardoMark/gobid/internal/policy"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+var Subject = uuid.New()
+
+var routeParam = regexp.MustCompile(`\{[^}]+\}`)
+
+type denyAll struct{}
+
+func Deny() policy.Policy {
+	return denyAll{}
+}
+
+func (denyAll) Subject(ctx context.Context) (uuid.UUID, error) {
+	return uuid.UUID{}, policy.ErrUnauthenticated
+}
+
+func (denyAll) AuthorizeOwner(ctx context.Context, owner uuid.UUID) error {
+	return policy.ErrForbidden
+}
+
+type allowAll struct{}
+
+func Allow() policy.Policy {
+	return allowAll{}
+}
+
+func (allowAll) Subject(ctx context.Context) (uuid.UUID, error) {
+	return Subject, nil
+}
+
+func (allowAll) AuthorizeOwner(ctx context.Context, owner uuid.UUID) error {
+	return nil
+}
+
+type jwtService struct {
+	token.JwtService
+	permissions []string
+}
+
+func JwtService(permissions ...string) token.JwtService {
+	return jwtService{permissions: permissions}
+}
+
+func (s jwtService) ValidateToken(encodedToken string) (*token.Claims, error) {
+	return &token.Claims{UserID: Subject.String(), Permissions: s.permissions}, nil
+}
+
+type sessions struct {
+	session.Checker
+}
+
+func Sessions() session.Checker {
+	return sessions{}
+}
+
+func (sessions) IsRevoked(ctx context.Context, claims *token.Claims) (bool, error) {
+	return false, nil
+}
+
+func (sessions) CheckAccount(ctx context.Context, claims *token.Claims) error {
+	return nil
+}
+
+func (sessions) Touch(ctx context.Context, claims *token.Claims) error {
+	return nil
+}
+
+func CheckRoutes(t *testing.T, r chi.Router, reads ...string) {
+	t.Helper()
+
+	declared := make(map[string]bool, len(reads))
+	for _, read := range reads {
+		declared[read] = false
+	}
+
+	err := chi.Walk(r, func(method, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+		name := method + " " + route
+		if _, ok := declared[name]; ok {
+			declared[name] = true
+			return nil
+		}
+
+		t.Run(name, func(t *testing.T) {
+			path := routeParam.ReplaceAllStringFunc(route, func(string) string {
+				return uuid.New().String()
+			})
+
+			req := httptest.NewRequest(method, path, nil)
+			req.Header.Set("Authorization", "Bearer token")
+
+			status, err := serve(r, req)
+			if err != nil {
+				t.Fatalf("reached the service without a policy check: %v", err)
+			}
+
+			if status != http.StatusUnauthorized && status != http.StatusForbidden {
+				t.Fatalf("status = %d, want 401 or 403 when the policy denies", status)
+			}
+		})
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("walk routes: %v", err)
+	}
+
+	for name, seen := range declared {
+		if !seen {
+			t.Errorf("read route %s is not registered", name)
+		}
+	}
+}
+
+func Do(h http.Handler, method, path, contentType string, body io.Reader) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, body)
+	req.Header.Set("Authorization", "Bearer token")
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	return rec
+}
+
+func serve(h http.Handler, req *http.Request) (status int, err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("%v", p)
+		}
+	}()
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	return rec.Code, nil
+}
@@ -4,20 +4,37 @@ import (
 	"context"
 	"time"
 
+	"github.com/EduardoMark/gobid/internal/money"
 	"github.com/EduardoMark/gobid/internal/validator"
 	"github.com/google/uuid"
 )
 
 type CreateProductReq struct {
-	SellerID    string    `json:"seller_id"`
-	Name        string    `json:"name"`
-	Description string    `json:"description"`
-	BasePrice   float64   `json:"base_price"`
-	AuctionEnd  time.Time `json:"auction_end"`
+	SellerID    string       `json:"seller_id"`
+	Name        string       `json:"name"`
+	Description string       `json:"description"`
+	BasePrice   money.Amount `json:"base_price"`
+	AuctionEnd  time.Time    `json:"auction_end"`
+	Category    string       `json:"category"`
+
+	ShippingOptions []ShippingOptionReq `json:"shipping_options"`
+}
+
+type ShippingOptionReq struct {
+	Kind   string       `json:"kind"`
+	Region string       `json:"region"`
+	Cost   money.Amount `json:"cost"`
 }
 
 const minAuctionDuration = time.Hour * 2
 
+var shippingKinds = map[string]bool{
+	"flat":   true,
+	"free":   true,
+	"pickup": true,
+	"region": true,
+}
+
 func (r *CreateProductReq) Valid(ctx context.Context) validator.Evaluator {
 	var eval validator.Evaluator
 
@@ -29,18 +46,65 @@ func (r *CreateProductReq) Valid(ctx context.Context) validator.Evaluator {
 	)
 	eval.CheckField(r.BasePrice > 0, "base_price", "this field grather than 0")
 	eval.CheckField(time.Until(r.AuctionEnd) >= minAuctionDuration, "auction_end", "must be at least two hours duration")
+	eval.CheckField(validator.MaxChars(r.Category, 50), "category", "this field must have at most 50 characters")
+	eval.CheckField(len(r.ShippingOptions) > 0, "shipping_options", "at least one shipping option is required")
+
+	for _, option := range r.ShippingOptions {
+		eval.CheckField(shippingKinds[option.Kind], "shipping_options", "kind must be one of flat, free, pickup, region")
+		eval.CheckField(option.Cost >= 0, "shipping_options", "cost cannot be negative")
+		eval.CheckField(
+			option.Kind != "region" || validator.NotBlank(option.Region),
+			"shipping_options", "region is required for region shipping options",
+		)
+		eval.CheckField(
+			(option.Kind != "free" && option.Kind != "pickup") || option.Cost == 0,
+			"shipping_options", "free and pickup shipping options cannot have a cost",
+		)
+	}
 
 	return eval
 }
 
+type ShippingOptionResponse struct {
+	ID     uuid.UUID    `json:"id"`
+	Kind   string       `json:"kind"`
+	Region string       `json:"region,omitempty"`
+	Cost   money.Amount `json:"cost"`
+}
+
 type ProductResponse struct {
-	ID          uuid.UUID `json:"id"`
-	SellerID    uuid.UUID `json:"seller_id"`
-	Name        string    `json:"name"`
-	Description string    `json:"description"`
-	BasePrice   float64   `json:"base_price"`
-	AuctionEnd  time.Time `json:"auction_end"`
-	IsSold      bool      `json:"is_sold"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID          uuid.UUID    `json:"id"`
+	SellerID    uuid.UUID    `json:"seller_id"`
+	Name        string       `json:"name"`
+	Description string       `json:"description"`
+	BasePrice   money.Amount `json:"base_price"`
+	AuctionEnd  time.Time    `json:"auction_end"`
+	Category    string       `json:"category"`
+	IsSold      bool         `json:"is_sold"`
+	CreatedAt   time.Time    `json:"created_at"`
+	UpdatedAt   time.Time    `json:"updated_at"`
+
+	Images          []ImageResponse          `json:"images"`
+	ShippingOptions []ShippingOptionResponse `json:"shipping_options,omitempty"`
+}
+
+type ImageResponse struct {
+	ID           uuid.UUID `json:"id"`
+	URL          string    `json:"url"`
+	ThumbnailURL string    `json:"thumbnail_url"`
+	Width        int32     `json:"width"`
+	Height       int32     `json:"height"`
+	Position     int32     `json:"position"`
+}
+
+type ReorderImagesReq struct {
+	ImageIDs []uuid.UUID `json:"image_ids"`
+}
+
+func (r *ReorderImagesReq) Valid(ctx context.Context) validator.Evaluator {
+	var eval validator.Evaluator
+
+	eval.CheckField(len(r.ImageIDs) > 0, "image_ids", "this field cannot be empty")
+
+	return eval
 }
@@ -5,8 +5,13 @@ import (
 	"net/http"
 
 	"github.com/EduardoMark/gobid/internal/api/middlewares"
+	"github.com/EduardoMark/gobid/internal/auth/session"
 	"github.com/EduardoMark/gobid/internal/auth/token"
 	"github.com/EduardoMark/gobid/internal/jsonutils"
+	"github.com/EduardoMark/gobid/internal/media"
+	"github.com/EduardoMark/gobid/internal/policy"
+	"github.com/EduardoMark/gobid/internal/rbac"
+	"github.com/EduardoMark/gobid/internal/store/pgstore"
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
@@ -15,23 +20,35 @@ import (
 type ProductHandler struct {
 	svc        Service
 	jwtService token.JwtService
+	sessions   session.Checker
+	apiKeys    middlewares.APIKeyAuthenticator
+	verified   middlewares.EmailVerificationChecker
+	policy     policy.Policy
 }
 
-func NewProductHandler(svc Service, jwt token.JwtService) ProductHandler {
+func NewProductHandler(svc Service, jwt token.JwtService, sessions session.Checker, apiKeys middlewares.APIKeyAuthenticator, verified middlewares.EmailVerificationChecker, owners policy.Policy) ProductHandler {
 	return ProductHandler{
 		svc:        svc,
 		jwtService: jwt,
+		sessions:   sessions,
+		apiKeys:    apiKeys,
+		verified:   verified,
+		policy:     owners,
 	}
 }
 
 func (m *ProductHandler) RegisterProductsRoutes(r chi.Router) {
 	r.Route("/products", func(r chi.Router) {
 		r.Group(func(r chi.Router) {
-			r.Use(middlewares.AuthToken(m.jwtService))
+			r.Use(middlewares.AuthTokenOrAPIKey(m.jwtService, m.sessions, m.apiKeys))
 
-			r.Post("/", m.Create)
+			r.With(middlewares.RequirePermission(rbac.PermListingsCreate), middlewares.RequireVerifiedEmail(m.verified)).Post("/", m.Create)
 			r.Get("/{id}", m.GetOne)
 			r.Get("/", m.GetAll)
+
+			r.Post("/{id}/images", m.AddImage)
+			r.Put("/{id}/images/order", m.ReorderImages)
+			r.Delete("/{id}/images/{imageID}", m.DeleteImage)
 		})
 	})
 }
@@ -39,19 +56,9 @@ func (m *ProductHandler) RegisterProductsRoutes(r chi.Router) {
 func (m *ProductHandler) Create(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	id, ok := ctx.Value(middlewares.UserIDKey).(string)
-	if !ok {
-		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
-			"error": "user ID not found in context",
-		})
-		return
-	}
-
-	sellerID, err := uuid.Parse(id)
+	sellerID, err := m.policy.Subject(ctx)
 	if err != nil {
-		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
-			"error": "invalid user ID format",
-		})
+		writePolicyError(w, r, "Handler.Create", err)
 		return
 	}
 
@@ -61,6 +68,15 @@ func (m *ProductHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	shipping := make([]ShippingOption, len(data.ShippingOptions))
+	for i, option := range data.ShippingOptions {
+		shipping[i] = ShippingOption{
+			Kind:   option.Kind,
+			Region: option.Region,
+			Cost:   option.Cost,
+		}
+	}
+
 	productID, err := m.svc.Create(
 		ctx,
 		sellerID,
@@ -68,6 +84,8 @@ func (m *ProductHandler) Create(w http.ResponseWriter, r *http.Request) {
 		data.Description,
 		data.BasePrice,
 		data.AuctionEnd,
+		data.Category,
+		shipping,
 	)
 	if err != nil {
 		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
@@ -117,11 +135,43 @@ func (m *ProductHandler) GetOne(w http.ResponseWriter, r *http.Request) {
 		Description: record.Description,
 		BasePrice:   record.BasePrice,
 		AuctionEnd:  record.AuctionEnd,
+		Category:    record.Category,
 		IsSold:      record.IsSold,
 		CreatedAt:   record.CreatedAt,
 		UpdatedAt:   record.UpdatedAt,
 	}
 
+	images, err := m.svc.ListImages(ctx, record.ID)
+	if err != nil {
+		logrus.WithField("err", err.Error()).Error("Handler.GetOne")
+
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "unexpected internal server error",
+		})
+		return
+	}
+	res.Images = toImageResponses(images)
+
+	options, err := m.svc.GetShippingOptions(ctx, record.ID)
+	if err != nil {
+		logrus.WithField("err", err.Error()).Error("Handler.GetOne")
+
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "unexpected internal server error",
+		})
+		return
+	}
+
+	res.ShippingOptions = make([]ShippingOptionResponse, len(options))
+	for i, option := range options {
+		res.ShippingOptions[i] = ShippingOptionResponse{
+			ID:     option.ID,
+			Kind:   option.Kind,
+			Region: option.Region,
+			Cost:   option.Cost,
+		}
+	}
+
 	jsonutils.EncodeJson(w, r, http.StatusOK, map[string]any{
 		"product": res,
 	})
@@ -147,6 +197,21 @@ func (m *ProductHandler) GetAll(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	ids := make([]uuid.UUID, len(records))
+	for i, record := range records {
+		ids[i] = record.ID
+	}
+
+	images, err := m.svc.ListImagesForProducts(ctx, ids)
+	if err != nil {
+		logrus.WithField("err", err.Error()).Error("Handler.GetAll")
+
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "unexpected internal server error",
+		})
+		return
+	}
+
 	res := make([]ProductResponse, len(records))
 	for i, record := range records {
 		res[i] = ProductResponse{
@@ -156,7 +221,9 @@ func (m *ProductHandler) GetAll(w http.ResponseWriter, r *http.Request) {
 			Description: record.Description,
 			BasePrice:   record.BasePrice,
 			AuctionEnd:  record.AuctionEnd,
+			Category:    record.Category,
 			IsSold:      record.IsSold,
+			Images:      toImageResponses(images[record.ID]),
 			CreatedAt:   record.CreatedAt,
 			UpdatedAt:   record.UpdatedAt,
 		}
@@ -166,3 +233,172 @@ func (m *ProductHandler) GetAll(w http.ResponseWriter, r *http.Request) {
 		"products": res,
 	})
 }
+
+func (m *ProductHandler) AddImage(w http.ResponseWriter, r *http.Request) {
+	sellerID, productID, ok := m.authorizeSeller(w, r)
+	if !ok {
+		return
+	}
+
+	img, err := media.ReadUpload(w, r, "file")
+	if err != nil {
+		if media.WriteUploadError(w, r, err) {
+			return
+		}
+
+		writeImageError(w, r, "Handler.AddImage", err)
+		return
+	}
+
+	record, err := m.svc.AddImage(r.Context(), sellerID, productID, img)
+	if err != nil {
+		writeImageError(w, r, "Handler.AddImage", err)
+		return
+	}
+
+	jsonutils.EncodeJson(w, r, http.StatusCreated, map[string]any{
+		"image": toImageResponse(record),
+	})
+}
+
+func (m *ProductHandler) ReorderImages(w http.ResponseWriter, r *http.Request) {
+	sellerID, productID, ok := m.authorizeSeller(w, r)
+	if !ok {
+		return
+	}
+
+	data, problems, err := jsonutils.DecodeValidJson[*ReorderImagesReq](r)
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+
+	records, err := m.svc.ReorderImages(r.Context(), sellerID, productID, data.ImageIDs)
+	if err != nil {
+		writeImageError(w, r, "Handler.ReorderImages", err)
+		return
+	}
+
+	jsonutils.EncodeJson(w, r, http.StatusOK, map[string]any{
+		"images": toImageResponses(records),
+	})
+}
+
+func (m *ProductHandler) DeleteImage(w http.ResponseWriter, r *http.Request) {
+	sellerID, productID, ok := m.authorizeSeller(w, r)
+	if !ok {
+		return
+	}
+
+	imageID, err := uuid.Parse(chi.URLParam(r, "imageID"))
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "invalid uuid type",
+		})
+		return
+	}
+
+	if err := m.svc.DeleteImage(r.Context(), sellerID, productID, imageID); err != nil {
+		writeImageError(w, r, "Handler.DeleteImage", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (m *ProductHandler) authorizeSeller(w http.ResponseWriter, r *http.Request) (uuid.UUID, uuid.UUID, bool) {
+	ctx := r.Context()
+
+	subject, err := m.policy.Subject(ctx)
+	if err != nil {
+		writePolicyError(w, r, "Handler.authorizeSeller", err)
+		return uuid.UUID{}, uuid.UUID{}, false
+	}
+
+	productID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "invalid uuid type",
+		})
+		return uuid.UUID{}, uuid.UUID{}, false
+	}
+
+	record, err := m.svc.GetProductByID(ctx, productID)
+	if err != nil {
+		writeImageError(w, r, "Handler.authorizeSeller", err)
+		return uuid.UUID{}, uuid.UUID{}, false
+	}
+
+	if err := m.policy.AuthorizeOwner(ctx, record.SellerID); err != nil {
+		writePolicyError(w, r, "Handler.authorizeSeller", err)
+		return uuid.UUID{}, uuid.UUID{}, false
+	}
+
+	return subject, productID, true
+}
+
+func writePolicyError(w http.ResponseWriter, r *http.Request, op string, err error) {
+	switch {
+	case errors.Is(err, policy.ErrUnauthenticated):
+		jsonutils.EncodeJson(w, r, http.StatusUnauthorized, map[string]any{
+			"error": "unauthorized",
+		})
+	case errors.Is(err, policy.ErrForbidden):
+		jsonutils.EncodeJson(w, r, http.StatusForbidden, map[string]any{
+			"error": "only the seller can manage this listing",
+		})
+	default:
+		logrus.WithField("err", err.Error()).Error(op)
+
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "unexpected internal server error",
+		})
+	}
+}
+
+func writeImageError(w http.ResponseWriter, r *http.Request, op string, err error) {
+	switch {
+	case errors.Is(err, ErrNotFound):
+		jsonutils.EncodeJson(w, r, http.StatusNotFound, map[string]any{
+			"error": "product not found",
+		})
+	case errors.Is(err, ErrNotSeller):
+		jsonutils.EncodeJson(w, r, http.StatusForbidden, map[string]any{
+			"error": "only the seller can manage this listing",
+		})
+	case errors.Is(err, ErrTooManyImages):
+		jsonutils.EncodeJson(w, r, http.StatusConflict, map[string]any{
+			"error": "listing image limit reached",
+		})
+	case errors.Is(err, ErrInvalidImageOrder):
+		jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]any{
+			"error": "image order must list every image of the listing exactly once",
+		})
+	default:
+		logrus.WithField("err", err.Error()).Error(op)
+
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "unexpected internal server error",
+		})
+	}
+}
+
+func toImageResponses(records []*pgstore.ProductImage) []ImageResponse {
+	res := make([]ImageResponse, len(records))
+	for i, record := range records {
+		res[i] = toImageResponse(record)
+	}
+
+	return res
+}
+
+func toImageResponse(record *pgstore.ProductImage) ImageResponse {
+	return ImageResponse{
+		ID:           record.ID,
+		URL:          media.URL(record.ImageKey),
+		ThumbnailURL: media.URL(record.ThumbnailKey),
+		Width:        record.Width,
+		Height:       record.Height,
+		Position:     record.Position,
+	}
+}
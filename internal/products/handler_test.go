@@ -0,0 +1,231 @@
+package products
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/png"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/EduardoMark/gobid/internal/auth/token"
+	"github.com/EduardoMark/gobid/internal/media"
+	"github.com/EduardoMark/gobid/internal/policy"
+	"github.com/EduardoMark/gobid/internal/policy/policytest"
+	"github.com/EduardoMark/gobid/internal/rbac"
+	"github.com/EduardoMark/gobid/internal/store/pgstore"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type unreachableService struct {
+	Service
+}
+
+type fakeService struct {
+	Service
+	product *pgstore.Product
+	image   *pgstore.ProductImage
+}
+
+func newFakeService(sellerID uuid.UUID) fakeService {
+	product := &pgstore.Product{
+		ID:             uuid.New(),
+		SellerID:       sellerID,
+		Name:           "Camera",
+		Description:    "A vintage camera",
+		AuctionEnd:     time.Now().Add(24 * time.Hour),
+		Category:       "general",
+		TakenDownBy:    pgtype.UUID{Bytes: uuid.New(), Valid: true},
+		TakedownReason: "counterfeit report",
+		FrozenAt:       pgtype.Timestamptz{Time: time.Now(), Valid: true},
+	}
+
+	return fakeService{
+		product: product,
+		image: &pgstore.ProductImage{
+			ID:           uuid.New(),
+			ProductID:    product.ID,
+			ImageKey:     "products/camera.jpg",
+			ThumbnailKey: "products/camera_thumb.jpg",
+			ContentType:  "image/jpeg",
+			Width:        32,
+			Height:       32,
+		},
+	}
+}
+
+func (s fakeService) GetProductByID(ctx context.Context, id uuid.UUID) (*pgstore.Product, error) {
+	if id != s.product.ID {
+		return nil, ErrNotFound
+	}
+	return s.product, nil
+}
+
+func (s fakeService) GetAllProducts(ctx context.Context) ([]*pgstore.Product, error) {
+	return []*pgstore.Product{s.product}, nil
+}
+
+func (s fakeService) GetShippingOptions(ctx context.Context, productID uuid.UUID) ([]*pgstore.ShippingOption, error) {
+	return []*pgstore.ShippingOption{{ID: uuid.New(), ProductID: productID, Kind: "pickup"}}, nil
+}
+
+func (s fakeService) ListImages(ctx context.Context, productID uuid.UUID) ([]*pgstore.ProductImage, error) {
+	return []*pgstore.ProductImage{s.image}, nil
+}
+
+func (s fakeService) ListImagesForProducts(ctx context.Context, productIDs []uuid.UUID) (map[uuid.UUID][]*pgstore.ProductImage, error) {
+	return map[uuid.UUID][]*pgstore.ProductImage{s.product.ID: {s.image}}, nil
+}
+
+func (s fakeService) AddImage(ctx context.Context, sellerID, productID uuid.UUID, img *media.Image) (*pgstore.ProductImage, error) {
+	return s.image, nil
+}
+
+func (s fakeService) DeleteImage(ctx context.Context, sellerID, productID, imageID uuid.UUID) error {
+	return nil
+}
+
+func (s fakeService) ReorderImages(ctx context.Context, sellerID, productID uuid.UUID, imageIDs []uuid.UUID) ([]*pgstore.ProductImage, error) {
+	return []*pgstore.ProductImage{s.image}, nil
+}
+
+func upload(t *testing.T) (string, *bytes.Buffer) {
+	t.Helper()
+
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+
+	part, err := form.CreateFormFile("file", "camera.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := png.Encode(part, image.NewRGBA(image.Rect(0, 0, 32, 32))); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := form.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return form.FormDataContentType(), &body
+}
+
+func TestProductRoutesEnforcePolicy(t *testing.T) {
+	h := NewProductHandler(unreachableService{}, policytest.JwtService(), policytest.Sessions(), nil, nil, policytest.Deny())
+
+	r := chi.NewRouter()
+	h.RegisterProductsRoutes(r)
+
+	policytest.CheckRoutes(t, r, "GET /products/{id}", "GET /products/")
+}
+
+func TestProductRoutesAllowSellers(t *testing.T) {
+	tests := []struct {
+		name   string
+		jwt    token.JwtService
+		policy policy.Policy
+		seller uuid.UUID
+		want   int
+	}{
+		{
+			name:   "permitting policy",
+			jwt:    policytest.JwtService(),
+			policy: policytest.Allow(),
+			seller: uuid.New(),
+		},
+		{
+			name:   "owner",
+			jwt:    policytest.JwtService(),
+			policy: policy.NewOwnershipPolicy(),
+			seller: policytest.Subject,
+		},
+		{
+			name:   "admin",
+			jwt:    policytest.JwtService(rbac.PermUsersManage),
+			policy: policy.NewOwnershipPolicy(),
+			seller: uuid.New(),
+		},
+		{
+			name:   "non-owner",
+			jwt:    policytest.JwtService(),
+			policy: policy.NewOwnershipPolicy(),
+			seller: uuid.New(),
+			want:   http.StatusForbidden,
+		},
+	}
+
+	routes := []struct {
+		method string
+		path   string
+		want   int
+	}{
+		{http.MethodPost, "/products/{id}/images", http.StatusCreated},
+		{http.MethodPut, "/products/{id}/images/order", http.StatusOK},
+		{http.MethodDelete, "/products/{id}/images/{imageID}", http.StatusNoContent},
+	}
+
+	for _, tt := range tests {
+		for _, route := range routes {
+			t.Run(tt.name+" "+route.method+" "+route.path, func(t *testing.T) {
+				svc := newFakeService(tt.seller)
+
+				h := NewProductHandler(svc, tt.jwt, policytest.Sessions(), nil, nil, tt.policy)
+
+				r := chi.NewRouter()
+				h.RegisterProductsRoutes(r)
+
+				path := strings.NewReplacer("{id}", svc.product.ID.String(), "{imageID}", svc.image.ID.String()).Replace(route.path)
+
+				contentType, body := "application/json", bytes.NewBufferString(`{"image_ids":["`+svc.image.ID.String()+`"]}`)
+				if route.method == http.MethodPost {
+					contentType, body = upload(t)
+				}
+
+				want := route.want
+				if tt.want != 0 {
+					want = tt.want
+				}
+
+				rec := policytest.Do(r, route.method, path, contentType, body)
+				if rec.Code != want {
+					t.Fatalf("status = %d, want %d: %s", rec.Code, want, rec.Body)
+				}
+			})
+		}
+	}
+}
+
+func TestReadRoutesHidePrivateFields(t *testing.T) {
+	svc := newFakeService(uuid.New())
+
+	h := NewProductHandler(svc, policytest.JwtService(), policytest.Sessions(), nil, nil, policy.NewOwnershipPolicy())
+
+	r := chi.NewRouter()
+	h.RegisterProductsRoutes(r)
+
+	for _, path := range []string{"/products/" + svc.product.ID.String(), "/products/"} {
+		t.Run(path, func(t *testing.T) {
+			rec := policytest.Do(r, http.MethodGet, path, "", nil)
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body)
+			}
+
+			body := rec.Body.String()
+			if !strings.Contains(body, svc.product.ID.String()) {
+				t.Fatalf("response is missing the product: %s", body)
+			}
+
+			for _, private := range []string{"taken_down", "takedown", "counterfeit report", "frozen_at", "image_key"} {
+				if strings.Contains(body, private) {
+					t.Errorf("response exposes %q: %s", private, body)
+				}
+			}
+		})
+	}
+}
@@ -4,8 +4,13 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
+	"github.com/EduardoMark/gobid/internal/audit"
+	"github.com/EduardoMark/gobid/internal/media"
+	"github.com/EduardoMark/gobid/internal/money"
+	"github.com/EduardoMark/gobid/internal/storage"
 	"github.com/EduardoMark/gobid/internal/store/pgstore"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
@@ -13,38 +18,106 @@ import (
 )
 
 type Service interface {
-	Create(ctx context.Context, sellerID uuid.UUID, name, description string, basePrice float64, auctionEnd time.Time) (uuid.UUID, error)
+	Create(ctx context.Context, sellerID uuid.UUID, name, description string, basePrice money.Amount, auctionEnd time.Time, category string, shipping []ShippingOption) (uuid.UUID, error)
 	GetProductByID(ctx context.Context, id uuid.UUID) (*pgstore.Product, error)
 	GetAllProducts(ctx context.Context) ([]*pgstore.Product, error)
+	GetShippingOptions(ctx context.Context, productID uuid.UUID) ([]*pgstore.ShippingOption, error)
+	ListImages(ctx context.Context, productID uuid.UUID) ([]*pgstore.ProductImage, error)
+	ListImagesForProducts(ctx context.Context, productIDs []uuid.UUID) (map[uuid.UUID][]*pgstore.ProductImage, error)
+	AddImage(ctx context.Context, sellerID, productID uuid.UUID, img *media.Image) (*pgstore.ProductImage, error)
+	DeleteImage(ctx context.Context, sellerID, productID, imageID uuid.UUID) error
+	ReorderImages(ctx context.Context, sellerID, productID uuid.UUID, imageIDs []uuid.UUID) ([]*pgstore.ProductImage, error)
+}
+
+type ShippingOption struct {
+	Kind   string
+	Region string
+	Cost   money.Amount
 }
 
 type productService struct {
-	pool *pgxpool.Pool
-	q    *pgstore.Queries
+	pool  *pgxpool.Pool
+	q     *pgstore.Queries
+	blobs storage.BlobStore
+	audit audit.Recorder
 }
 
 var ErrNotFound = errors.New("not found")
+var ErrNotSeller = errors.New("only the seller can manage this listing")
+var ErrTooManyImages = errors.New("listing image limit reached")
+var ErrInvalidImageOrder = errors.New("image order must list every image of the listing exactly once")
+
+const maxImagesPerProduct = 10
 
-func NewProductService(pool *pgxpool.Pool) Service {
+const defaultCategory = "general"
+
+func NewProductService(pool *pgxpool.Pool, blobs storage.BlobStore, recorder audit.Recorder) Service {
 	return &productService{
-		pool: pool,
-		q:    pgstore.New(pool),
+		pool:  pool,
+		q:     pgstore.New(pool),
+		blobs: blobs,
+		audit: recorder,
 	}
 }
 
-func (s *productService) Create(ctx context.Context, sellerID uuid.UUID, name, description string, basePrice float64, auctionEnd time.Time) (uuid.UUID, error) {
+func (s *productService) Create(ctx context.Context, sellerID uuid.UUID, name, description string, basePrice money.Amount, auctionEnd time.Time, category string, shipping []ShippingOption) (uuid.UUID, error) {
+	if category == "" {
+		category = defaultCategory
+	}
+
 	args := pgstore.CreateProductParams{
 		SellerID:    sellerID,
 		Name:        name,
 		Description: description,
 		BasePrice:   basePrice,
 		AuctionEnd:  auctionEnd,
+		Category:    category,
 	}
 
-	id, err := s.q.CreateProduct(ctx, args)
+	tx, err := s.pool.Begin(ctx)
 	if err != nil {
 		return uuid.UUID{}, fmt.Errorf("service.create: %v", err)
 	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.q.WithTx(tx)
+
+	id, err := qtx.CreateProduct(ctx, args)
+	if err != nil {
+		return uuid.UUID{}, fmt.Errorf("service.create: %v", err)
+	}
+
+	for _, option := range shipping {
+		err := qtx.CreateShippingOption(ctx, pgstore.CreateShippingOptionParams{
+			ProductID: id,
+			Kind:      option.Kind,
+			Region:    strings.ToUpper(option.Region),
+			Cost:      option.Cost,
+		})
+		if err != nil {
+			return uuid.UUID{}, fmt.Errorf("service.create: %v", err)
+		}
+	}
+
+	err = s.audit.WithTx(tx).Record(ctx, audit.Event{
+		ActorID:    sellerID,
+		Action:     audit.ActionListingCreate,
+		TargetType: audit.TargetListing,
+		TargetID:   id,
+		After: map[string]any{
+			"name":        name,
+			"base_price":  basePrice,
+			"auction_end": auctionEnd,
+			"category":    category,
+		},
+	})
+	if err != nil {
+		return uuid.UUID{}, fmt.Errorf("service.create: %v", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return uuid.UUID{}, fmt.Errorf("service.create: %v", err)
+	}
 
 	return id, nil
 }
@@ -58,6 +131,10 @@ func (s *productService) GetProductByID(ctx context.Context, id uuid.UUID) (*pgs
 		return nil, fmt.Errorf("service.getProductByID: %v", err)
 	}
 
+	if record.TakenDownAt.Valid {
+		return nil, ErrNotFound
+	}
+
 	return record, nil
 }
 
@@ -72,3 +149,235 @@ func (s *productService) GetAllProducts(ctx context.Context) ([]*pgstore.Product
 
 	return records, nil
 }
+
+func (s *productService) GetShippingOptions(ctx context.Context, productID uuid.UUID) ([]*pgstore.ShippingOption, error) {
+	records, err := s.q.ListShippingOptionsByProductID(ctx, productID)
+	if err != nil {
+		return nil, fmt.Errorf("service.getShippingOptions: %v", err)
+	}
+
+	return records, nil
+}
+
+func (s *productService) ListImages(ctx context.Context, productID uuid.UUID) ([]*pgstore.ProductImage, error) {
+	records, err := s.q.ListProductImages(ctx, productID)
+	if err != nil {
+		return nil, fmt.Errorf("service.listImages: %v", err)
+	}
+
+	return records, nil
+}
+
+func (s *productService) ListImagesForProducts(ctx context.Context, productIDs []uuid.UUID) (map[uuid.UUID][]*pgstore.ProductImage, error) {
+	records, err := s.q.ListProductImagesByProductIDs(ctx, productIDs)
+	if err != nil {
+		return nil, fmt.Errorf("service.listImagesForProducts: %v", err)
+	}
+
+	images := make(map[uuid.UUID][]*pgstore.ProductImage, len(productIDs))
+	for _, record := range records {
+		images[record.ProductID] = append(images[record.ProductID], record)
+	}
+
+	return images, nil
+}
+
+func (s *productService) AddImage(ctx context.Context, sellerID, productID uuid.UUID, img *media.Image) (*pgstore.ProductImage, error) {
+	if err := s.authorizeSeller(ctx, sellerID, productID); err != nil {
+		return nil, err
+	}
+
+	count, err := s.q.CountProductImages(ctx, productID)
+	if err != nil {
+		return nil, fmt.Errorf("service.addImage: %v", err)
+	}
+
+	if count >= maxImagesPerProduct {
+		return nil, ErrTooManyImages
+	}
+
+	key, thumbKey, err := media.Save(ctx, s.blobs, "products/"+productID.String(), img)
+	if err != nil {
+		return nil, fmt.Errorf("service.addImage: %v", err)
+	}
+
+	record, err := s.createImage(ctx, sellerID, productID, key, thumbKey, img)
+	if err != nil {
+		media.Remove(ctx, s.blobs, key, thumbKey)
+		return nil, fmt.Errorf("service.addImage: %v", err)
+	}
+
+	return record, nil
+}
+
+func (s *productService) createImage(ctx context.Context, sellerID, productID uuid.UUID, key, thumbKey string, img *media.Image) (*pgstore.ProductImage, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	record, err := s.q.WithTx(tx).CreateProductImage(ctx, pgstore.CreateProductImageParams{
+		ProductID:    productID,
+		ImageKey:     key,
+		ThumbnailKey: thumbKey,
+		ContentType:  img.ContentType,
+		Width:        int32(img.Width),
+		Height:       int32(img.Height),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.audit.WithTx(tx).Record(ctx, audit.Event{
+		ActorID:    sellerID,
+		Action:     audit.ActionListingImageAdd,
+		TargetType: audit.TargetListing,
+		TargetID:   productID,
+		After:      map[string]any{"image_id": record.ID, "position": record.Position},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return record, nil
+}
+
+func (s *productService) DeleteImage(ctx context.Context, sellerID, productID, imageID uuid.UUID) error {
+	if err := s.authorizeSeller(ctx, sellerID, productID); err != nil {
+		return err
+	}
+
+	record, err := s.q.GetProductImage(ctx, pgstore.GetProductImageParams{
+		ID:        imageID,
+		ProductID: productID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrNotFound
+		}
+		return fmt.Errorf("service.deleteImage: %v", err)
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("service.deleteImage: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := s.q.WithTx(tx).DeleteProductImage(ctx, record.ID); err != nil {
+		return fmt.Errorf("service.deleteImage: %v", err)
+	}
+
+	err = s.audit.WithTx(tx).Record(ctx, audit.Event{
+		ActorID:    sellerID,
+		Action:     audit.ActionListingImageRemove,
+		TargetType: audit.TargetListing,
+		TargetID:   productID,
+		Before:     map[string]any{"image_id": record.ID, "position": record.Position},
+	})
+	if err != nil {
+		return fmt.Errorf("service.deleteImage: %v", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("service.deleteImage: %v", err)
+	}
+
+	media.Remove(ctx, s.blobs, record.ImageKey, record.ThumbnailKey)
+
+	return nil
+}
+
+func (s *productService) ReorderImages(ctx context.Context, sellerID, productID uuid.UUID, imageIDs []uuid.UUID) ([]*pgstore.ProductImage, error) {
+	if err := s.authorizeSeller(ctx, sellerID, productID); err != nil {
+		return nil, err
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("service.reorderImages: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.q.WithTx(tx)
+
+	current, err := qtx.ListProductImages(ctx, productID)
+	if err != nil {
+		return nil, fmt.Errorf("service.reorderImages: %v", err)
+	}
+
+	if len(current) != len(imageIDs) {
+		return nil, ErrInvalidImageOrder
+	}
+
+	known := make(map[uuid.UUID]bool, len(current))
+	for _, record := range current {
+		known[record.ID] = true
+	}
+
+	for position, id := range imageIDs {
+		if !known[id] {
+			return nil, ErrInvalidImageOrder
+		}
+		delete(known, id)
+
+		err := qtx.SetProductImagePosition(ctx, pgstore.SetProductImagePositionParams{
+			ID:        id,
+			ProductID: productID,
+			Position:  int32(position),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("service.reorderImages: %v", err)
+		}
+	}
+
+	records, err := qtx.ListProductImages(ctx, productID)
+	if err != nil {
+		return nil, fmt.Errorf("service.reorderImages: %v", err)
+	}
+
+	err = s.audit.WithTx(tx).Record(ctx, audit.Event{
+		ActorID:    sellerID,
+		Action:     audit.ActionListingImageReorder,
+		TargetType: audit.TargetListing,
+		TargetID:   productID,
+		Before:     map[string]any{"order": imageOrder(current)},
+		After:      map[string]any{"order": imageOrder(records)},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("service.reorderImages: %v", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("service.reorderImages: %v", err)
+	}
+
+	return records, nil
+}
+
+func (s *productService) authorizeSeller(ctx context.Context, sellerID, productID uuid.UUID) error {
+	product, err := s.GetProductByID(ctx, productID)
+	if err != nil {
+		return err
+	}
+
+	if product.SellerID != sellerID {
+		return ErrNotSeller
+	}
+
+	return nil
+}
+
+func imageOrder(records []*pgstore.ProductImage) []uuid.UUID {
+	ids := make([]uuid.UUID, len(records))
+	for i, record := range records {
+		ids[i] = record.ID
+	}
+
+	return ids
+}
@@ -0,0 +1,26 @@
+package rbac
+
+import (
+	"context"
+
+	"github.com/EduardoMark/gobid/internal/validator"
+)
+
+type GrantRoleReq struct {
+	Role string `json:"role"`
+}
+
+func (r *GrantRoleReq) Valid(ctx context.Context) validator.Evaluator {
+	var eval validator.Evaluator
+
+	eval.CheckField(validator.NotBlank(r.Role), "role", "this field cannot be blank")
+
+	return eval
+}
+
+type RoleResponse struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	IsDefault   bool     `json:"is_default"`
+	Permissions []string `json:"permissions"`
+}
@@ -0,0 +1,191 @@
+package rbac
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/EduardoMark/gobid/internal/api/middlewares"
+	"github.com/EduardoMark/gobid/internal/auth/session"
+	"github.com/EduardoMark/gobid/internal/auth/token"
+	"github.com/EduardoMark/gobid/internal/jsonutils"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+type RBACHandler struct {
+	svc        Service
+	jwtService token.JwtService
+	sessions   session.Checker
+}
+
+func NewRBACHandler(svc Service, jwtService token.JwtService, sessions session.Checker) RBACHandler {
+	return RBACHandler{
+		svc:        svc,
+		jwtService: jwtService,
+		sessions:   sessions,
+	}
+}
+
+func (m *RBACHandler) RegisterRBACRoutes(r chi.Router) {
+	r.Route("/rbac", func(r chi.Router) {
+		r.Group(func(r chi.Router) {
+			r.Use(middlewares.AuthToken(m.jwtService, m.sessions))
+			r.Use(middlewares.RequirePermission(PermRolesManage))
+
+			r.Get("/roles", m.ListRoles)
+			r.Get("/users/{id}/roles", m.GetUserRoles)
+			r.Post("/users/{id}/roles", m.Grant)
+			r.Delete("/users/{id}/roles/{role}", m.Revoke)
+		})
+	})
+}
+
+func (m *RBACHandler) ListRoles(w http.ResponseWriter, r *http.Request) {
+	roles, permissions, err := m.svc.ListRoles(r.Context())
+	if err != nil {
+		logrus.WithField("err", err.Error()).Error("Handler.ListRoles")
+
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "unexpected internal server error",
+		})
+		return
+	}
+
+	res := make([]RoleResponse, len(roles))
+	for i, role := range roles {
+		res[i] = RoleResponse{
+			Name:        role.Name,
+			Description: role.Description,
+			IsDefault:   role.IsDefault,
+			Permissions: permissions[role.Name],
+		}
+		if res[i].Permissions == nil {
+			res[i].Permissions = []string{}
+		}
+	}
+
+	jsonutils.EncodeJson(w, r, http.StatusOK, map[string]any{
+		"roles": res,
+	})
+}
+
+func (m *RBACHandler) GetUserRoles(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "invalid uuid type",
+		})
+		return
+	}
+
+	access, err := m.svc.Access(r.Context(), userID)
+	if err != nil {
+		logrus.WithField("err", err.Error()).Error("Handler.GetUserRoles")
+
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "unexpected internal server error",
+		})
+		return
+	}
+
+	if access.Roles == nil {
+		access.Roles = []string{}
+	}
+	if access.Permissions == nil {
+		access.Permissions = []string{}
+	}
+
+	jsonutils.EncodeJson(w, r, http.StatusOK, map[string]any{
+		"roles":       access.Roles,
+		"permissions": access.Permissions,
+	})
+}
+
+func (m *RBACHandler) Grant(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	actor, ok := ctx.Value(middlewares.UserIDKey).(string)
+	if !ok {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "user ID not found in context",
+		})
+		return
+	}
+
+	actorID, err := uuid.Parse(actor)
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "invalid user ID format",
+		})
+		return
+	}
+
+	userID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "invalid uuid type",
+		})
+		return
+	}
+
+	data, problems, err := jsonutils.DecodeValidJson[*GrantRoleReq](r)
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+
+	if err := m.svc.Grant(ctx, userID, data.Role, actorID); err != nil {
+		switch {
+		case errors.Is(err, ErrUnknownRole):
+			jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]any{
+				"error": "unknown role",
+			})
+		case errors.Is(err, ErrUserNotFound):
+			jsonutils.EncodeJson(w, r, http.StatusNotFound, map[string]any{
+				"error": "user not found",
+			})
+		default:
+			logrus.WithField("err", err.Error()).Error("Handler.Grant")
+
+			jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+				"error": "unexpected internal server error",
+			})
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (m *RBACHandler) Revoke(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "invalid uuid type",
+		})
+		return
+	}
+
+	if err := m.svc.Revoke(r.Context(), userID, chi.URLParam(r, "role")); err != nil {
+		switch {
+		case errors.Is(err, ErrRoleNotGranted):
+			jsonutils.EncodeJson(w, r, http.StatusNotFound, map[string]any{
+				"error": "role not granted",
+			})
+		case errors.Is(err, ErrLastAdmin):
+			jsonutils.EncodeJson(w, r, http.StatusConflict, map[string]any{
+				"error": "cannot revoke the last admin",
+			})
+		default:
+			logrus.WithField("err", err.Error()).Error("Handler.Revoke")
+
+			jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+				"error": "unexpected internal server error",
+			})
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
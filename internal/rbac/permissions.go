@@ -0,0 +1,21 @@
+package rbac
+
+const (
+	RoleAdmin     = "admin"
+	RoleModerator = "moderator"
+	RoleSeller    = "seller"
+	RoleBidder    = "bidder"
+)
+
+const (
+	PermAdminAccess      = "admin:access"
+	PermUsersManage      = "users:manage"
+	PermRolesManage      = "roles:manage"
+	PermLockoutsUnlock   = "lockouts:unlock"
+	PermDisputesViewAny  = "disputes:view_any"
+	PermDisputesResolve  = "disputes:resolve"
+	PermListingsCreate   = "listings:create"
+	PermListingsModerate = "listings:moderate"
+	PermOrdersCreate     = "orders:create"
+	PermTaxManage        = "tax:manage"
+)
@@ -0,0 +1,182 @@
+package rbac
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/EduardoMark/gobid/internal/audit"
+	"github.com/EduardoMark/gobid/internal/auth/token"
+	"github.com/EduardoMark/gobid/internal/store/pgstore"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type Service interface {
+	ListRoles(ctx context.Context) ([]*pgstore.Role, map[string][]string, error)
+	Access(ctx context.Context, userID uuid.UUID) (token.Access, error)
+	Grant(ctx context.Context, userID uuid.UUID, role string, actorID uuid.UUID) error
+	Revoke(ctx context.Context, userID uuid.UUID, role string) error
+}
+
+type rbacService struct {
+	pool  *pgxpool.Pool
+	q     *pgstore.Queries
+	audit audit.Recorder
+}
+
+var ErrUnknownRole = errors.New("unknown role")
+var ErrUserNotFound = errors.New("user not found")
+var ErrRoleNotGranted = errors.New("role not granted")
+var ErrLastAdmin = errors.New("cannot revoke the last admin")
+
+func NewRBACService(pool *pgxpool.Pool, recorder audit.Recorder) Service {
+	return &rbacService{
+		pool:  pool,
+		q:     pgstore.New(pool),
+		audit: recorder,
+	}
+}
+
+func (s *rbacService) ListRoles(ctx context.Context) ([]*pgstore.Role, map[string][]string, error) {
+	roles, err := s.q.ListRoles(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("service.listRoles: %v", err)
+	}
+
+	grants, err := s.q.ListRolePermissions(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("service.listRoles: %v", err)
+	}
+
+	permissions := make(map[string][]string, len(roles))
+	for _, grant := range grants {
+		permissions[grant.Role] = append(permissions[grant.Role], grant.Permission)
+	}
+
+	return roles, permissions, nil
+}
+
+func (s *rbacService) Access(ctx context.Context, userID uuid.UUID) (token.Access, error) {
+	roles, err := s.q.ListUserRoles(ctx, userID)
+	if err != nil {
+		return token.Access{}, fmt.Errorf("service.access: %v", err)
+	}
+
+	permissions, err := s.q.ListUserPermissions(ctx, userID)
+	if err != nil {
+		return token.Access{}, fmt.Errorf("service.access: %v", err)
+	}
+
+	return token.Access{Roles: roles, Permissions: permissions}, nil
+}
+
+func (s *rbacService) Grant(ctx context.Context, userID uuid.UUID, role string, actorID uuid.UUID) error {
+	exists, err := s.q.RoleExists(ctx, role)
+	if err != nil {
+		return fmt.Errorf("service.grant: %v", err)
+	}
+
+	if !exists {
+		return ErrUnknownRole
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("service.grant: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.q.WithTx(tx)
+
+	rows, err := qtx.GrantRole(ctx, pgstore.GrantRoleParams{
+		UserID:    userID,
+		Role:      role,
+		GrantedBy: pgtype.UUID{Bytes: actorID, Valid: true},
+	})
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			return ErrUserNotFound
+		}
+		return fmt.Errorf("service.grant: %v", err)
+	}
+
+	if rows > 0 {
+		if err := qtx.InvalidateUserAccessTokens(ctx, userID); err != nil {
+			return fmt.Errorf("service.grant: %v", err)
+		}
+
+		err := s.audit.WithTx(tx).Record(ctx, audit.Event{
+			ActorID:    actorID,
+			Action:     audit.ActionRoleGrant,
+			TargetType: audit.TargetUser,
+			TargetID:   userID,
+			After:      map[string]any{"role": role},
+		})
+		if err != nil {
+			return fmt.Errorf("service.grant: %v", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("service.grant: %v", err)
+	}
+
+	return nil
+}
+
+func (s *rbacService) Revoke(ctx context.Context, userID uuid.UUID, role string) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("service.revoke: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.q.WithTx(tx)
+
+	rows, err := qtx.RevokeRole(ctx, pgstore.RevokeRoleParams{
+		UserID: userID,
+		Role:   role,
+	})
+	if err != nil {
+		return fmt.Errorf("service.revoke: %v", err)
+	}
+
+	if rows == 0 {
+		return ErrRoleNotGranted
+	}
+
+	if role == RoleAdmin {
+		remaining, err := qtx.CountRoleMembers(ctx, RoleAdmin)
+		if err != nil {
+			return fmt.Errorf("service.revoke: %v", err)
+		}
+
+		if remaining == 0 {
+			return ErrLastAdmin
+		}
+	}
+
+	if err := qtx.InvalidateUserAccessTokens(ctx, userID); err != nil {
+		return fmt.Errorf("service.revoke: %v", err)
+	}
+
+	err = s.audit.WithTx(tx).Record(ctx, audit.Event{
+		Action:     audit.ActionRoleRevoke,
+		TargetType: audit.TargetUser,
+		TargetID:   userID,
+		Before:     map[string]any{"role": role},
+	})
+	if err != nil {
+		return fmt.Errorf("service.revoke: %v", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("service.revoke: %v", err)
+	}
+
+	return nil
+}
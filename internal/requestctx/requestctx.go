@@ -0,0 +1,18 @@
+package requestctx
+
+import "context"
+
+type key string
+
+const UserIDKey key = "user_id"
+const ClientIPKey key = "client_ip"
+
+func UserID(ctx context.Context) string {
+	id, _ := ctx.Value(UserIDKey).(string)
+	return id
+}
+
+func ClientIP(ctx context.Context) string {
+	ip, _ := ctx.Value(ClientIPKey).(string)
+	return ip
+}
@@ -0,0 +1,115 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+var (
+	ErrNotFound   = errors.New("blob not found")
+	ErrInvalidKey = errors.New("invalid blob key")
+)
+
+type BlobStore interface {
+	Put(ctx context.Context, key string, r io.Reader) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
+
+func Load() (BlobStore, error) {
+	dir := os.Getenv("GOBID_BLOB_DIR")
+	if dir == "" {
+		dir = "./uploads"
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("invalid GOBID_BLOB_DIR: %v", err)
+	}
+
+	return NewLocalBlobStore(dir), nil
+}
+
+type localBlobStore struct {
+	dir string
+}
+
+func NewLocalBlobStore(dir string) BlobStore {
+	return localBlobStore{dir: dir}
+}
+
+func (s localBlobStore) Put(ctx context.Context, key string, r io.Reader) error {
+	dst, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return fmt.Errorf("localBlobStore.put: %v", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(dst), ".upload-*")
+	if err != nil {
+		return fmt.Errorf("localBlobStore.put: %v", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return fmt.Errorf("localBlobStore.put: %v", err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("localBlobStore.put: %v", err)
+	}
+
+	if err := os.Rename(tmp.Name(), dst); err != nil {
+		return fmt.Errorf("localBlobStore.put: %v", err)
+	}
+
+	return nil
+}
+
+func (s localBlobStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	src, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(src)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("localBlobStore.get: %v", err)
+	}
+
+	return file, nil
+}
+
+func (s localBlobStore) Delete(ctx context.Context, key string) error {
+	dst, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(dst); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("localBlobStore.delete: %v", err)
+	}
+
+	return nil
+}
+
+func (s localBlobStore) path(key string) (string, error) {
+	if key == "" || strings.HasPrefix(key, "/") || path.Clean(key) != key || strings.HasPrefix(key, "../") || key == ".." {
+		return "", ErrInvalidKey
+	}
+
+	return filepath.Join(s.dir, filepath.FromSlash(key)), nil
+}
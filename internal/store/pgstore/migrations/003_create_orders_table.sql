@@ -0,0 +1,18 @@
+-- Write your migrate up statements here
+CREATE TABLE IF NOT EXISTS orders (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  product_id UUID NOT NULL REFERENCES products (id),
+  buyer_id UUID NOT NULL REFERENCES users (id),
+  seller_id UUID NOT NULL REFERENCES users (id),
+  amount FLOAT NOT NULL,
+  status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'paid')),
+  listing_fee FLOAT NOT NULL DEFAULT 0,
+  final_value_fee FLOAT NOT NULL DEFAULT 0,
+  seller_net FLOAT NOT NULL DEFAULT 0,
+  paid_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+---- create above / drop below ----
+DROP TABLE IF EXISTS orders;
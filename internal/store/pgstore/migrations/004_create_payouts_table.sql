@@ -0,0 +1,16 @@
+-- Write your migrate up statements here
+CREATE TABLE IF NOT EXISTS payouts (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  seller_id UUID NOT NULL REFERENCES users (id),
+  amount FLOAT NOT NULL DEFAULT 0,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS payout_items (
+  order_id UUID PRIMARY KEY REFERENCES orders (id),
+  payout_id UUID NOT NULL REFERENCES payouts (id)
+);
+
+---- create above / drop below ----
+DROP TABLE IF EXISTS payout_items;
+DROP TABLE IF EXISTS payouts;
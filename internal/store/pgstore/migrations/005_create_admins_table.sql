@@ -0,0 +1,8 @@
+-- Write your migrate up statements here
+CREATE TABLE IF NOT EXISTS admins (
+  user_id UUID PRIMARY KEY REFERENCES users (id),
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+---- create above / drop below ----
+DROP TABLE IF EXISTS admins;
@@ -0,0 +1,29 @@
+-- Write your migrate up statements here
+CREATE TABLE IF NOT EXISTS disputes (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  order_id UUID NOT NULL UNIQUE REFERENCES orders (id),
+  buyer_id UUID NOT NULL REFERENCES users (id),
+  seller_id UUID NOT NULL REFERENCES users (id),
+  reason TEXT NOT NULL CHECK (reason IN ('not_as_described', 'not_received', 'other')),
+  evidence TEXT NOT NULL,
+  status TEXT NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'seller_responded', 'resolved')),
+  resolution TEXT CHECK (resolution IN ('full_refund', 'partial_refund', 'rejected')),
+  refund_amount FLOAT NOT NULL DEFAULT 0,
+  resolved_by UUID REFERENCES users (id),
+  resolved_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS dispute_events (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  dispute_id UUID NOT NULL REFERENCES disputes (id),
+  actor_id UUID NOT NULL REFERENCES users (id),
+  kind TEXT NOT NULL,
+  body TEXT NOT NULL DEFAULT '',
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+---- create above / drop below ----
+DROP TABLE IF EXISTS dispute_events;
+DROP TABLE IF EXISTS disputes;
@@ -0,0 +1,16 @@
+-- Write your migrate up statements here
+CREATE TABLE IF NOT EXISTS ledger_entries (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  order_id UUID NOT NULL REFERENCES orders (id),
+  account TEXT NOT NULL CHECK (account IN ('buyer', 'seller', 'platform')),
+  kind TEXT NOT NULL,
+  amount FLOAT NOT NULL,
+  reference TEXT NOT NULL DEFAULT '',
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+ALTER TABLE orders ADD COLUMN refunded_amount FLOAT NOT NULL DEFAULT 0;
+
+---- create above / drop below ----
+ALTER TABLE orders DROP COLUMN IF EXISTS refunded_amount;
+DROP TABLE IF EXISTS ledger_entries;
@@ -0,0 +1,34 @@
+-- Write your migrate up statements here
+CREATE TABLE IF NOT EXISTS tax_rules (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  region TEXT NOT NULL,
+  category TEXT NOT NULL DEFAULT '*',
+  name TEXT NOT NULL,
+  rate FLOAT NOT NULL CHECK (rate >= 0),
+  inclusive BOOLEAN NOT NULL DEFAULT false,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  UNIQUE (region, category)
+);
+
+ALTER TABLE products ADD COLUMN category TEXT NOT NULL DEFAULT 'general';
+
+ALTER TABLE orders
+  ADD COLUMN tax_region TEXT NOT NULL DEFAULT '',
+  ADD COLUMN tax_name TEXT NOT NULL DEFAULT '',
+  ADD COLUMN tax_rate FLOAT NOT NULL DEFAULT 0,
+  ADD COLUMN tax_inclusive BOOLEAN NOT NULL DEFAULT false,
+  ADD COLUMN tax_amount FLOAT NOT NULL DEFAULT 0,
+  ADD COLUMN total_amount FLOAT NOT NULL DEFAULT 0;
+
+UPDATE orders SET total_amount = amount;
+
+---- create above / drop below ----
+ALTER TABLE orders
+  DROP COLUMN IF EXISTS tax_region,
+  DROP COLUMN IF EXISTS tax_name,
+  DROP COLUMN IF EXISTS tax_rate,
+  DROP COLUMN IF EXISTS tax_inclusive,
+  DROP COLUMN IF EXISTS tax_amount,
+  DROP COLUMN IF EXISTS total_amount;
+ALTER TABLE products DROP COLUMN IF EXISTS category;
+DROP TABLE IF EXISTS tax_rules;
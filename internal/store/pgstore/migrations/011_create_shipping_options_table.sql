@@ -0,0 +1,23 @@
+-- Write your migrate up statements here
+CREATE TABLE IF NOT EXISTS shipping_options (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  product_id UUID NOT NULL REFERENCES products (id),
+  kind TEXT NOT NULL CHECK (kind IN ('flat', 'free', 'pickup', 'region')),
+  region TEXT NOT NULL DEFAULT '',
+  cost FLOAT NOT NULL DEFAULT 0 CHECK (cost >= 0),
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS shipping_options_product_id_idx ON shipping_options (product_id);
+
+ALTER TABLE orders
+  ADD COLUMN shipping_kind TEXT NOT NULL DEFAULT '',
+  ADD COLUMN shipping_cost FLOAT NOT NULL DEFAULT 0,
+  ADD COLUMN shipping_address JSONB;
+
+---- create above / drop below ----
+ALTER TABLE orders
+  DROP COLUMN IF EXISTS shipping_kind,
+  DROP COLUMN IF EXISTS shipping_cost,
+  DROP COLUMN IF EXISTS shipping_address;
+DROP TABLE IF EXISTS shipping_options;
@@ -0,0 +1,5 @@
+-- Write your migrate up statements here
+ALTER TABLE users ADD COLUMN IF NOT EXISTS tokens_invalid_before TIMESTAMPTZ;
+
+---- create above / drop below ----
+ALTER TABLE users DROP COLUMN IF EXISTS tokens_invalid_before;
@@ -0,0 +1,26 @@
+-- Write your migrate up statements here
+CREATE TABLE IF NOT EXISTS login_attempts (
+  key TEXT PRIMARY KEY,
+  failures INTEGER NOT NULL DEFAULT 0,
+  locked_until TIMESTAMPTZ,
+  last_failed_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS account_lockouts (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  scope TEXT NOT NULL CHECK (scope IN ('account', 'ip', 'two_factor')),
+  subject TEXT NOT NULL,
+  user_id UUID REFERENCES users (id) ON DELETE SET NULL,
+  failures INTEGER NOT NULL,
+  locked_until TIMESTAMPTZ NOT NULL,
+  unlocked_by UUID REFERENCES users (id) ON DELETE SET NULL,
+  unlocked_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS account_lockouts_user_id_idx ON account_lockouts (user_id);
+
+---- create above / drop below ----
+DROP INDEX IF EXISTS account_lockouts_user_id_idx;
+DROP TABLE IF EXISTS account_lockouts;
+DROP TABLE IF EXISTS login_attempts;
@@ -0,0 +1,89 @@
+-- Write your migrate up statements here
+CREATE TABLE IF NOT EXISTS roles (
+  name TEXT PRIMARY KEY,
+  description TEXT NOT NULL,
+  is_default BOOLEAN NOT NULL DEFAULT false,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS permissions (
+  name TEXT PRIMARY KEY,
+  description TEXT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS role_permissions (
+  role TEXT NOT NULL REFERENCES roles (name) ON DELETE CASCADE,
+  permission TEXT NOT NULL REFERENCES permissions (name) ON DELETE CASCADE,
+  PRIMARY KEY (role, permission)
+);
+
+CREATE TABLE IF NOT EXISTS user_roles (
+  user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+  role TEXT NOT NULL REFERENCES roles (name) ON DELETE CASCADE,
+  granted_by UUID REFERENCES users (id) ON DELETE SET NULL,
+  granted_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  PRIMARY KEY (user_id, role)
+);
+
+INSERT INTO roles (name, description, is_default) VALUES
+  ('admin', 'Full access to every administrative capability', false),
+  ('moderator', 'Reviews disputes, listings and locked accounts', false),
+  ('seller', 'Lists products for auction', true),
+  ('bidder', 'Bids on and buys products', true)
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO permissions (name, description) VALUES
+  ('users:manage', 'Update or delete any user account'),
+  ('roles:manage', 'Grant and revoke roles'),
+  ('lockouts:unlock', 'Unlock accounts locked after failed logins'),
+  ('disputes:view_any', 'View disputes the caller is not a party to'),
+  ('disputes:resolve', 'Resolve disputes'),
+  ('listings:create', 'Create product listings'),
+  ('listings:moderate', 'Take down product listings'),
+  ('orders:create', 'Place orders')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role, permission) VALUES
+  ('admin', 'users:manage'),
+  ('admin', 'roles:manage'),
+  ('admin', 'lockouts:unlock'),
+  ('admin', 'disputes:view_any'),
+  ('admin', 'disputes:resolve'),
+  ('admin', 'listings:moderate'),
+  ('moderator', 'lockouts:unlock'),
+  ('moderator', 'disputes:view_any'),
+  ('moderator', 'disputes:resolve'),
+  ('moderator', 'listings:moderate'),
+  ('seller', 'listings:create'),
+  ('bidder', 'orders:create')
+ON CONFLICT DO NOTHING;
+
+INSERT INTO user_roles (user_id, role)
+SELECT users.id, roles.name
+FROM users CROSS JOIN roles
+WHERE roles.is_default
+ON CONFLICT DO NOTHING;
+
+INSERT INTO user_roles (user_id, role, granted_at)
+SELECT user_id, 'admin', created_at
+FROM admins
+ON CONFLICT DO NOTHING;
+
+DROP TABLE IF EXISTS admins;
+
+---- create above / drop below ----
+CREATE TABLE IF NOT EXISTS admins (
+  user_id UUID PRIMARY KEY REFERENCES users (id),
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+INSERT INTO admins (user_id, created_at)
+SELECT user_id, granted_at
+FROM user_roles
+WHERE role = 'admin'
+ON CONFLICT DO NOTHING;
+
+DROP TABLE IF EXISTS user_roles;
+DROP TABLE IF EXISTS role_permissions;
+DROP TABLE IF EXISTS permissions;
+DROP TABLE IF EXISTS roles;
@@ -0,0 +1,48 @@
+-- Write your migrate up statements here
+ALTER TABLE users
+  ADD COLUMN IF NOT EXISTS suspended_at TIMESTAMPTZ,
+  ADD COLUMN IF NOT EXISTS suspension_reason TEXT NOT NULL DEFAULT '',
+  ADD COLUMN IF NOT EXISTS password_reset_required BOOLEAN NOT NULL DEFAULT false;
+
+ALTER TABLE products
+  ADD COLUMN IF NOT EXISTS taken_down_at TIMESTAMPTZ,
+  ADD COLUMN IF NOT EXISTS takedown_reason TEXT NOT NULL DEFAULT '',
+  ADD COLUMN IF NOT EXISTS taken_down_by UUID REFERENCES users (id) ON DELETE SET NULL;
+
+CREATE TABLE IF NOT EXISTS admin_actions (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  actor_id UUID NOT NULL REFERENCES users (id),
+  action TEXT NOT NULL,
+  target_type TEXT NOT NULL,
+  target_id UUID NOT NULL,
+  reason TEXT NOT NULL DEFAULT '',
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS admin_actions_target_idx ON admin_actions (target_type, target_id);
+CREATE INDEX IF NOT EXISTS admin_actions_actor_id_idx ON admin_actions (actor_id);
+
+INSERT INTO permissions (name, description) VALUES
+  ('admin:access', 'Use the support staff admin API')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role, permission) VALUES
+  ('admin', 'admin:access')
+ON CONFLICT DO NOTHING;
+
+---- create above / drop below ----
+DELETE FROM permissions WHERE name = 'admin:access';
+
+DROP INDEX IF EXISTS admin_actions_actor_id_idx;
+DROP INDEX IF EXISTS admin_actions_target_idx;
+DROP TABLE IF EXISTS admin_actions;
+
+ALTER TABLE products
+  DROP COLUMN IF EXISTS taken_down_by,
+  DROP COLUMN IF EXISTS takedown_reason,
+  DROP COLUMN IF EXISTS taken_down_at;
+
+ALTER TABLE users
+  DROP COLUMN IF EXISTS password_reset_required,
+  DROP COLUMN IF EXISTS suspension_reason,
+  DROP COLUMN IF EXISTS suspended_at;
@@ -0,0 +1,23 @@
+-- Write your migrate up statements here
+ALTER TABLE users
+  ADD COLUMN IF NOT EXISTS suspended_until TIMESTAMPTZ,
+  ADD COLUMN IF NOT EXISTS banned BOOLEAN NOT NULL DEFAULT false;
+
+ALTER TABLE products
+  ADD COLUMN IF NOT EXISTS frozen_at TIMESTAMPTZ;
+
+ALTER TABLE admin_actions
+  ALTER COLUMN actor_id DROP NOT NULL;
+
+---- create above / drop below ----
+DELETE FROM admin_actions WHERE actor_id IS NULL;
+
+ALTER TABLE admin_actions
+  ALTER COLUMN actor_id SET NOT NULL;
+
+ALTER TABLE products
+  DROP COLUMN IF EXISTS frozen_at;
+
+ALTER TABLE users
+  DROP COLUMN IF EXISTS banned,
+  DROP COLUMN IF EXISTS suspended_until;
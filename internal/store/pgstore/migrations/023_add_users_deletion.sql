@@ -0,0 +1,14 @@
+-- Write your migrate up statements here
+ALTER TABLE users
+  ADD COLUMN IF NOT EXISTS deletion_requested_at TIMESTAMPTZ,
+  ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS users_deletion_requested_at_idx ON users (deletion_requested_at)
+  WHERE deletion_requested_at IS NOT NULL AND deleted_at IS NULL;
+
+---- create above / drop below ----
+DROP INDEX IF EXISTS users_deletion_requested_at_idx;
+
+ALTER TABLE users
+  DROP COLUMN IF EXISTS deleted_at,
+  DROP COLUMN IF EXISTS deletion_requested_at;
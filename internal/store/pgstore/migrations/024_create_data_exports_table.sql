@@ -0,0 +1,17 @@
+-- Write your migrate up statements here
+CREATE TABLE IF NOT EXISTS data_exports (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+  status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'ready', 'failed', 'expired')),
+  archive BYTEA,
+  token_hash TEXT NOT NULL DEFAULT '',
+  expires_at TIMESTAMPTZ,
+  completed_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS data_exports_user_id_idx ON data_exports (user_id);
+
+---- create above / drop below ----
+DROP INDEX IF EXISTS data_exports_user_id_idx;
+DROP TABLE IF EXISTS data_exports;
@@ -0,0 +1,11 @@
+-- Write your migrate up statements here
+ALTER TABLE users
+  ADD COLUMN IF NOT EXISTS show_email BOOLEAN NOT NULL DEFAULT false,
+  ADD COLUMN IF NOT EXISTS show_email_verified BOOLEAN NOT NULL DEFAULT true,
+  ADD COLUMN IF NOT EXISTS show_sales_history BOOLEAN NOT NULL DEFAULT true;
+
+---- create above / drop below ----
+ALTER TABLE users
+  DROP COLUMN IF EXISTS show_sales_history,
+  DROP COLUMN IF EXISTS show_email_verified,
+  DROP COLUMN IF EXISTS show_email;
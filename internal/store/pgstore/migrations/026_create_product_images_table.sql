@@ -0,0 +1,26 @@
+-- Write your migrate up statements here
+ALTER TABLE users
+  ADD COLUMN IF NOT EXISTS avatar_key TEXT NOT NULL DEFAULT '',
+  ADD COLUMN IF NOT EXISTS avatar_thumbnail_key TEXT NOT NULL DEFAULT '';
+
+CREATE TABLE IF NOT EXISTS product_images (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  product_id UUID NOT NULL REFERENCES products (id) ON DELETE CASCADE,
+  position INTEGER NOT NULL,
+  image_key TEXT NOT NULL,
+  thumbnail_key TEXT NOT NULL,
+  content_type TEXT NOT NULL,
+  width INTEGER NOT NULL,
+  height INTEGER NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS product_images_product_id_idx ON product_images (product_id, position);
+
+---- create above / drop below ----
+DROP INDEX IF EXISTS product_images_product_id_idx;
+DROP TABLE IF EXISTS product_images;
+
+ALTER TABLE users
+  DROP COLUMN IF EXISTS avatar_thumbnail_key,
+  DROP COLUMN IF EXISTS avatar_key;
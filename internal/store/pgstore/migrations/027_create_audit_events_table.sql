@@ -0,0 +1,71 @@
+-- Write your migrate up statements here
+CREATE TABLE IF NOT EXISTS audit_events (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  actor_id UUID,
+  action TEXT NOT NULL,
+  target_type TEXT NOT NULL,
+  target_id UUID,
+  before JSONB,
+  after JSONB,
+  reason TEXT NOT NULL DEFAULT '',
+  ip TEXT NOT NULL DEFAULT '',
+  request_id TEXT NOT NULL DEFAULT '',
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS audit_events_created_at_idx ON audit_events (created_at DESC);
+CREATE INDEX IF NOT EXISTS audit_events_actor_id_idx ON audit_events (actor_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS audit_events_target_idx ON audit_events (target_type, target_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS audit_events_action_idx ON audit_events (action, created_at DESC);
+
+CREATE OR REPLACE FUNCTION audit_events_append_only() RETURNS trigger AS $$
+BEGIN
+  RAISE EXCEPTION 'audit_events is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_events_no_update_or_delete
+  BEFORE UPDATE OR DELETE ON audit_events
+  FOR EACH ROW EXECUTE FUNCTION audit_events_append_only();
+
+CREATE TRIGGER audit_events_no_truncate
+  BEFORE TRUNCATE ON audit_events
+  FOR EACH STATEMENT EXECUTE FUNCTION audit_events_append_only();
+
+INSERT INTO audit_events (id, actor_id, action, target_type, target_id, reason, created_at)
+SELECT id, actor_id, action, target_type, target_id, reason, created_at
+FROM admin_actions;
+
+DROP INDEX IF EXISTS admin_actions_actor_id_idx;
+DROP INDEX IF EXISTS admin_actions_target_idx;
+DROP TABLE IF EXISTS admin_actions;
+
+---- create above / drop below ----
+CREATE TABLE IF NOT EXISTS admin_actions (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  actor_id UUID REFERENCES users (id),
+  action TEXT NOT NULL,
+  target_type TEXT NOT NULL,
+  target_id UUID NOT NULL,
+  reason TEXT NOT NULL DEFAULT '',
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS admin_actions_target_idx ON admin_actions (target_type, target_id);
+CREATE INDEX IF NOT EXISTS admin_actions_actor_id_idx ON admin_actions (actor_id);
+
+INSERT INTO admin_actions (id, actor_id, action, target_type, target_id, reason, created_at)
+SELECT id, actor_id, action, target_type, target_id, reason, created_at
+FROM audit_events
+WHERE action IN ('user.suspend', 'user.ban', 'user.unsuspend', 'user.force_password_reset', 'listing.take_down')
+  AND target_id IS NOT NULL;
+
+DROP TRIGGER IF EXISTS audit_events_no_truncate ON audit_events;
+DROP TRIGGER IF EXISTS audit_events_no_update_or_delete ON audit_events;
+DROP FUNCTION IF EXISTS audit_events_append_only();
+
+DROP INDEX IF EXISTS audit_events_action_idx;
+DROP INDEX IF EXISTS audit_events_target_idx;
+DROP INDEX IF EXISTS audit_events_actor_id_idx;
+DROP INDEX IF EXISTS audit_events_created_at_idx;
+DROP TABLE IF EXISTS audit_events;
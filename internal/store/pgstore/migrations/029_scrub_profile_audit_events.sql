@@ -0,0 +1,14 @@
+-- Write your migrate up statements here
+ALTER TABLE audit_events DISABLE TRIGGER audit_events_no_update_or_delete;
+
+UPDATE audit_events
+SET before = NULL,
+    after = jsonb_build_object(
+      'fields',
+      (SELECT coalesce(jsonb_agg(field ORDER BY field), '[]'::jsonb) FROM jsonb_object_keys(coalesce(after, '{}'::jsonb)) AS field)
+    )
+WHERE action = 'user.update';
+
+ALTER TABLE audit_events ENABLE TRIGGER audit_events_no_update_or_delete;
+
+---- create above / drop below ----
@@ -0,0 +1,10 @@
+-- Write your migrate up statements here
+ALTER TABLE payouts ADD COLUMN exported_at TIMESTAMPTZ;
+
+UPDATE payouts SET exported_at = created_at;
+
+CREATE INDEX IF NOT EXISTS payouts_unexported_idx ON payouts (created_at) WHERE exported_at IS NULL;
+
+---- create above / drop below ----
+DROP INDEX IF EXISTS payouts_unexported_idx;
+ALTER TABLE payouts DROP COLUMN IF EXISTS exported_at;
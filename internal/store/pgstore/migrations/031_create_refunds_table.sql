@@ -0,0 +1,17 @@
+-- Write your migrate up statements here
+CREATE TABLE IF NOT EXISTS refunds (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  dispute_id UUID NOT NULL UNIQUE REFERENCES disputes (id),
+  order_id UUID NOT NULL REFERENCES orders (id),
+  amount FLOAT NOT NULL,
+  status TEXT NOT NULL DEFAULT 'pending',
+  reference TEXT NOT NULL DEFAULT '',
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  completed_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS refunds_pending_idx ON refunds (created_at) WHERE status = 'pending';
+
+---- create above / drop below ----
+DROP INDEX IF EXISTS refunds_pending_idx;
+DROP TABLE IF EXISTS refunds;
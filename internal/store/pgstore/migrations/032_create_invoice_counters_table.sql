@@ -0,0 +1,18 @@
+-- Write your migrate up statements here
+CREATE TABLE IF NOT EXISTS invoice_counters (
+  id BOOLEAN PRIMARY KEY DEFAULT TRUE CHECK (id),
+  last_number BIGINT NOT NULL
+);
+
+INSERT INTO invoice_counters (last_number)
+SELECT COALESCE(MAX(number), 0) FROM invoices;
+
+ALTER TABLE invoices ALTER COLUMN number DROP DEFAULT;
+DROP SEQUENCE IF EXISTS invoice_number_seq;
+
+---- create above / drop below ----
+CREATE SEQUENCE IF NOT EXISTS invoice_number_seq;
+SELECT setval('invoice_number_seq', last_number, last_number > 0) FROM invoice_counters;
+ALTER TABLE invoices ALTER COLUMN number SET DEFAULT nextval('invoice_number_seq');
+
+DROP TABLE IF EXISTS invoice_counters;
@@ -0,0 +1,47 @@
+-- Write your migrate up statements here
+ALTER TABLE products
+  ALTER COLUMN base_price TYPE NUMERIC(12,2) USING round(base_price::numeric, 2);
+
+ALTER TABLE orders
+  ALTER COLUMN amount TYPE NUMERIC(12,2) USING round(amount::numeric, 2),
+  ALTER COLUMN listing_fee TYPE NUMERIC(12,2) USING round(listing_fee::numeric, 2),
+  ALTER COLUMN final_value_fee TYPE NUMERIC(12,2) USING round(final_value_fee::numeric, 2),
+  ALTER COLUMN seller_net TYPE NUMERIC(12,2) USING round(seller_net::numeric, 2),
+  ALTER COLUMN refunded_amount TYPE NUMERIC(12,2) USING round(refunded_amount::numeric, 2),
+  ALTER COLUMN tax_amount TYPE NUMERIC(12,2) USING round(tax_amount::numeric, 2),
+  ALTER COLUMN total_amount TYPE NUMERIC(12,2) USING round(total_amount::numeric, 2),
+  ALTER COLUMN shipping_cost TYPE NUMERIC(12,2) USING round(shipping_cost::numeric, 2);
+
+ALTER TABLE payouts
+  ALTER COLUMN amount TYPE NUMERIC(12,2) USING round(amount::numeric, 2);
+
+ALTER TABLE disputes
+  ALTER COLUMN refund_amount TYPE NUMERIC(12,2) USING round(refund_amount::numeric, 2);
+
+ALTER TABLE ledger_entries
+  ALTER COLUMN amount TYPE NUMERIC(12,2) USING round(amount::numeric, 2);
+
+ALTER TABLE refunds
+  ALTER COLUMN amount TYPE NUMERIC(12,2) USING round(amount::numeric, 2);
+
+ALTER TABLE shipping_options
+  ALTER COLUMN cost TYPE NUMERIC(12,2) USING round(cost::numeric, 2);
+
+---- create above / drop below ----
+ALTER TABLE shipping_options ALTER COLUMN cost TYPE FLOAT;
+ALTER TABLE refunds ALTER COLUMN amount TYPE FLOAT;
+ALTER TABLE ledger_entries ALTER COLUMN amount TYPE FLOAT;
+ALTER TABLE disputes ALTER COLUMN refund_amount TYPE FLOAT;
+ALTER TABLE payouts ALTER COLUMN amount TYPE FLOAT;
+
+ALTER TABLE orders
+  ALTER COLUMN amount TYPE FLOAT,
+  ALTER COLUMN listing_fee TYPE FLOAT,
+  ALTER COLUMN final_value_fee TYPE FLOAT,
+  ALTER COLUMN seller_net TYPE FLOAT,
+  ALTER COLUMN refunded_amount TYPE FLOAT,
+  ALTER COLUMN tax_amount TYPE FLOAT,
+  ALTER COLUMN total_amount TYPE FLOAT,
+  ALTER COLUMN shipping_cost TYPE FLOAT;
+
+ALTER TABLE products ALTER COLUMN base_price TYPE FLOAT;
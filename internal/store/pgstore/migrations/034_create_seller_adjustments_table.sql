@@ -0,0 +1,64 @@
+-- Write your migrate up statements here
+ALTER TABLE refunds
+  ADD COLUMN net_amount NUMERIC(12,2) NOT NULL DEFAULT 0,
+  ADD COLUMN tax_amount NUMERIC(12,2) NOT NULL DEFAULT 0,
+  ADD COLUMN shipping_amount NUMERIC(12,2) NOT NULL DEFAULT 0,
+  ADD COLUMN fee_reversal NUMERIC(12,2) NOT NULL DEFAULT 0;
+
+UPDATE refunds SET net_amount = amount WHERE status = 'completed';
+
+UPDATE refunds r
+SET tax_amount = COALESCE(round(r.amount * o.tax_amount / NULLIF(o.total_amount, 0), 2), 0),
+    shipping_amount = COALESCE(round(r.amount * o.shipping_cost / NULLIF(o.total_amount, 0), 2), 0)
+FROM orders o
+WHERE o.id = r.order_id AND r.status = 'pending';
+
+UPDATE refunds r
+SET net_amount = r.amount - r.tax_amount - r.shipping_amount
+WHERE r.status = 'pending';
+
+UPDATE refunds r
+SET fee_reversal = COALESCE(round(o.final_value_fee * r.net_amount / NULLIF(o.total_amount - o.tax_amount - o.shipping_cost, 0), 2), 0)
+FROM orders o
+WHERE o.id = r.order_id AND r.status = 'pending';
+
+CREATE TABLE IF NOT EXISTS seller_adjustments (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  seller_id UUID NOT NULL REFERENCES users (id),
+  order_id UUID REFERENCES orders (id),
+  kind TEXT NOT NULL CHECK (kind IN ('refund', 'carry_forward')),
+  amount NUMERIC(12,2) NOT NULL,
+  payout_id UUID REFERENCES payouts (id),
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS seller_adjustments_unsettled_idx ON seller_adjustments (seller_id) WHERE payout_id IS NULL;
+
+INSERT INTO seller_adjustments (seller_id, order_id, kind, amount)
+SELECT o.seller_id, o.id, 'refund', -r.amount
+FROM refunds r
+JOIN orders o ON o.id = r.order_id
+LEFT JOIN payout_items pi ON pi.order_id = o.id
+LEFT JOIN payouts p ON p.id = pi.payout_id
+WHERE r.status = 'completed'
+  AND (p.id IS NULL OR p.created_at < r.completed_at);
+
+INSERT INTO seller_adjustments (seller_id, order_id, kind, amount)
+SELECT o.seller_id, o.id, 'refund', o.seller_net - r.amount
+FROM refunds r
+JOIN orders o ON o.id = r.order_id
+JOIN payout_items pi ON pi.order_id = o.id
+JOIN payouts p ON p.id = pi.payout_id
+WHERE r.status = 'completed'
+  AND p.created_at >= r.completed_at
+  AND r.amount > o.seller_net;
+
+---- create above / drop below ----
+DROP INDEX IF EXISTS seller_adjustments_unsettled_idx;
+DROP TABLE IF EXISTS seller_adjustments;
+
+ALTER TABLE refunds
+  DROP COLUMN IF EXISTS fee_reversal,
+  DROP COLUMN IF EXISTS shipping_amount,
+  DROP COLUMN IF EXISTS tax_amount,
+  DROP COLUMN IF EXISTS net_amount;
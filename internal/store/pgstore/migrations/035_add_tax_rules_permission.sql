@@ -0,0 +1,11 @@
+-- Write your migrate up statements here
+INSERT INTO permissions (name, description) VALUES
+  ('tax:manage', 'Create, update and delete tax rules')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role, permission) VALUES
+  ('admin', 'tax:manage')
+ON CONFLICT DO NOTHING;
+
+---- create above / drop below ----
+DELETE FROM permissions WHERE name = 'tax:manage';
@@ -0,0 +1,9 @@
+-- Write your migrate up statements here
+INSERT INTO shipping_options (product_id, kind, region, cost, created_at)
+SELECT products.id, 'pickup', '', 0, products.created_at
+FROM products
+WHERE NOT EXISTS (
+  SELECT 1 FROM shipping_options WHERE shipping_options.product_id = products.id
+);
+
+---- create above / drop below ----
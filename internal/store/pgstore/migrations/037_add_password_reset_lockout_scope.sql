@@ -0,0 +1,10 @@
+-- Write your migrate up statements here
+ALTER TABLE account_lockouts DROP CONSTRAINT IF EXISTS account_lockouts_scope_check;
+ALTER TABLE account_lockouts
+  ADD CONSTRAINT account_lockouts_scope_check CHECK (scope IN ('account', 'ip', 'two_factor', 'password_reset'));
+
+---- create above / drop below ----
+DELETE FROM account_lockouts WHERE scope = 'password_reset';
+ALTER TABLE account_lockouts DROP CONSTRAINT IF EXISTS account_lockouts_scope_check;
+ALTER TABLE account_lockouts
+  ADD CONSTRAINT account_lockouts_scope_check CHECK (scope IN ('account', 'ip', 'two_factor'));
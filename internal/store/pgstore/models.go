@@ -7,27 +7,375 @@ package pgstore
 import (
 	"time"
 
+	"github.com/EduardoMark/gobid/internal/money"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
+type AccountLockout struct {
+	ID          uuid.UUID          `json:"id"`
+	Scope       string             `json:"scope"`
+	Subject     string             `json:"subject"`
+	UserID      pgtype.UUID        `json:"user_id"`
+	Failures    int32              `json:"failures"`
+	LockedUntil time.Time          `json:"locked_until"`
+	UnlockedBy  pgtype.UUID        `json:"unlocked_by"`
+	UnlockedAt  pgtype.Timestamptz `json:"unlocked_at"`
+	CreatedAt   time.Time          `json:"created_at"`
+}
+
+type Address struct {
+	ID         uuid.UUID `json:"id"`
+	UserID     uuid.UUID `json:"user_id"`
+	Name       string    `json:"name"`
+	Line1      string    `json:"line1"`
+	Line2      string    `json:"line2"`
+	City       string    `json:"city"`
+	PostalCode string    `json:"postal_code"`
+	Region     string    `json:"region"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+type ApiKey struct {
+	ID         uuid.UUID          `json:"id"`
+	UserID     uuid.UUID          `json:"user_id"`
+	Name       string             `json:"name"`
+	Prefix     string             `json:"prefix"`
+	KeyHash    string             `json:"key_hash"`
+	Scopes     []string           `json:"scopes"`
+	ExpiresAt  pgtype.Timestamptz `json:"expires_at"`
+	LastUsedAt pgtype.Timestamptz `json:"last_used_at"`
+	RevokedAt  pgtype.Timestamptz `json:"revoked_at"`
+	CreatedAt  time.Time          `json:"created_at"`
+}
+
+type AuditEvent struct {
+	ID         uuid.UUID   `json:"id"`
+	ActorID    pgtype.UUID `json:"actor_id"`
+	Action     string      `json:"action"`
+	TargetType string      `json:"target_type"`
+	TargetID   pgtype.UUID `json:"target_id"`
+	Before     []byte      `json:"before"`
+	After      []byte      `json:"after"`
+	Reason     string      `json:"reason"`
+	Ip         string      `json:"ip"`
+	RequestID  string      `json:"request_id"`
+	CreatedAt  time.Time   `json:"created_at"`
+}
+
+type DataExport struct {
+	ID          uuid.UUID          `json:"id"`
+	UserID      uuid.UUID          `json:"user_id"`
+	Status      string             `json:"status"`
+	Archive     []byte             `json:"archive"`
+	TokenHash   string             `json:"token_hash"`
+	ExpiresAt   pgtype.Timestamptz `json:"expires_at"`
+	CompletedAt pgtype.Timestamptz `json:"completed_at"`
+	CreatedAt   time.Time          `json:"created_at"`
+}
+
+type Dispute struct {
+	ID           uuid.UUID          `json:"id"`
+	OrderID      uuid.UUID          `json:"order_id"`
+	BuyerID      uuid.UUID          `json:"buyer_id"`
+	SellerID     uuid.UUID          `json:"seller_id"`
+	Reason       string             `json:"reason"`
+	Evidence     string             `json:"evidence"`
+	Status       string             `json:"status"`
+	Resolution   pgtype.Text        `json:"resolution"`
+	RefundAmount money.Amount       `json:"refund_amount"`
+	ResolvedBy   pgtype.UUID        `json:"resolved_by"`
+	ResolvedAt   pgtype.Timestamptz `json:"resolved_at"`
+	CreatedAt    time.Time          `json:"created_at"`
+	UpdatedAt    time.Time          `json:"updated_at"`
+}
+
+type DisputeEvent struct {
+	ID        uuid.UUID `json:"id"`
+	DisputeID uuid.UUID `json:"dispute_id"`
+	ActorID   uuid.UUID `json:"actor_id"`
+	Kind      string    `json:"kind"`
+	Body      string    `json:"body"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type Invoice struct {
+	ID        uuid.UUID `json:"id"`
+	OrderID   uuid.UUID `json:"order_id"`
+	Number    int64     `json:"number"`
+	Html      string    `json:"html"`
+	Pdf       []byte    `json:"pdf"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type InvoiceCounter struct {
+	ID         bool  `json:"id"`
+	LastNumber int64 `json:"last_number"`
+}
+
+type LedgerEntry struct {
+	ID        uuid.UUID    `json:"id"`
+	OrderID   uuid.UUID    `json:"order_id"`
+	Account   string       `json:"account"`
+	Kind      string       `json:"kind"`
+	Amount    money.Amount `json:"amount"`
+	Reference string       `json:"reference"`
+	CreatedAt time.Time    `json:"created_at"`
+}
+
+type LoginAttempt struct {
+	Key          string             `json:"key"`
+	Failures     int32              `json:"failures"`
+	LockedUntil  pgtype.Timestamptz `json:"locked_until"`
+	LastFailedAt time.Time          `json:"last_failed_at"`
+}
+
+type OidcLoginState struct {
+	StateHash    string    `json:"state_hash"`
+	Provider     string    `json:"provider"`
+	Nonce        string    `json:"nonce"`
+	CodeVerifier string    `json:"code_verifier"`
+	ExpiresAt    time.Time `json:"expires_at"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type Order struct {
+	ID              uuid.UUID          `json:"id"`
+	ProductID       uuid.UUID          `json:"product_id"`
+	BuyerID         uuid.UUID          `json:"buyer_id"`
+	SellerID        uuid.UUID          `json:"seller_id"`
+	Amount          money.Amount       `json:"amount"`
+	Status          string             `json:"status"`
+	ListingFee      money.Amount       `json:"listing_fee"`
+	FinalValueFee   money.Amount       `json:"final_value_fee"`
+	SellerNet       money.Amount       `json:"seller_net"`
+	PaidAt          pgtype.Timestamptz `json:"paid_at"`
+	CreatedAt       time.Time          `json:"created_at"`
+	UpdatedAt       time.Time          `json:"updated_at"`
+	RefundedAmount  money.Amount       `json:"refunded_amount"`
+	TaxRegion       string             `json:"tax_region"`
+	TaxName         string             `json:"tax_name"`
+	TaxRate         float64            `json:"tax_rate"`
+	TaxInclusive    bool               `json:"tax_inclusive"`
+	TaxAmount       money.Amount       `json:"tax_amount"`
+	TotalAmount     money.Amount       `json:"total_amount"`
+	ShippingKind    string             `json:"shipping_kind"`
+	ShippingCost    money.Amount       `json:"shipping_cost"`
+	ShippingAddress []byte             `json:"shipping_address"`
+}
+
+type Payout struct {
+	ID         uuid.UUID          `json:"id"`
+	SellerID   uuid.UUID          `json:"seller_id"`
+	Amount     money.Amount       `json:"amount"`
+	CreatedAt  time.Time          `json:"created_at"`
+	ExportedAt pgtype.Timestamptz `json:"exported_at"`
+}
+
+type PayoutItem struct {
+	OrderID  uuid.UUID `json:"order_id"`
+	PayoutID uuid.UUID `json:"payout_id"`
+}
+
+type Permission struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
 type Product struct {
-	ID          uuid.UUID `json:"id"`
-	SellerID    uuid.UUID `json:"seller_id"`
+	ID             uuid.UUID          `json:"id"`
+	SellerID       uuid.UUID          `json:"seller_id"`
+	Name           string             `json:"name"`
+	Description    string             `json:"description"`
+	BasePrice      money.Amount       `json:"base_price"`
+	AuctionEnd     time.Time          `json:"auction_end"`
+	IsSold         bool               `json:"is_sold"`
+	CreatedAt      time.Time          `json:"created_at"`
+	UpdatedAt      time.Time          `json:"updated_at"`
+	Category       string             `json:"category"`
+	TakenDownAt    pgtype.Timestamptz `json:"taken_down_at"`
+	TakedownReason string             `json:"takedown_reason"`
+	TakenDownBy    pgtype.UUID        `json:"taken_down_by"`
+	FrozenAt       pgtype.Timestamptz `json:"frozen_at"`
+}
+
+type ProductImage struct {
+	ID           uuid.UUID `json:"id"`
+	ProductID    uuid.UUID `json:"product_id"`
+	Position     int32     `json:"position"`
+	ImageKey     string    `json:"image_key"`
+	ThumbnailKey string    `json:"thumbnail_key"`
+	ContentType  string    `json:"content_type"`
+	Width        int32     `json:"width"`
+	Height       int32     `json:"height"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type RecoveryCode struct {
+	ID        uuid.UUID          `json:"id"`
+	UserID    uuid.UUID          `json:"user_id"`
+	CodeHash  string             `json:"code_hash"`
+	UsedAt    pgtype.Timestamptz `json:"used_at"`
+	CreatedAt time.Time          `json:"created_at"`
+}
+
+type RefreshToken struct {
+	ID        uuid.UUID          `json:"id"`
+	UserID    uuid.UUID          `json:"user_id"`
+	FamilyID  uuid.UUID          `json:"family_id"`
+	TokenHash string             `json:"token_hash"`
+	ExpiresAt time.Time          `json:"expires_at"`
+	UsedAt    pgtype.Timestamptz `json:"used_at"`
+	RevokedAt pgtype.Timestamptz `json:"revoked_at"`
+	CreatedAt time.Time          `json:"created_at"`
+}
+
+type Refund struct {
+	ID             uuid.UUID          `json:"id"`
+	DisputeID      uuid.UUID          `json:"dispute_id"`
+	OrderID        uuid.UUID          `json:"order_id"`
+	Amount         money.Amount       `json:"amount"`
+	Status         string             `json:"status"`
+	Reference      string             `json:"reference"`
+	CreatedAt      time.Time          `json:"created_at"`
+	CompletedAt    pgtype.Timestamptz `json:"completed_at"`
+	NetAmount      money.Amount       `json:"net_amount"`
+	TaxAmount      money.Amount       `json:"tax_amount"`
+	ShippingAmount money.Amount       `json:"shipping_amount"`
+	FeeReversal    money.Amount       `json:"fee_reversal"`
+}
+
+type RevokedToken struct {
+	Jti       string    `json:"jti"`
+	ExpiresAt time.Time `json:"expires_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type Role struct {
 	Name        string    `json:"name"`
 	Description string    `json:"description"`
-	BasePrice   float64   `json:"base_price"`
-	AuctionEnd  time.Time `json:"auction_end"`
-	IsSold      bool      `json:"is_sold"`
+	IsDefault   bool      `json:"is_default"`
 	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type RolePermission struct {
+	Role       string `json:"role"`
+	Permission string `json:"permission"`
+}
+
+type SellerAdjustment struct {
+	ID        uuid.UUID    `json:"id"`
+	SellerID  uuid.UUID    `json:"seller_id"`
+	OrderID   pgtype.UUID  `json:"order_id"`
+	Kind      string       `json:"kind"`
+	Amount    money.Amount `json:"amount"`
+	PayoutID  pgtype.UUID  `json:"payout_id"`
+	CreatedAt time.Time    `json:"created_at"`
+}
+
+type Session struct {
+	ID         uuid.UUID          `json:"id"`
+	UserID     uuid.UUID          `json:"user_id"`
+	UserAgent  string             `json:"user_agent"`
+	Ip         string             `json:"ip"`
+	CreatedAt  time.Time          `json:"created_at"`
+	LastSeenAt time.Time          `json:"last_seen_at"`
+	RevokedAt  pgtype.Timestamptz `json:"revoked_at"`
+}
+
+type Shipment struct {
+	ID             uuid.UUID `json:"id"`
+	OrderID        uuid.UUID `json:"order_id"`
+	Carrier        string    `json:"carrier"`
+	TrackingNumber string    `json:"tracking_number"`
+	Status         string    `json:"status"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+type ShipmentEvent struct {
+	ID         uuid.UUID `json:"id"`
+	ShipmentID uuid.UUID `json:"shipment_id"`
+	Status     string    `json:"status"`
+	Note       string    `json:"note"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type ShippingOption struct {
+	ID        uuid.UUID    `json:"id"`
+	ProductID uuid.UUID    `json:"product_id"`
+	Kind      string       `json:"kind"`
+	Region    string       `json:"region"`
+	Cost      money.Amount `json:"cost"`
+	CreatedAt time.Time    `json:"created_at"`
+}
+
+type TaxRule struct {
+	ID        uuid.UUID `json:"id"`
+	Region    string    `json:"region"`
+	Category  string    `json:"category"`
+	Name      string    `json:"name"`
+	Rate      float64   `json:"rate"`
+	Inclusive bool      `json:"inclusive"`
+	CreatedAt time.Time `json:"created_at"`
 }
 
 type User struct {
-	ID           uuid.UUID `json:"id"`
-	Username     string    `json:"username"`
-	Email        string    `json:"email"`
-	PasswordHash string    `json:"password_hash"`
-	Bio          string    `json:"bio"`
-	CreatedAt    time.Time `json:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at"`
+	ID                    uuid.UUID          `json:"id"`
+	Username              string             `json:"username"`
+	Email                 string             `json:"email"`
+	PasswordHash          string             `json:"password_hash"`
+	Bio                   string             `json:"bio"`
+	CreatedAt             time.Time          `json:"created_at"`
+	UpdatedAt             time.Time          `json:"updated_at"`
+	EmailVerifiedAt       pgtype.Timestamptz `json:"email_verified_at"`
+	TokensInvalidBefore   pgtype.Timestamptz `json:"tokens_invalid_before"`
+	SuspendedAt           pgtype.Timestamptz `json:"suspended_at"`
+	SuspensionReason      string             `json:"suspension_reason"`
+	PasswordResetRequired bool               `json:"password_reset_required"`
+	SuspendedUntil        pgtype.Timestamptz `json:"suspended_until"`
+	Banned                bool               `json:"banned"`
+	DeletionRequestedAt   pgtype.Timestamptz `json:"deletion_requested_at"`
+	DeletedAt             pgtype.Timestamptz `json:"deleted_at"`
+	ShowEmail             bool               `json:"show_email"`
+	ShowEmailVerified     bool               `json:"show_email_verified"`
+	ShowSalesHistory      bool               `json:"show_sales_history"`
+	AvatarKey             string             `json:"avatar_key"`
+	AvatarThumbnailKey    string             `json:"avatar_thumbnail_key"`
+}
+
+type UserIdentity struct {
+	ID          uuid.UUID `json:"id"`
+	UserID      uuid.UUID `json:"user_id"`
+	Provider    string    `json:"provider"`
+	Subject     string    `json:"subject"`
+	Email       string    `json:"email"`
+	CreatedAt   time.Time `json:"created_at"`
+	LastLoginAt time.Time `json:"last_login_at"`
+}
+
+type UserRole struct {
+	UserID    uuid.UUID   `json:"user_id"`
+	Role      string      `json:"role"`
+	GrantedBy pgtype.UUID `json:"granted_by"`
+	GrantedAt time.Time   `json:"granted_at"`
+}
+
+type UserToken struct {
+	ID        uuid.UUID          `json:"id"`
+	UserID    uuid.UUID          `json:"user_id"`
+	Purpose   string             `json:"purpose"`
+	TokenHash string             `json:"token_hash"`
+	ExpiresAt time.Time          `json:"expires_at"`
+	UsedAt    pgtype.Timestamptz `json:"used_at"`
+	CreatedAt time.Time          `json:"created_at"`
+}
+
+type UserTotp struct {
+	UserID       uuid.UUID          `json:"user_id"`
+	Secret       string             `json:"secret"`
+	ConfirmedAt  pgtype.Timestamptz `json:"confirmed_at"`
+	LastUsedStep int64              `json:"last_used_step"`
+	CreatedAt    time.Time          `json:"created_at"`
 }
@@ -9,24 +9,27 @@ import (
 	"context"
 	"time"
 
+	"github.com/EduardoMark/gobid/internal/money"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 const createProduct = `-- name: CreateProduct :one
 INSERT INTO products (
   seller_id, name,
   description, base_price,
-  auction_end
-) VALUES ($1, $2, $3, $4, $5)
+  auction_end, category
+) VALUES ($1, $2, $3, $4, $5, $6)
 RETURNING id
 `
 
 type CreateProductParams struct {
-	SellerID    uuid.UUID `json:"seller_id"`
-	Name        string    `json:"name"`
-	Description string    `json:"description"`
-	BasePrice   float64   `json:"base_price"`
-	AuctionEnd  time.Time `json:"auction_end"`
+	SellerID    uuid.UUID    `json:"seller_id"`
+	Name        string       `json:"name"`
+	Description string       `json:"description"`
+	BasePrice   money.Amount `json:"base_price"`
+	AuctionEnd  time.Time    `json:"auction_end"`
+	Category    string       `json:"category"`
 }
 
 func (q *Queries) CreateProduct(ctx context.Context, arg CreateProductParams) (uuid.UUID, error) {
@@ -36,14 +39,35 @@ func (q *Queries) CreateProduct(ctx context.Context, arg CreateProductParams) (u
 		arg.Description,
 		arg.BasePrice,
 		arg.AuctionEnd,
+		arg.Category,
 	)
 	var id uuid.UUID
 	err := row.Scan(&id)
 	return id, err
 }
 
+const freezeSellerAuctions = `-- name: FreezeSellerAuctions :execrows
+UPDATE products
+SET frozen_at = now(),
+    updated_at = now()
+WHERE seller_id = $1
+  AND is_sold = false
+  AND taken_down_at IS NULL
+  AND frozen_at IS NULL
+  AND auction_end > now()
+`
+
+func (q *Queries) FreezeSellerAuctions(ctx context.Context, sellerID uuid.UUID) (int64, error) {
+	result, err := q.db.Exec(ctx, freezeSellerAuctions, sellerID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const getAllProducts = `-- name: GetAllProducts :many
-SELECT id, seller_id, name, description, base_price, auction_end, is_sold, created_at, updated_at FROM products
+SELECT id, seller_id, name, description, base_price, auction_end, is_sold, created_at, updated_at, category, taken_down_at, takedown_reason, taken_down_by, frozen_at FROM products
+WHERE taken_down_at IS NULL AND frozen_at IS NULL
 `
 
 func (q *Queries) GetAllProducts(ctx context.Context) ([]*Product, error) {
@@ -65,6 +89,11 @@ func (q *Queries) GetAllProducts(ctx context.Context) ([]*Product, error) {
 			&i.IsSold,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.Category,
+			&i.TakenDownAt,
+			&i.TakedownReason,
+			&i.TakenDownBy,
+			&i.FrozenAt,
 		); err != nil {
 			return nil, err
 		}
@@ -77,7 +106,7 @@ func (q *Queries) GetAllProducts(ctx context.Context) ([]*Product, error) {
 }
 
 const getOneProductByID = `-- name: GetOneProductByID :one
-SELECT id, seller_id, name, description, base_price, auction_end, is_sold, created_at, updated_at FROM products
+SELECT id, seller_id, name, description, base_price, auction_end, is_sold, created_at, updated_at, category, taken_down_at, takedown_reason, taken_down_by, frozen_at FROM products
 WHERE id = $1
 `
 
@@ -94,6 +123,79 @@ func (q *Queries) GetOneProductByID(ctx context.Context, id uuid.UUID) (*Product
 		&i.IsSold,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Category,
+		&i.TakenDownAt,
+		&i.TakedownReason,
+		&i.TakenDownBy,
+		&i.FrozenAt,
+	)
+	return &i, err
+}
+
+const markProductSold = `-- name: MarkProductSold :one
+UPDATE products
+SET is_sold = true,
+    updated_at = now()
+WHERE id = $1 AND is_sold = false AND taken_down_at IS NULL AND frozen_at IS NULL
+RETURNING id
+`
+
+func (q *Queries) MarkProductSold(ctx context.Context, id uuid.UUID) (uuid.UUID, error) {
+	row := q.db.QueryRow(ctx, markProductSold, id)
+	err := row.Scan(&id)
+	return id, err
+}
+
+const takeDownProduct = `-- name: TakeDownProduct :one
+UPDATE products
+SET taken_down_at = now(),
+    takedown_reason = $2,
+    taken_down_by = $3,
+    updated_at = now()
+WHERE id = $1 AND taken_down_at IS NULL
+RETURNING id, seller_id, name, description, base_price, auction_end, is_sold, created_at, updated_at, category, taken_down_at, takedown_reason, taken_down_by, frozen_at
+`
+
+type TakeDownProductParams struct {
+	ID             uuid.UUID   `json:"id"`
+	TakedownReason string      `json:"takedown_reason"`
+	TakenDownBy    pgtype.UUID `json:"taken_down_by"`
+}
+
+func (q *Queries) TakeDownProduct(ctx context.Context, arg TakeDownProductParams) (*Product, error) {
+	row := q.db.QueryRow(ctx, takeDownProduct, arg.ID, arg.TakedownReason, arg.TakenDownBy)
+	var i Product
+	err := row.Scan(
+		&i.ID,
+		&i.SellerID,
+		&i.Name,
+		&i.Description,
+		&i.BasePrice,
+		&i.AuctionEnd,
+		&i.IsSold,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Category,
+		&i.TakenDownAt,
+		&i.TakedownReason,
+		&i.TakenDownBy,
+		&i.FrozenAt,
 	)
 	return &i, err
 }
+
+const unfreezeSellerAuctions = `-- name: UnfreezeSellerAuctions :execrows
+UPDATE products
+SET auction_end = auction_end + (now() - frozen_at),
+    frozen_at = NULL,
+    updated_at = now()
+WHERE seller_id = $1 AND frozen_at IS NOT NULL
+`
+
+func (q *Queries) UnfreezeSellerAuctions(ctx context.Context, sellerID uuid.UUID) (int64, error) {
+	result, err := q.db.Exec(ctx, unfreezeSellerAuctions, sellerID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
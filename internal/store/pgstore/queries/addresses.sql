@@ -0,0 +1,33 @@
+-- name: CreateAddress :one
+INSERT INTO addresses (
+  user_id, name,
+  line1, line2,
+  city, postal_code,
+  region
+) VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING *;
+
+-- name: ListAddressesByUserID :many
+SELECT * FROM addresses
+WHERE user_id = $1
+ORDER BY created_at;
+
+-- name: GetAddressByID :one
+SELECT * FROM addresses
+WHERE id = $1;
+
+-- name: UpdateAddress :one
+UPDATE addresses
+SET name = $3,
+    line1 = $4,
+    line2 = $5,
+    city = $6,
+    postal_code = $7,
+    region = $8,
+    updated_at = now()
+WHERE id = $1 AND user_id = $2
+RETURNING *;
+
+-- name: DeleteAddress :execrows
+DELETE FROM addresses
+WHERE id = $1 AND user_id = $2;
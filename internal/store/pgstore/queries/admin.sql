@@ -0,0 +1,45 @@
+-- name: SearchUsers :many
+SELECT * FROM users
+WHERE ($1::text = '' OR username ILIKE '%' || $1::text || '%' OR email ILIKE '%' || $1::text || '%')
+  AND ($2::text = '' OR EXISTS(
+    SELECT 1
+    FROM user_roles
+    WHERE user_roles.user_id = users.id AND user_roles.role = $2::text
+  ))
+  AND ($3::boolean IS NULL OR (suspended_at IS NOT NULL AND (banned OR suspended_until IS NULL OR suspended_until > now())) = $3::boolean)
+  AND ($4::boolean IS NULL OR (email_verified_at IS NOT NULL) = $4::boolean)
+ORDER BY created_at DESC
+LIMIT $5 OFFSET $6;
+
+-- name: SuspendUser :execrows
+UPDATE users
+SET suspended_at = now(),
+    suspended_until = $2,
+    suspension_reason = $3,
+    updated_at = now()
+WHERE id = $1 AND NOT banned;
+
+-- name: BanUser :execrows
+UPDATE users
+SET suspended_at = now(),
+    suspended_until = NULL,
+    suspension_reason = $2,
+    banned = true,
+    updated_at = now()
+WHERE id = $1 AND NOT banned;
+
+-- name: UnsuspendUser :execrows
+UPDATE users
+SET suspended_at = NULL,
+    suspended_until = NULL,
+    suspension_reason = '',
+    banned = false,
+    updated_at = now()
+WHERE id = $1 AND suspended_at IS NOT NULL;
+
+-- name: ListExpiredSuspensions :many
+SELECT id FROM users
+WHERE suspended_at IS NOT NULL
+  AND NOT banned
+  AND suspended_until IS NOT NULL
+  AND suspended_until <= now();
@@ -0,0 +1,26 @@
+-- name: CreateAuditEvent :exec
+INSERT INTO audit_events (
+  actor_id, action,
+  target_type, target_id,
+  before, after, reason,
+  ip, request_id
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9);
+
+-- name: ListAuditEvents :many
+SELECT * FROM audit_events
+WHERE ($1::uuid IS NULL OR actor_id = $1::uuid)
+  AND ($2::text = '' OR target_type = $2::text)
+  AND ($3::uuid IS NULL OR target_id = $3::uuid)
+  AND ($4::text = '' OR action = $4::text OR action LIKE $4::text || '.%')
+  AND ($5::text = '' OR ip = $5::text)
+  AND ($6::text = '' OR request_id = $6::text)
+  AND ($7::timestamptz IS NULL OR created_at >= $7::timestamptz)
+  AND ($8::timestamptz IS NULL OR created_at < $8::timestamptz)
+ORDER BY created_at DESC, id DESC
+LIMIT $9 OFFSET $10;
+
+-- name: AuditEventsMention :one
+SELECT EXISTS(
+  SELECT 1 FROM audit_events
+  WHERE strpos(coalesce(before::text, '') || coalesce(after::text, '') || reason, $1::text) > 0
+);
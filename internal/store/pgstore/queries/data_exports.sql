@@ -0,0 +1,79 @@
+-- name: CreateDataExport :one
+INSERT INTO data_exports (
+  user_id
+) VALUES ($1)
+RETURNING *;
+
+-- name: HasPendingDataExport :one
+SELECT EXISTS(
+  SELECT 1
+  FROM data_exports
+  WHERE user_id = $1 AND status = 'pending' AND created_at > now() - interval '1 hour'
+);
+
+-- name: GetDataExport :one
+SELECT * FROM data_exports
+WHERE id = $1;
+
+-- name: CompleteDataExport :execrows
+UPDATE data_exports
+SET status = 'ready',
+    archive = $2,
+    token_hash = $3,
+    expires_at = $4,
+    completed_at = now()
+WHERE id = $1 AND status = 'pending';
+
+-- name: FailDataExport :exec
+UPDATE data_exports
+SET status = 'failed',
+    completed_at = now()
+WHERE id = $1 AND status = 'pending';
+
+-- name: FailStaleDataExports :execrows
+UPDATE data_exports
+SET status = 'failed',
+    completed_at = now()
+WHERE status = 'pending' AND created_at <= now() - interval '1 hour';
+
+-- name: ExpireDataExports :exec
+UPDATE data_exports
+SET status = 'expired',
+    archive = NULL
+WHERE status = 'ready' AND expires_at <= now();
+
+-- name: ExportUserProducts :many
+SELECT * FROM products
+WHERE seller_id = $1
+ORDER BY created_at;
+
+-- name: ExportUserOrders :many
+SELECT * FROM orders
+WHERE buyer_id = $1 OR seller_id = $1
+ORDER BY created_at;
+
+-- name: ExportUserDisputes :many
+SELECT * FROM disputes
+WHERE buyer_id = $1 OR seller_id = $1
+ORDER BY created_at;
+
+-- name: ExportUserDisputeEvents :many
+SELECT dispute_events.* FROM dispute_events
+JOIN disputes ON disputes.id = dispute_events.dispute_id
+WHERE disputes.buyer_id = $1 OR disputes.seller_id = $1
+ORDER BY dispute_events.created_at;
+
+-- name: ExportUserSessions :many
+SELECT * FROM sessions
+WHERE user_id = $1
+ORDER BY created_at;
+
+-- name: ExportUserIdentities :many
+SELECT * FROM user_identities
+WHERE user_id = $1
+ORDER BY created_at;
+
+-- name: ExportUserAuditEvents :many
+SELECT * FROM audit_events
+WHERE actor_id = $1 OR (target_type = 'user' AND target_id = $1)
+ORDER BY created_at;
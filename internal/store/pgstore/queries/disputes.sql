@@ -0,0 +1,39 @@
+-- name: CreateDispute :one
+INSERT INTO disputes (
+  order_id, buyer_id,
+  seller_id, reason,
+  evidence
+) VALUES ($1, $2, $3, $4, $5)
+RETURNING id;
+
+-- name: GetDisputeByID :one
+SELECT * FROM disputes
+WHERE id = $1;
+
+-- name: MarkDisputeResponded :exec
+UPDATE disputes
+SET status = 'seller_responded',
+    updated_at = now()
+WHERE id = $1 AND status = 'open';
+
+-- name: ResolveDispute :one
+UPDATE disputes
+SET status = 'resolved',
+    resolution = $2,
+    refund_amount = $3,
+    resolved_by = $4,
+    resolved_at = now(),
+    updated_at = now()
+WHERE id = $1 AND status <> 'resolved'
+RETURNING *;
+
+-- name: CreateDisputeEvent :exec
+INSERT INTO dispute_events (
+  dispute_id, actor_id,
+  kind, body
+) VALUES ($1, $2, $3, $4);
+
+-- name: ListDisputeEvents :many
+SELECT * FROM dispute_events
+WHERE dispute_id = $1
+ORDER BY created_at;
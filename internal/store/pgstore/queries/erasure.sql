@@ -0,0 +1,79 @@
+-- name: ListDueUserDeletions :many
+SELECT id FROM users
+WHERE deletion_requested_at <= $1 AND deleted_at IS NULL
+ORDER BY deletion_requested_at;
+
+-- name: AnonymiseUser :one
+UPDATE users
+SET username = 'deleted-user',
+    email = 'deleted+' || id::text || '@gobid.invalid',
+    password_hash = '',
+    bio = '',
+    avatar_key = '',
+    avatar_thumbnail_key = '',
+    email_verified_at = NULL,
+    password_reset_required = false,
+    tokens_invalid_before = date_trunc('second', now()),
+    deleted_at = now(),
+    updated_at = now()
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING *;
+
+-- name: DeleteUserAddresses :exec
+DELETE FROM addresses
+WHERE user_id = $1;
+
+-- name: DeleteUserIdentities :exec
+DELETE FROM user_identities
+WHERE user_id = $1;
+
+-- name: DeleteUserSessions :exec
+DELETE FROM sessions
+WHERE user_id = $1;
+
+-- name: DeleteUserRefreshTokens :exec
+DELETE FROM refresh_tokens
+WHERE user_id = $1;
+
+-- name: DeleteUserTokens :exec
+DELETE FROM user_tokens
+WHERE user_id = $1;
+
+-- name: DeleteUserRecoveryCodes :exec
+DELETE FROM recovery_codes
+WHERE user_id = $1;
+
+-- name: DeleteUserRoles :exec
+DELETE FROM user_roles
+WHERE user_id = $1;
+
+-- name: DeleteUserLockouts :exec
+DELETE FROM account_lockouts
+WHERE user_id = $1;
+
+-- name: ScrubBuyerShippingAddresses :exec
+UPDATE orders
+SET shipping_address = NULL,
+    updated_at = now()
+WHERE buyer_id = $1
+  AND shipping_address IS NOT NULL
+  AND (status = 'pending' OR EXISTS(
+    SELECT 1
+    FROM shipments
+    WHERE shipments.order_id = orders.id AND shipments.status IN ('delivered', 'returned')
+  ));
+
+-- name: TakeDownSellerListings :exec
+UPDATE products
+SET taken_down_at = now(),
+    takedown_reason = 'seller account deleted',
+    updated_at = now()
+WHERE seller_id = $1 AND is_sold = false AND taken_down_at IS NULL;
+
+-- name: DeleteUserDataExports :exec
+DELETE FROM data_exports
+WHERE user_id = $1;
+
+-- name: DeleteUserAPIKeys :exec
+DELETE FROM api_keys
+WHERE user_id = $1;
@@ -0,0 +1,19 @@
+-- name: LockInvoiceCounter :one
+SELECT last_number FROM invoice_counters
+WHERE id
+FOR UPDATE;
+
+-- name: SetInvoiceCounter :exec
+UPDATE invoice_counters
+SET last_number = $1
+WHERE id;
+
+-- name: CreateInvoice :exec
+INSERT INTO invoices (
+  order_id, number,
+  html, pdf
+) VALUES ($1, $2, $3, $4);
+
+-- name: GetInvoiceByOrderID :one
+SELECT * FROM invoices
+WHERE order_id = $1;
@@ -0,0 +1,6 @@
+-- name: CreateLedgerEntry :exec
+INSERT INTO ledger_entries (
+  order_id, account,
+  kind, amount,
+  reference
+) VALUES ($1, $2, $3, $4, $5);
@@ -0,0 +1,37 @@
+-- name: GetLoginAttempt :one
+SELECT * FROM login_attempts
+WHERE key = $1;
+
+-- name: RecordLoginFailure :one
+INSERT INTO login_attempts (
+  key, failures, last_failed_at
+) VALUES ($1, 1, now())
+ON CONFLICT (key) DO UPDATE
+SET failures = CASE
+      WHEN login_attempts.last_failed_at < now() - interval '24 hours' THEN 1
+      ELSE login_attempts.failures + 1
+    END,
+    last_failed_at = now()
+RETURNING *;
+
+-- name: LockLoginAttempt :exec
+UPDATE login_attempts
+SET locked_until = $2
+WHERE key = $1;
+
+-- name: ClearLoginAttempts :exec
+DELETE FROM login_attempts
+WHERE key = $1;
+
+-- name: CreateAccountLockout :exec
+INSERT INTO account_lockouts (
+  scope, subject,
+  user_id, failures,
+  locked_until
+) VALUES ($1, $2, $3, $4, $5);
+
+-- name: UnlockAccountLockouts :exec
+UPDATE account_lockouts
+SET unlocked_by = $2,
+    unlocked_at = now()
+WHERE user_id = $1 AND unlocked_at IS NULL;
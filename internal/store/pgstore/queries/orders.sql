@@ -0,0 +1,38 @@
+-- name: CreateOrder :one
+INSERT INTO orders (
+  product_id, buyer_id,
+  seller_id, amount,
+  tax_region, tax_name,
+  tax_rate, tax_inclusive,
+  tax_amount, total_amount,
+  shipping_kind, shipping_cost,
+  shipping_address
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+RETURNING id;
+
+-- name: GetOrderByID :one
+SELECT * FROM orders
+WHERE id = $1;
+
+-- name: MarkOrderPaid :one
+UPDATE orders
+SET status = 'paid',
+    listing_fee = $2,
+    final_value_fee = $3,
+    seller_net = $4,
+    paid_at = now(),
+    updated_at = now()
+WHERE id = $1 AND status = 'pending'
+RETURNING *;
+
+-- name: AddOrderRefund :exec
+UPDATE orders
+SET refunded_amount = refunded_amount + $2,
+    updated_at = now()
+WHERE id = $1;
+
+-- name: ListOrdersByUserID :many
+SELECT * FROM orders
+WHERE buyer_id = $1 OR seller_id = $1
+ORDER BY created_at DESC
+LIMIT $2 OFFSET $3;
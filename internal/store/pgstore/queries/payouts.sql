@@ -0,0 +1,74 @@
+-- name: ListSellersWithPendingPayouts :many
+SELECT DISTINCT o.seller_id
+FROM orders o
+LEFT JOIN payout_items pi ON pi.order_id = o.id
+WHERE o.status = 'paid' AND pi.order_id IS NULL
+  AND NOT EXISTS (
+    SELECT 1 FROM disputes d
+    WHERE d.order_id = o.id AND d.status <> 'resolved'
+  )
+ORDER BY o.seller_id;
+
+-- name: CreatePayout :one
+INSERT INTO payouts (seller_id)
+VALUES ($1)
+RETURNING id;
+
+-- name: AddPendingOrdersToPayout :exec
+INSERT INTO payout_items (order_id, payout_id)
+SELECT o.id, p.id
+FROM payouts p
+JOIN orders o ON o.seller_id = p.seller_id
+LEFT JOIN payout_items pi ON pi.order_id = o.id
+WHERE p.id = $1 AND o.status = 'paid' AND pi.order_id IS NULL
+  AND NOT EXISTS (
+    SELECT 1 FROM disputes d
+    WHERE d.order_id = o.id AND d.status <> 'resolved'
+  );
+
+-- name: SummarizePayout :one
+SELECT
+  p.id,
+  p.seller_id,
+  u.username,
+  u.email,
+  (SELECT COUNT(*) FROM payout_items pi WHERE pi.payout_id = p.id) AS order_count,
+  (SELECT COALESCE(SUM(o.total_amount - o.tax_amount), 0)
+    FROM payout_items pi JOIN orders o ON o.id = pi.order_id
+    WHERE pi.payout_id = p.id)::numeric AS gross,
+  (SELECT COALESCE(SUM(o.listing_fee + o.final_value_fee), 0)
+    FROM payout_items pi JOIN orders o ON o.id = pi.order_id
+    WHERE pi.payout_id = p.id)::numeric AS fees,
+  (SELECT COALESCE(SUM(a.amount), 0)
+    FROM seller_adjustments a
+    WHERE a.payout_id = p.id)::numeric AS adjustments,
+  ((SELECT COALESCE(SUM(o.seller_net), 0)
+    FROM payout_items pi JOIN orders o ON o.id = pi.order_id
+    WHERE pi.payout_id = p.id)
+  + (SELECT COALESCE(SUM(a.amount), 0)
+    FROM seller_adjustments a
+    WHERE a.payout_id = p.id))::numeric AS net
+FROM payouts p
+JOIN users u ON u.id = p.seller_id
+WHERE p.id = $1;
+
+-- name: SetPayoutAmount :exec
+UPDATE payouts
+SET amount = $2
+WHERE id = $1;
+
+-- name: ListUnexportedPayoutIDs :many
+SELECT id FROM payouts
+WHERE exported_at IS NULL
+ORDER BY created_at, id;
+
+-- name: MarkPayoutsExported :exec
+UPDATE payouts
+SET exported_at = now()
+WHERE id = ANY($1::uuid[]) AND exported_at IS NULL;
+
+-- name: TryLockPayoutBatch :one
+SELECT pg_try_advisory_lock(hashtext('payouts.run_batch')) AS locked;
+
+-- name: UnlockPayoutBatch :one
+SELECT pg_advisory_unlock(hashtext('payouts.run_batch')) AS unlocked;
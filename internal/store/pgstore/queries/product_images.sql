@@ -0,0 +1,39 @@
+-- name: CreateProductImage :one
+INSERT INTO product_images (
+  product_id, position,
+  image_key, thumbnail_key,
+  content_type, width,
+  height
+) VALUES (
+  $1,
+  (SELECT COALESCE(MAX(position) + 1, 0) FROM product_images WHERE product_id = $1),
+  $2, $3, $4, $5, $6
+)
+RETURNING *;
+
+-- name: CountProductImages :one
+SELECT count(*) FROM product_images
+WHERE product_id = $1;
+
+-- name: ListProductImages :many
+SELECT * FROM product_images
+WHERE product_id = $1
+ORDER BY position, created_at;
+
+-- name: ListProductImagesByProductIDs :many
+SELECT * FROM product_images
+WHERE product_id = ANY($1::uuid[])
+ORDER BY product_id, position, created_at;
+
+-- name: GetProductImage :one
+SELECT * FROM product_images
+WHERE id = $1 AND product_id = $2;
+
+-- name: DeleteProductImage :exec
+DELETE FROM product_images
+WHERE id = $1;
+
+-- name: SetProductImagePosition :exec
+UPDATE product_images
+SET position = $3
+WHERE id = $1 AND product_id = $2;
@@ -2,8 +2,8 @@
 INSERT INTO products (
   seller_id, name,
   description, base_price,
-  auction_end
-) VALUES ($1, $2, $3, $4, $5)
+  auction_end, category
+) VALUES ($1, $2, $3, $4, $5, $6)
 RETURNING id;
 
 -- name: GetOneProductByID :one
@@ -11,4 +11,38 @@ SELECT * FROM products
 WHERE id = $1;
 
 -- name: GetAllProducts :many
-SELECT * FROM products;
\ No newline at end of file
+SELECT * FROM products
+WHERE taken_down_at IS NULL AND frozen_at IS NULL;
+
+-- name: MarkProductSold :one
+UPDATE products
+SET is_sold = true,
+    updated_at = now()
+WHERE id = $1 AND is_sold = false AND taken_down_at IS NULL AND frozen_at IS NULL
+RETURNING id;
+
+-- name: TakeDownProduct :one
+UPDATE products
+SET taken_down_at = now(),
+    takedown_reason = $2,
+    taken_down_by = $3,
+    updated_at = now()
+WHERE id = $1 AND taken_down_at IS NULL
+RETURNING *;
+
+-- name: FreezeSellerAuctions :execrows
+UPDATE products
+SET frozen_at = now(),
+    updated_at = now()
+WHERE seller_id = $1
+  AND is_sold = false
+  AND taken_down_at IS NULL
+  AND frozen_at IS NULL
+  AND auction_end > now();
+
+-- name: UnfreezeSellerAuctions :execrows
+UPDATE products
+SET auction_end = auction_end + (now() - frozen_at),
+    frozen_at = NULL,
+    updated_at = now()
+WHERE seller_id = $1 AND frozen_at IS NOT NULL;
@@ -0,0 +1,21 @@
+-- name: CreateRefund :one
+INSERT INTO refunds (
+  dispute_id, order_id,
+  amount, net_amount,
+  tax_amount, shipping_amount,
+  fee_reversal
+) VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING *;
+
+-- name: ListPendingRefunds :many
+SELECT * FROM refunds
+WHERE status = 'pending'
+ORDER BY created_at;
+
+-- name: CompleteRefund :one
+UPDATE refunds
+SET status = 'completed',
+    reference = $2,
+    completed_at = now()
+WHERE id = $1 AND status = 'pending'
+RETURNING *;
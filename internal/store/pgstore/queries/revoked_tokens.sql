@@ -0,0 +1,24 @@
+-- name: RevokeToken :exec
+INSERT INTO revoked_tokens (
+  jti, expires_at
+) VALUES ($1, $2)
+ON CONFLICT (jti) DO NOTHING;
+
+-- name: IsTokenRevoked :one
+SELECT EXISTS(
+  SELECT 1
+  FROM revoked_tokens
+  WHERE jti = $1
+) OR EXISTS(
+  SELECT 1
+  FROM users
+  WHERE id = $2 AND tokens_invalid_before > $3
+) OR EXISTS(
+  SELECT 1
+  FROM sessions
+  WHERE id = $4 AND revoked_at IS NOT NULL
+);
+
+-- name: DeleteExpiredRevokedTokens :exec
+DELETE FROM revoked_tokens
+WHERE expires_at < now();
@@ -0,0 +1,48 @@
+-- name: ListRoles :many
+SELECT * FROM roles
+ORDER BY name;
+
+-- name: ListRolePermissions :many
+SELECT * FROM role_permissions
+ORDER BY role, permission;
+
+-- name: RoleExists :one
+SELECT EXISTS(
+  SELECT 1
+  FROM roles
+  WHERE name = $1
+);
+
+-- name: ListUserRoles :many
+SELECT role FROM user_roles
+WHERE user_id = $1
+ORDER BY role;
+
+-- name: ListUserPermissions :many
+SELECT DISTINCT role_permissions.permission
+FROM user_roles
+JOIN role_permissions ON role_permissions.role = user_roles.role
+WHERE user_roles.user_id = $1
+ORDER BY role_permissions.permission;
+
+-- name: GrantRole :execrows
+INSERT INTO user_roles (
+  user_id, role,
+  granted_by
+) VALUES ($1, $2, $3)
+ON CONFLICT DO NOTHING;
+
+-- name: RevokeRole :execrows
+DELETE FROM user_roles
+WHERE user_id = $1 AND role = $2;
+
+-- name: CountRoleMembers :one
+SELECT COUNT(*) FROM user_roles
+WHERE role = $1;
+
+-- name: AssignDefaultRoles :exec
+INSERT INTO user_roles (user_id, role)
+SELECT $1, name
+FROM roles
+WHERE is_default
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,12 @@
+-- name: CreateSellerAdjustment :exec
+INSERT INTO seller_adjustments (
+  seller_id, order_id,
+  kind, amount,
+  payout_id
+) VALUES ($1, $2, $3, $4, $5);
+
+-- name: AddPendingAdjustmentsToPayout :exec
+UPDATE seller_adjustments a
+SET payout_id = p.id
+FROM payouts p
+WHERE p.id = $1 AND a.seller_id = p.seller_id AND a.payout_id IS NULL;
@@ -0,0 +1,26 @@
+-- name: CreateSession :one
+INSERT INTO sessions (
+  user_id, user_agent,
+  ip
+) VALUES ($1, $2, $3)
+RETURNING id;
+
+-- name: ListActiveSessionsByUserID :many
+SELECT * FROM sessions
+WHERE user_id = $1 AND revoked_at IS NULL
+ORDER BY last_seen_at DESC;
+
+-- name: RevokeSession :execrows
+UPDATE sessions
+SET revoked_at = now()
+WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL;
+
+-- name: RevokeUserSessions :exec
+UPDATE sessions
+SET revoked_at = now()
+WHERE user_id = $1 AND revoked_at IS NULL;
+
+-- name: TouchSession :exec
+UPDATE sessions
+SET last_seen_at = now()
+WHERE id = $1 AND revoked_at IS NULL AND last_seen_at < now() - interval '1 minute';
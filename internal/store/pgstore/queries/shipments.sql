@@ -0,0 +1,28 @@
+-- name: CreateShipment :one
+INSERT INTO shipments (
+  order_id, carrier,
+  tracking_number
+) VALUES ($1, $2, $3)
+RETURNING *;
+
+-- name: GetShipmentByOrderID :one
+SELECT * FROM shipments
+WHERE order_id = $1;
+
+-- name: UpdateShipmentStatus :one
+UPDATE shipments
+SET status = $2,
+    updated_at = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: CreateShipmentEvent :exec
+INSERT INTO shipment_events (
+  shipment_id, status,
+  note
+) VALUES ($1, $2, $3);
+
+-- name: ListShipmentEvents :many
+SELECT * FROM shipment_events
+WHERE shipment_id = $1
+ORDER BY created_at;
@@ -0,0 +1,14 @@
+-- name: CreateShippingOption :exec
+INSERT INTO shipping_options (
+  product_id, kind,
+  region, cost
+) VALUES ($1, $2, $3, $4);
+
+-- name: ListShippingOptionsByProductID :many
+SELECT * FROM shipping_options
+WHERE product_id = $1
+ORDER BY created_at;
+
+-- name: GetShippingOptionByID :one
+SELECT * FROM shipping_options
+WHERE id = $1;
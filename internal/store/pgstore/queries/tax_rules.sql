@@ -0,0 +1,22 @@
+-- name: FindTaxRule :one
+SELECT * FROM tax_rules
+WHERE region = $1 AND category = $2;
+
+-- name: ListTaxRules :many
+SELECT * FROM tax_rules
+ORDER BY region, category;
+
+-- name: UpsertTaxRule :one
+INSERT INTO tax_rules (
+  region, category, name, rate, inclusive
+) VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (region, category) DO UPDATE
+SET name = EXCLUDED.name,
+    rate = EXCLUDED.rate,
+    inclusive = EXCLUDED.inclusive
+RETURNING *;
+
+-- name: DeleteTaxRule :one
+DELETE FROM tax_rules
+WHERE region = $1 AND category = $2
+RETURNING *;
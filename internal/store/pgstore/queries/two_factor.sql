@@ -0,0 +1,50 @@
+-- name: UpsertPendingTOTP :exec
+INSERT INTO user_totp (
+  user_id, secret
+) VALUES ($1, $2)
+ON CONFLICT (user_id) DO UPDATE
+SET secret = EXCLUDED.secret,
+    confirmed_at = NULL,
+    last_used_step = 0,
+    created_at = now()
+WHERE user_totp.confirmed_at IS NULL;
+
+-- name: GetTOTPByUserID :one
+SELECT * FROM user_totp
+WHERE user_id = $1;
+
+-- name: ConfirmTOTP :execrows
+UPDATE user_totp
+SET confirmed_at = now(),
+    last_used_step = $2
+WHERE user_id = $1 AND confirmed_at IS NULL;
+
+-- name: UseTOTPStep :execrows
+UPDATE user_totp
+SET last_used_step = $2
+WHERE user_id = $1 AND last_used_step < $2;
+
+-- name: DeleteTOTP :exec
+DELETE FROM user_totp
+WHERE user_id = $1;
+
+-- name: IsTwoFactorEnabled :one
+SELECT EXISTS(
+  SELECT 1
+  FROM user_totp
+  WHERE user_id = $1 AND confirmed_at IS NOT NULL
+);
+
+-- name: CreateRecoveryCode :exec
+INSERT INTO recovery_codes (
+  user_id, code_hash
+) VALUES ($1, $2);
+
+-- name: UseRecoveryCode :execrows
+UPDATE recovery_codes
+SET used_at = now()
+WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL;
+
+-- name: DeleteRecoveryCodes :exec
+DELETE FROM recovery_codes
+WHERE user_id = $1;
@@ -28,15 +28,86 @@ UPDATE users
 SET username = $2,
     email = $3,
     bio = $4,
+    email_verified_at = CASE WHEN email = $3 THEN email_verified_at ELSE NULL END,
     updated_at = now()
 WHERE id = $1
 RETURNING id, username, email, bio, created_at, updated_at;
 
 -- name: ChangePassword :exec
 UPDATE users
-SET password_hash = $2
+SET password_hash = $2,
+    password_reset_required = false
 WHERE id = $1;
 
--- name: DeleteUser :exec
-DELETE FROM users
-WHERE id = $1;
\ No newline at end of file
+-- name: ScheduleUserDeletion :one
+UPDATE users
+SET deletion_requested_at = now(),
+    updated_at = now()
+WHERE id = $1 AND deletion_requested_at IS NULL AND deleted_at IS NULL
+RETURNING deletion_requested_at;
+
+-- name: CancelUserDeletion :execrows
+UPDATE users
+SET deletion_requested_at = NULL,
+    updated_at = now()
+WHERE id = $1 AND deletion_requested_at IS NOT NULL AND deleted_at IS NULL;
+
+-- name: MarkEmailVerified :exec
+UPDATE users
+SET email_verified_at = now()
+WHERE id = $1 AND email_verified_at IS NULL;
+
+-- name: IsEmailVerified :one
+SELECT email_verified_at IS NOT NULL AS verified
+FROM users
+WHERE id = $1;
+
+-- name: InvalidateUserAccessTokens :exec
+UPDATE users
+SET tokens_invalid_before = date_trunc('second', now())
+WHERE id = $1;
+
+-- name: RequirePasswordReset :exec
+UPDATE users
+SET password_reset_required = true,
+    updated_at = now()
+WHERE id = $1;
+
+-- name: UpdatePrivacySettings :one
+UPDATE users
+SET show_email = $2,
+    show_email_verified = $3,
+    show_sales_history = $4,
+    updated_at = now()
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING *;
+
+-- name: GetSellerStats :one
+SELECT
+  (
+    SELECT count(*)
+    FROM products
+    WHERE products.seller_id = $1
+      AND products.is_sold = false
+      AND products.taken_down_at IS NULL
+      AND products.frozen_at IS NULL
+      AND products.auction_end > now()
+  )::bigint AS active_listings,
+  (
+    SELECT count(*)
+    FROM orders
+    WHERE orders.seller_id = $1 AND orders.status = 'paid'
+  )::bigint AS completed_sales,
+  (
+    SELECT count(DISTINCT disputes.order_id)
+    FROM disputes
+    WHERE disputes.seller_id = $1
+  )::bigint AS disputed_sales;
+
+-- name: SetUserAvatar :one
+UPDATE users
+SET avatar_key = $2,
+    avatar_thumbnail_key = $3,
+    updated_at = now()
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING *;
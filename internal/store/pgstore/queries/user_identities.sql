@@ -0,0 +1,31 @@
+-- name: CreateOIDCLoginState :exec
+INSERT INTO oidc_login_states (
+  state_hash, provider,
+  nonce, code_verifier,
+  expires_at
+) VALUES ($1, $2, $3, $4, $5);
+
+-- name: ConsumeOIDCLoginState :one
+DELETE FROM oidc_login_states
+WHERE state_hash = $1 AND provider = $2 AND expires_at > now()
+RETURNING *;
+
+-- name: DeleteExpiredOIDCLoginStates :exec
+DELETE FROM oidc_login_states
+WHERE expires_at <= now();
+
+-- name: GetUserIdentity :one
+SELECT * FROM user_identities
+WHERE provider = $1 AND subject = $2;
+
+-- name: CreateUserIdentity :exec
+INSERT INTO user_identities (
+  user_id, provider,
+  subject, email
+) VALUES ($1, $2, $3, $4);
+
+-- name: TouchUserIdentity :exec
+UPDATE user_identities
+SET last_login_at = now(),
+    email = $2
+WHERE id = $1;
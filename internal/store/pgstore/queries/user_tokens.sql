@@ -0,0 +1,19 @@
+-- name: CreateUserToken :exec
+INSERT INTO user_tokens (
+  user_id, purpose,
+  token_hash, expires_at
+) VALUES ($1, $2, $3, $4);
+
+-- name: GetUserTokenByHash :one
+SELECT * FROM user_tokens
+WHERE token_hash = $1 AND purpose = $2;
+
+-- name: MarkUserTokenUsed :execrows
+UPDATE user_tokens
+SET used_at = now()
+WHERE id = $1 AND used_at IS NULL;
+
+-- name: InvalidateUserTokens :exec
+UPDATE user_tokens
+SET used_at = now()
+WHERE user_id = $1 AND purpose = $2 AND used_at IS NULL;
@@ -10,11 +10,28 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const cancelUserDeletion = `-- name: CancelUserDeletion :execrows
+UPDATE users
+SET deletion_requested_at = NULL,
+    updated_at = now()
+WHERE id = $1 AND deletion_requested_at IS NOT NULL AND deleted_at IS NULL
+`
+
+func (q *Queries) CancelUserDeletion(ctx context.Context, id uuid.UUID) (int64, error) {
+	result, err := q.db.Exec(ctx, cancelUserDeletion, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const changePassword = `-- name: ChangePassword :exec
 UPDATE users
-SET password_hash = $2
+SET password_hash = $2,
+    password_reset_required = false
 WHERE id = $1
 `
 
@@ -75,18 +92,8 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (uuid.UU
 	return id, err
 }
 
-const deleteUser = `-- name: DeleteUser :exec
-DELETE FROM users
-WHERE id = $1
-`
-
-func (q *Queries) DeleteUser(ctx context.Context, id uuid.UUID) error {
-	_, err := q.db.Exec(ctx, deleteUser, id)
-	return err
-}
-
 const getAllUsers = `-- name: GetAllUsers :many
-SELECT id, username, email, password_hash, bio, created_at, updated_at FROM users
+SELECT id, username, email, password_hash, bio, created_at, updated_at, email_verified_at, tokens_invalid_before, suspended_at, suspension_reason, password_reset_required, suspended_until, banned, deletion_requested_at, deleted_at, show_email, show_email_verified, show_sales_history, avatar_key, avatar_thumbnail_key FROM users
 `
 
 func (q *Queries) GetAllUsers(ctx context.Context) ([]*User, error) {
@@ -106,6 +113,20 @@ func (q *Queries) GetAllUsers(ctx context.Context) ([]*User, error) {
 			&i.Bio,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.EmailVerifiedAt,
+			&i.TokensInvalidBefore,
+			&i.SuspendedAt,
+			&i.SuspensionReason,
+			&i.PasswordResetRequired,
+			&i.SuspendedUntil,
+			&i.Banned,
+			&i.DeletionRequestedAt,
+			&i.DeletedAt,
+			&i.ShowEmail,
+			&i.ShowEmailVerified,
+			&i.ShowSalesHistory,
+			&i.AvatarKey,
+			&i.AvatarThumbnailKey,
 		); err != nil {
 			return nil, err
 		}
@@ -117,8 +138,48 @@ func (q *Queries) GetAllUsers(ctx context.Context) ([]*User, error) {
 	return items, nil
 }
 
+const getSellerStats = `-- name: GetSellerStats :one
+SELECT
+  (
+    SELECT count(*)
+    FROM products
+    WHERE products.seller_id = $1
+      AND products.is_sold = false
+      AND products.taken_down_at IS NULL
+      AND products.frozen_at IS NULL
+      AND products.auction_end > now()
+  )::bigint AS active_listings,
+  (
+    SELECT count(*)
+    FROM orders
+    WHERE orders.seller_id = $1 AND orders.status = 'paid'
+  )::bigint AS completed_sales,
+  (
+    SELECT count(DISTINCT disputes.order_id)
+    FROM disputes
+    WHERE disputes.seller_id = $1
+  )::bigint AS disputed_sales
+`
+
+type GetSellerStatsRow struct {
+	ActiveListings int64 `json:"active_listings"`
+	CompletedSales int64 `json:"completed_sales"`
+	DisputedSales  int64 `json:"disputed_sales"`
+}
+
+func (q *Queries) GetSellerStats(ctx context.Context, sellerID uuid.UUID) (*GetSellerStatsRow, error) {
+	row := q.db.QueryRow(ctx, getSellerStats, sellerID)
+	var i GetSellerStatsRow
+	err := row.Scan(
+		&i.ActiveListings,
+		&i.CompletedSales,
+		&i.DisputedSales,
+	)
+	return &i, err
+}
+
 const getUserByEmail = `-- name: GetUserByEmail :one
-SELECT id, username, email, password_hash, bio, created_at, updated_at FROM users
+SELECT id, username, email, password_hash, bio, created_at, updated_at, email_verified_at, tokens_invalid_before, suspended_at, suspension_reason, password_reset_required, suspended_until, banned, deletion_requested_at, deleted_at, show_email, show_email_verified, show_sales_history, avatar_key, avatar_thumbnail_key FROM users
 WHERE email = $1
 `
 
@@ -133,12 +194,26 @@ func (q *Queries) GetUserByEmail(ctx context.Context, email string) (*User, erro
 		&i.Bio,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.EmailVerifiedAt,
+		&i.TokensInvalidBefore,
+		&i.SuspendedAt,
+		&i.SuspensionReason,
+		&i.PasswordResetRequired,
+		&i.SuspendedUntil,
+		&i.Banned,
+		&i.DeletionRequestedAt,
+		&i.DeletedAt,
+		&i.ShowEmail,
+		&i.ShowEmailVerified,
+		&i.ShowSalesHistory,
+		&i.AvatarKey,
+		&i.AvatarThumbnailKey,
 	)
 	return &i, err
 }
 
 const getUserByID = `-- name: GetUserByID :one
-SELECT id, username, email, password_hash, bio, created_at, updated_at FROM users
+SELECT id, username, email, password_hash, bio, created_at, updated_at, email_verified_at, tokens_invalid_before, suspended_at, suspension_reason, password_reset_required, suspended_until, banned, deletion_requested_at, deleted_at, show_email, show_email_verified, show_sales_history, avatar_key, avatar_thumbnail_key FROM users
 WHERE id = $1
 `
 
@@ -153,6 +228,177 @@ func (q *Queries) GetUserByID(ctx context.Context, id uuid.UUID) (*User, error)
 		&i.Bio,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.EmailVerifiedAt,
+		&i.TokensInvalidBefore,
+		&i.SuspendedAt,
+		&i.SuspensionReason,
+		&i.PasswordResetRequired,
+		&i.SuspendedUntil,
+		&i.Banned,
+		&i.DeletionRequestedAt,
+		&i.DeletedAt,
+		&i.ShowEmail,
+		&i.ShowEmailVerified,
+		&i.ShowSalesHistory,
+		&i.AvatarKey,
+		&i.AvatarThumbnailKey,
+	)
+	return &i, err
+}
+
+const invalidateUserAccessTokens = `-- name: InvalidateUserAccessTokens :exec
+UPDATE users
+SET tokens_invalid_before = date_trunc('second', now())
+WHERE id = $1
+`
+
+func (q *Queries) InvalidateUserAccessTokens(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.Exec(ctx, invalidateUserAccessTokens, id)
+	return err
+}
+
+const isEmailVerified = `-- name: IsEmailVerified :one
+SELECT email_verified_at IS NOT NULL AS verified
+FROM users
+WHERE id = $1
+`
+
+func (q *Queries) IsEmailVerified(ctx context.Context, id uuid.UUID) (bool, error) {
+	row := q.db.QueryRow(ctx, isEmailVerified, id)
+	var verified bool
+	err := row.Scan(&verified)
+	return verified, err
+}
+
+const markEmailVerified = `-- name: MarkEmailVerified :exec
+UPDATE users
+SET email_verified_at = now()
+WHERE id = $1 AND email_verified_at IS NULL
+`
+
+func (q *Queries) MarkEmailVerified(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.Exec(ctx, markEmailVerified, id)
+	return err
+}
+
+const requirePasswordReset = `-- name: RequirePasswordReset :exec
+UPDATE users
+SET password_reset_required = true,
+    updated_at = now()
+WHERE id = $1
+`
+
+func (q *Queries) RequirePasswordReset(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.Exec(ctx, requirePasswordReset, id)
+	return err
+}
+
+const scheduleUserDeletion = `-- name: ScheduleUserDeletion :one
+UPDATE users
+SET deletion_requested_at = now(),
+    updated_at = now()
+WHERE id = $1 AND deletion_requested_at IS NULL AND deleted_at IS NULL
+RETURNING deletion_requested_at
+`
+
+func (q *Queries) ScheduleUserDeletion(ctx context.Context, id uuid.UUID) (pgtype.Timestamptz, error) {
+	row := q.db.QueryRow(ctx, scheduleUserDeletion, id)
+	var deletion_requested_at pgtype.Timestamptz
+	err := row.Scan(&deletion_requested_at)
+	return deletion_requested_at, err
+}
+
+const setUserAvatar = `-- name: SetUserAvatar :one
+UPDATE users
+SET avatar_key = $2,
+    avatar_thumbnail_key = $3,
+    updated_at = now()
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING id, username, email, password_hash, bio, created_at, updated_at, email_verified_at, tokens_invalid_before, suspended_at, suspension_reason, password_reset_required, suspended_until, banned, deletion_requested_at, deleted_at, show_email, show_email_verified, show_sales_history, avatar_key, avatar_thumbnail_key
+`
+
+type SetUserAvatarParams struct {
+	ID                 uuid.UUID `json:"id"`
+	AvatarKey          string    `json:"avatar_key"`
+	AvatarThumbnailKey string    `json:"avatar_thumbnail_key"`
+}
+
+func (q *Queries) SetUserAvatar(ctx context.Context, arg SetUserAvatarParams) (*User, error) {
+	row := q.db.QueryRow(ctx, setUserAvatar, arg.ID, arg.AvatarKey, arg.AvatarThumbnailKey)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.Email,
+		&i.PasswordHash,
+		&i.Bio,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.EmailVerifiedAt,
+		&i.TokensInvalidBefore,
+		&i.SuspendedAt,
+		&i.SuspensionReason,
+		&i.PasswordResetRequired,
+		&i.SuspendedUntil,
+		&i.Banned,
+		&i.DeletionRequestedAt,
+		&i.DeletedAt,
+		&i.ShowEmail,
+		&i.ShowEmailVerified,
+		&i.ShowSalesHistory,
+		&i.AvatarKey,
+		&i.AvatarThumbnailKey,
+	)
+	return &i, err
+}
+
+const updatePrivacySettings = `-- name: UpdatePrivacySettings :one
+UPDATE users
+SET show_email = $2,
+    show_email_verified = $3,
+    show_sales_history = $4,
+    updated_at = now()
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING id, username, email, password_hash, bio, created_at, updated_at, email_verified_at, tokens_invalid_before, suspended_at, suspension_reason, password_reset_required, suspended_until, banned, deletion_requested_at, deleted_at, show_email, show_email_verified, show_sales_history, avatar_key, avatar_thumbnail_key
+`
+
+type UpdatePrivacySettingsParams struct {
+	ID                uuid.UUID `json:"id"`
+	ShowEmail         bool      `json:"show_email"`
+	ShowEmailVerified bool      `json:"show_email_verified"`
+	ShowSalesHistory  bool      `json:"show_sales_history"`
+}
+
+func (q *Queries) UpdatePrivacySettings(ctx context.Context, arg UpdatePrivacySettingsParams) (*User, error) {
+	row := q.db.QueryRow(ctx, updatePrivacySettings,
+		arg.ID,
+		arg.ShowEmail,
+		arg.ShowEmailVerified,
+		arg.ShowSalesHistory,
+	)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.Email,
+		&i.PasswordHash,
+		&i.Bio,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.EmailVerifiedAt,
+		&i.TokensInvalidBefore,
+		&i.SuspendedAt,
+		&i.SuspensionReason,
+		&i.PasswordResetRequired,
+		&i.SuspendedUntil,
+		&i.Banned,
+		&i.DeletionRequestedAt,
+		&i.DeletedAt,
+		&i.ShowEmail,
+		&i.ShowEmailVerified,
+		&i.ShowSalesHistory,
+		&i.AvatarKey,
+		&i.AvatarThumbnailKey,
 	)
 	return &i, err
 }
@@ -162,6 +408,7 @@ UPDATE users
 SET username = $2,
     email = $3,
     bio = $4,
+    email_verified_at = CASE WHEN email = $3 THEN email_verified_at ELSE NULL END,
     updated_at = now()
 WHERE id = $1
 RETURNING id, username, email, bio, created_at, updated_at
@@ -0,0 +1,57 @@
+package suspension
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/EduardoMark/gobid/internal/store/pgstore"
+)
+
+var ErrSuspended = errors.New("account suspended")
+
+type Error struct {
+	Reason string
+	Until  *time.Time
+	Banned bool
+}
+
+func (e *Error) Error() string {
+	switch {
+	case e.Banned:
+		return fmt.Sprintf("account banned: %s", e.Reason)
+	case e.Until != nil:
+		return fmt.Sprintf("account suspended until %s: %s", e.Until.Format(time.RFC3339), e.Reason)
+	default:
+		return fmt.Sprintf("account suspended: %s", e.Reason)
+	}
+}
+
+func (e *Error) Unwrap() error {
+	return ErrSuspended
+}
+
+func Check(record *pgstore.User, now time.Time) error {
+	if !Active(record, now) {
+		return nil
+	}
+
+	err := &Error{Reason: record.SuspensionReason, Banned: record.Banned}
+	if record.SuspendedUntil.Valid && !record.Banned {
+		err.Until = &record.SuspendedUntil.Time
+	}
+
+	return err
+}
+
+func Active(record *pgstore.User, now time.Time) bool {
+	if !record.SuspendedAt.Valid {
+		return false
+	}
+
+	if record.Banned || !record.SuspendedUntil.Valid {
+		return true
+	}
+
+	return now.Before(record.SuspendedUntil.Time)
+}
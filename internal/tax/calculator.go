@@ -0,0 +1,89 @@
+package tax
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/EduardoMark/gobid/internal/money"
+	"github.com/EduardoMark/gobid/internal/store/pgstore"
+	"github.com/jackc/pgx/v5"
+)
+
+type Rule struct {
+	Name      string
+	Rate      float64
+	Inclusive bool
+}
+
+type Breakdown struct {
+	Name      string
+	Rate      float64
+	Inclusive bool
+	Net       money.Amount
+	Tax       money.Amount
+	Total     money.Amount
+}
+
+func Apply(price money.Amount, rule Rule) Breakdown {
+	b := Breakdown{
+		Name:      rule.Name,
+		Rate:      rule.Rate,
+		Inclusive: rule.Inclusive,
+	}
+
+	if rule.Inclusive {
+		b.Total = price
+		b.Net = price.ExcludingPercent(rule.Rate)
+		b.Tax = b.Total - b.Net
+		return b
+	}
+
+	b.Net = price
+	b.Tax = price.Percent(rule.Rate)
+	b.Total = b.Net + b.Tax
+	return b
+}
+
+const AnyCategory = "*"
+
+var ErrNoRule = errors.New("no tax rule for region")
+
+type RuleStore interface {
+	FindTaxRule(ctx context.Context, arg pgstore.FindTaxRuleParams) (*pgstore.TaxRule, error)
+}
+
+type Calculator interface {
+	Calculate(ctx context.Context, region, category string, price money.Amount) (Breakdown, error)
+}
+
+type ruleCalculator struct {
+	rules RuleStore
+}
+
+func NewCalculator(rules RuleStore) Calculator {
+	return &ruleCalculator{rules: rules}
+}
+
+func (c *ruleCalculator) Calculate(ctx context.Context, region, category string, price money.Amount) (Breakdown, error) {
+	for _, candidate := range []string{category, AnyCategory} {
+		record, err := c.rules.FindTaxRule(ctx, pgstore.FindTaxRuleParams{
+			Region:   region,
+			Category: candidate,
+		})
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				continue
+			}
+			return Breakdown{}, fmt.Errorf("tax.calculate: %v", err)
+		}
+
+		return Apply(price, Rule{
+			Name:      record.Name,
+			Rate:      record.Rate,
+			Inclusive: record.Inclusive,
+		}), nil
+	}
+
+	return Breakdown{}, fmt.Errorf("%w: %s/%s", ErrNoRule, region, category)
+}
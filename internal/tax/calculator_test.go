@@ -0,0 +1,164 @@
+package tax
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/EduardoMark/gobid/internal/money"
+	"github.com/EduardoMark/gobid/internal/store/pgstore"
+	"github.com/jackc/pgx/v5"
+)
+
+func TestApply(t *testing.T) {
+	tests := []struct {
+		name  string
+		price money.Amount
+		rule  Rule
+		want  Breakdown
+	}{
+		{
+			name:  "exclusive adds tax on top",
+			price: 10000,
+			rule:  Rule{Name: "VAT", Rate: 20},
+			want:  Breakdown{Name: "VAT", Rate: 20, Net: 10000, Tax: 2000, Total: 12000},
+		},
+		{
+			name:  "inclusive extracts tax from price",
+			price: 12000,
+			rule:  Rule{Name: "VAT", Rate: 20, Inclusive: true},
+			want:  Breakdown{Name: "VAT", Rate: 20, Inclusive: true, Net: 10000, Tax: 2000, Total: 12000},
+		},
+		{
+			name:  "zero rate",
+			price: 4999,
+			rule:  Rule{Name: "Exempt"},
+			want:  Breakdown{Name: "Exempt", Net: 4999, Total: 4999},
+		},
+		{
+			name:  "exclusive rounds tax to cents",
+			price: 1999,
+			rule:  Rule{Name: "GST", Rate: 7.5},
+			want:  Breakdown{Name: "GST", Rate: 7.5, Net: 1999, Tax: 150, Total: 2149},
+		},
+		{
+			name:  "inclusive rounds net and keeps total",
+			price: 1000,
+			rule:  Rule{Name: "VAT", Rate: 21, Inclusive: true},
+			want:  Breakdown{Name: "VAT", Rate: 21, Inclusive: true, Net: 826, Tax: 174, Total: 1000},
+		},
+		{
+			name:  "exclusive rounds half a cent up",
+			price: 5,
+			rule:  Rule{Name: "VAT", Rate: 10},
+			want:  Breakdown{Name: "VAT", Rate: 10, Net: 5, Tax: 1, Total: 6},
+		},
+		{
+			name:  "fractional rate is applied exactly",
+			price: 1000,
+			rule:  Rule{Name: "Sales tax", Rate: 8.875},
+			want:  Breakdown{Name: "Sales tax", Rate: 8.875, Net: 1000, Tax: 89, Total: 1089},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Apply(tt.price, tt.rule)
+			if got != tt.want {
+				t.Fatalf("Apply(%v, %+v) = %+v, want %+v", tt.price, tt.rule, got, tt.want)
+			}
+		})
+	}
+}
+
+type fakeRules map[pgstore.FindTaxRuleParams]*pgstore.TaxRule
+
+func (f fakeRules) FindTaxRule(ctx context.Context, arg pgstore.FindTaxRuleParams) (*pgstore.TaxRule, error) {
+	record, ok := f[arg]
+	if !ok {
+		return nil, pgx.ErrNoRows
+	}
+	return record, nil
+}
+
+type failingRules struct{}
+
+func (failingRules) FindTaxRule(ctx context.Context, arg pgstore.FindTaxRuleParams) (*pgstore.TaxRule, error) {
+	return nil, errors.New("connection refused")
+}
+
+func TestCalculate(t *testing.T) {
+	rules := fakeRules{
+		{Region: "PT", Category: "books"}: {Region: "PT", Category: "books", Name: "IVA reduced", Rate: 6, Inclusive: true},
+		{Region: "PT", Category: "*"}:     {Region: "PT", Category: "*", Name: "IVA", Rate: 23, Inclusive: true},
+		{Region: "US", Category: "*"}:     {Region: "US", Category: "*", Name: "Sales tax", Rate: 8},
+	}
+
+	tests := []struct {
+		name     string
+		rules    RuleStore
+		region   string
+		category string
+		price    money.Amount
+		want     Breakdown
+		wantErr  error
+	}{
+		{
+			name:     "exact category rule wins over wildcard",
+			rules:    rules,
+			region:   "PT",
+			category: "books",
+			price:    1060,
+			want:     Breakdown{Name: "IVA reduced", Rate: 6, Inclusive: true, Net: 1000, Tax: 60, Total: 1060},
+		},
+		{
+			name:     "falls back to wildcard category",
+			rules:    rules,
+			region:   "PT",
+			category: "electronics",
+			price:    12300,
+			want:     Breakdown{Name: "IVA", Rate: 23, Inclusive: true, Net: 10000, Tax: 2300, Total: 12300},
+		},
+		{
+			name:     "wildcard exclusive rule",
+			rules:    rules,
+			region:   "US",
+			category: "general",
+			price:    5000,
+			want:     Breakdown{Name: "Sales tax", Rate: 8, Net: 5000, Tax: 400, Total: 5400},
+		},
+		{
+			name:     "missing rule is an error",
+			rules:    rules,
+			region:   "BR",
+			category: "general",
+			price:    5000,
+			wantErr:  ErrNoRule,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewCalculator(tt.rules).Calculate(context.Background(), tt.region, tt.category, tt.price)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Calculate() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Calculate() unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("Calculate() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("store failure is not reported as a missing rule", func(t *testing.T) {
+		_, err := NewCalculator(failingRules{}).Calculate(context.Background(), "PT", "books", 1000)
+		if err == nil || errors.Is(err, ErrNoRule) {
+			t.Fatalf("Calculate() error = %v, want a store error", err)
+		}
+	})
+}
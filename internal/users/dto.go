@@ -9,12 +9,37 @@ import (
 )
 
 type UsersResponse struct {
-	ID        uuid.UUID `json:"id"`
-	Username  string    `json:"username"`
-	Email     string    `json:"email"`
-	Bio       string    `json:"bio"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID                   uuid.UUID        `json:"id"`
+	Username             string           `json:"username"`
+	Email                string           `json:"email"`
+	EmailVerifiedAt      *time.Time       `json:"email_verified_at,omitempty"`
+	Bio                  string           `json:"bio"`
+	AvatarURL            string           `json:"avatar_url,omitempty"`
+	AvatarThumbnailURL   string           `json:"avatar_thumbnail_url,omitempty"`
+	DeletionScheduledFor *time.Time       `json:"deletion_scheduled_for,omitempty"`
+	Privacy              *PrivacyResponse `json:"privacy,omitempty"`
+	CreatedAt            time.Time        `json:"created_at"`
+	UpdatedAt            time.Time        `json:"updated_at"`
+}
+
+type PrivacyResponse struct {
+	ShowEmail         bool `json:"show_email"`
+	ShowEmailVerified bool `json:"show_email_verified"`
+	ShowSalesHistory  bool `json:"show_sales_history"`
+}
+
+type PublicProfileResponse struct {
+	ID                 uuid.UUID `json:"id"`
+	Username           string    `json:"username"`
+	Bio                string    `json:"bio"`
+	AvatarURL          string    `json:"avatar_url,omitempty"`
+	AvatarThumbnailURL string    `json:"avatar_thumbnail_url,omitempty"`
+	MemberSince        time.Time `json:"member_since"`
+	SellerRating       *float64  `json:"seller_rating"`
+	ActiveListings     int64     `json:"active_listings_count"`
+	Email              string    `json:"email,omitempty"`
+	EmailVerified      *bool     `json:"email_verified,omitempty"`
+	CompletedSales     *int64    `json:"completed_sales,omitempty"`
 }
 
 type UpdateReq struct {
@@ -36,3 +61,19 @@ func (r *UpdateReq) Valid(ctx context.Context) validator.Evaluator {
 
 	return eval
 }
+
+type PrivacyReq struct {
+	ShowEmail         *bool `json:"show_email"`
+	ShowEmailVerified *bool `json:"show_email_verified"`
+	ShowSalesHistory  *bool `json:"show_sales_history"`
+}
+
+func (r *PrivacyReq) Valid(ctx context.Context) validator.Evaluator {
+	var eval validator.Evaluator
+
+	eval.CheckField(r.ShowEmail != nil, "show_email", "this field is required")
+	eval.CheckField(r.ShowEmailVerified != nil, "show_email_verified", "this field is required")
+	eval.CheckField(r.ShowSalesHistory != nil, "show_sales_history", "this field is required")
+
+	return eval
+}
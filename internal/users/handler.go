@@ -2,92 +2,235 @@ package users
 
 import (
 	"errors"
+	"math"
 	"net/http"
 
 	"github.com/EduardoMark/gobid/internal/api/middlewares"
+	"github.com/EduardoMark/gobid/internal/auth/session"
 	"github.com/EduardoMark/gobid/internal/auth/token"
 	"github.com/EduardoMark/gobid/internal/jsonutils"
+	"github.com/EduardoMark/gobid/internal/media"
+	"github.com/EduardoMark/gobid/internal/policy"
+	"github.com/EduardoMark/gobid/internal/store/pgstore"
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
 )
 
 type UserHandler struct {
 	s          Service
 	jwtService token.JwtService
+	sessions   session.Checker
+	policy     policy.Policy
 }
 
-func NewUserHandler(s Service, jwtService token.JwtService) UserHandler {
+func NewUserHandler(s Service, jwtService token.JwtService, sessions session.Checker, owners policy.Policy) UserHandler {
 	return UserHandler{
 		s:          s,
 		jwtService: jwtService,
+		sessions:   sessions,
+		policy:     owners,
 	}
 }
 
 func (m *UserHandler) RegisterUserRoutes(r chi.Router) {
 	r.Route("/users", func(r chi.Router) {
 		r.Group(func(r chi.Router) {
-			r.Use(middlewares.AuthToken(m.jwtService))
+			r.Use(middlewares.AuthToken(m.jwtService, m.sessions))
+
+			r.Get("/me", m.GetMe)
+			r.Put("/me", m.UpdateMe)
+			r.Delete("/me", m.DeleteMe)
+			r.Post("/me/cancel-deletion", m.CancelMyDeletion)
+			r.Put("/me/privacy", m.UpdatePrivacy)
+			r.Put("/me/avatar", m.SetAvatar)
+			r.Delete("/me/avatar", m.RemoveAvatar)
 
 			r.Get("/{id}", m.GetOne)
 			r.Put("/{id}", m.Update)
 			r.Delete("/{id}", m.Delete)
+			r.Post("/{id}/cancel-deletion", m.CancelDeletion)
 		})
 	})
 }
 
-func (m *UserHandler) GetOne(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
-	id := chi.URLParam(r, "id")
+func (m *UserHandler) GetMe(w http.ResponseWriter, r *http.Request) {
+	userID, ok := m.subject(w, r)
+	if !ok {
+		return
+	}
 
-	parsedID, err := uuid.Parse(id)
+	record, err := m.s.GetOneUser(r.Context(), userID)
 	if err != nil {
-		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
-			"error": "invalid uuid type",
-		})
+		writeServiceError(w, r, "Handler.GetMe", err)
 		return
 	}
 
-	record, err := m.s.GetOneUser(ctx, parsedID)
+	jsonutils.EncodeJson(w, r, http.StatusOK, map[string]any{
+		"user": toUsersResponse(record),
+	})
+}
+
+func (m *UserHandler) UpdateMe(w http.ResponseWriter, r *http.Request) {
+	userID, ok := m.subject(w, r)
+	if !ok {
+		return
+	}
+
+	m.update(w, r, userID)
+}
+
+func (m *UserHandler) DeleteMe(w http.ResponseWriter, r *http.Request) {
+	userID, ok := m.subject(w, r)
+	if !ok {
+		return
+	}
+
+	m.requestDeletion(w, r, userID)
+}
+
+func (m *UserHandler) CancelMyDeletion(w http.ResponseWriter, r *http.Request) {
+	userID, ok := m.subject(w, r)
+	if !ok {
+		return
+	}
+
+	m.cancelDeletion(w, r, userID)
+}
+
+func (m *UserHandler) UpdatePrivacy(w http.ResponseWriter, r *http.Request) {
+	userID, ok := m.subject(w, r)
+	if !ok {
+		return
+	}
+
+	data, problems, err := jsonutils.DecodeValidJson[*PrivacyReq](r)
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+
+	record, err := m.s.UpdatePrivacy(r.Context(), userID, PrivacySettings{
+		ShowEmail:         *data.ShowEmail,
+		ShowEmailVerified: *data.ShowEmailVerified,
+		ShowSalesHistory:  *data.ShowSalesHistory,
+	})
+	if err != nil {
+		writeServiceError(w, r, "Handler.UpdatePrivacy", err)
+		return
+	}
+
+	jsonutils.EncodeJson(w, r, http.StatusOK, map[string]any{
+		"user": toUsersResponse(record),
+	})
+}
+
+func (m *UserHandler) SetAvatar(w http.ResponseWriter, r *http.Request) {
+	userID, ok := m.subject(w, r)
+	if !ok {
+		return
+	}
+
+	img, err := media.ReadUpload(w, r, "file")
 	if err != nil {
-		if errors.Is(err, ErrNotFound) {
-			jsonutils.EncodeJson(w, r, http.StatusNotFound, map[string]any{
-				"error": "user not found",
-			})
+		if media.WriteUploadError(w, r, err) {
 			return
 		}
 
-		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
-			"error": "unexpected internal server error",
-		})
+		writeServiceError(w, r, "Handler.SetAvatar", err)
 		return
 	}
 
-	res := UsersResponse{
-		ID:        record.ID,
-		Username:  record.Username,
-		Email:     record.Email,
-		Bio:       record.Bio,
-		CreatedAt: record.CreatedAt,
-		UpdatedAt: record.UpdatedAt,
+	record, err := m.s.SetAvatar(r.Context(), userID, img)
+	if err != nil {
+		writeServiceError(w, r, "Handler.SetAvatar", err)
+		return
 	}
 
 	jsonutils.EncodeJson(w, r, http.StatusOK, map[string]any{
-		"user": res,
+		"user": toUsersResponse(record),
 	})
 }
 
-func (m *UserHandler) Update(w http.ResponseWriter, r *http.Request) {
+func (m *UserHandler) RemoveAvatar(w http.ResponseWriter, r *http.Request) {
+	userID, ok := m.subject(w, r)
+	if !ok {
+		return
+	}
+
+	if _, err := m.s.RemoveAvatar(r.Context(), userID); err != nil {
+		writeServiceError(w, r, "Handler.RemoveAvatar", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (m *UserHandler) GetOne(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	id := chi.URLParam(r, "id")
 
-	parsedID, err := uuid.Parse(id)
+	parsedID, ok := parseID(w, r)
+	if !ok {
+		return
+	}
+
+	record, err := m.s.GetOneUser(ctx, parsedID)
 	if err != nil {
-		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
-			"error": "invalid uuid type",
-		})
+		writeServiceError(w, r, "Handler.GetOne", err)
+		return
+	}
+
+	stats, err := m.s.GetSellerStats(ctx, parsedID)
+	if err != nil {
+		writeServiceError(w, r, "Handler.GetOne", err)
 		return
 	}
 
+	jsonutils.EncodeJson(w, r, http.StatusOK, map[string]any{
+		"user": toPublicProfileResponse(record, stats),
+	})
+}
+
+func (m *UserHandler) Update(w http.ResponseWriter, r *http.Request) {
+	parsedID, ok := parseID(w, r)
+	if !ok {
+		return
+	}
+
+	if !m.authorizeOwner(w, r, parsedID) {
+		return
+	}
+
+	m.update(w, r, parsedID)
+}
+
+func (m *UserHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	parsedID, ok := parseID(w, r)
+	if !ok {
+		return
+	}
+
+	if !m.authorizeOwner(w, r, parsedID) {
+		return
+	}
+
+	m.requestDeletion(w, r, parsedID)
+}
+
+func (m *UserHandler) CancelDeletion(w http.ResponseWriter, r *http.Request) {
+	parsedID, ok := parseID(w, r)
+	if !ok {
+		return
+	}
+
+	if !m.authorizeOwner(w, r, parsedID) {
+		return
+	}
+
+	m.cancelDeletion(w, r, parsedID)
+}
+
+func (m *UserHandler) update(w http.ResponseWriter, r *http.Request, id uuid.UUID) {
 	data, problems, err := jsonutils.DecodeValidJson[*UpdateReq](r)
 	if err != nil {
 		jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
@@ -95,30 +238,14 @@ func (m *UserHandler) Update(w http.ResponseWriter, r *http.Request) {
 	}
 
 	updatedRecord, err := m.s.UpdateUser(
-		ctx,
-		parsedID,
+		r.Context(),
+		id,
 		data.Username,
 		data.Email,
 		data.Bio,
 	)
 	if err != nil {
-		if errors.Is(err, ErrNotFound) {
-			jsonutils.EncodeJson(w, r, http.StatusNotFound, map[string]any{
-				"error": "user not found",
-			})
-			return
-		}
-
-		if errors.Is(err, ErrEmailAlreadyExists) {
-			jsonutils.EncodeJson(w, r, http.StatusConflict, map[string]any{
-				"error": "email already exists",
-			})
-			return
-		}
-
-		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
-			"error": "unexpected internal server error",
-		})
+		writeServiceError(w, r, "Handler.Update", err)
 		return
 	}
 
@@ -136,24 +263,166 @@ func (m *UserHandler) Update(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
-func (m *UserHandler) Delete(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
-	id := chi.URLParam(r, "id")
+func (m *UserHandler) requestDeletion(w http.ResponseWriter, r *http.Request, id uuid.UUID) {
+	scheduledFor, err := m.s.RequestDeletion(r.Context(), id)
+	if err != nil {
+		writeServiceError(w, r, "Handler.Delete", err)
+		return
+	}
+
+	jsonutils.EncodeJson(w, r, http.StatusAccepted, map[string]any{
+		"deletion_scheduled_for": scheduledFor,
+	})
+}
+
+func (m *UserHandler) cancelDeletion(w http.ResponseWriter, r *http.Request, id uuid.UUID) {
+	if err := m.s.CancelDeletion(r.Context(), id); err != nil {
+		writeServiceError(w, r, "Handler.CancelDeletion", err)
+		return
+	}
 
-	parsedID, err := uuid.Parse(id)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (m *UserHandler) subject(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	userID, err := m.policy.Subject(r.Context())
 	if err != nil {
-		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
-			"error": "invalid uuid type",
+		jsonutils.EncodeJson(w, r, http.StatusUnauthorized, map[string]any{
+			"error": "unauthorized",
 		})
-		return
+		return uuid.UUID{}, false
 	}
 
-	if err := m.s.DeleteUser(ctx, parsedID); err != nil {
+	return userID, true
+}
+
+func (m *UserHandler) authorizeOwner(w http.ResponseWriter, r *http.Request, owner uuid.UUID) bool {
+	err := m.policy.AuthorizeOwner(r.Context(), owner)
+	switch {
+	case err == nil:
+		return true
+	case errors.Is(err, policy.ErrUnauthenticated):
+		jsonutils.EncodeJson(w, r, http.StatusUnauthorized, map[string]any{
+			"error": "unauthorized",
+		})
+	case errors.Is(err, policy.ErrForbidden):
+		jsonutils.EncodeJson(w, r, http.StatusForbidden, map[string]any{
+			"error": "forbidden",
+		})
+	default:
+		logrus.WithField("err", err.Error()).Error("Handler.authorizeOwner")
+
 		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
 			"error": "unexpected internal server error",
 		})
-		return
 	}
 
-	w.WriteHeader(http.StatusNoContent)
+	return false
+}
+
+func writeServiceError(w http.ResponseWriter, r *http.Request, op string, err error) {
+	switch {
+	case errors.Is(err, ErrNotFound):
+		jsonutils.EncodeJson(w, r, http.StatusNotFound, map[string]any{
+			"error": "user not found",
+		})
+	case errors.Is(err, ErrEmailAlreadyExists):
+		jsonutils.EncodeJson(w, r, http.StatusConflict, map[string]any{
+			"error": "email already exists",
+		})
+	case errors.Is(err, ErrDeletionAlreadyRequested):
+		jsonutils.EncodeJson(w, r, http.StatusConflict, map[string]any{
+			"error": "account deletion already requested",
+		})
+	case errors.Is(err, ErrNoDeletionRequested):
+		jsonutils.EncodeJson(w, r, http.StatusConflict, map[string]any{
+			"error": "no account deletion requested",
+		})
+	default:
+		logrus.WithField("err", err.Error()).Error(op)
+
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "unexpected internal server error",
+		})
+	}
+}
+
+func parseID(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	parsedID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "invalid uuid type",
+		})
+		return uuid.UUID{}, false
+	}
+
+	return parsedID, true
+}
+
+func toUsersResponse(record *pgstore.User) UsersResponse {
+	res := UsersResponse{
+		ID:                 record.ID,
+		Username:           record.Username,
+		Email:              record.Email,
+		Bio:                record.Bio,
+		AvatarURL:          media.URL(record.AvatarKey),
+		AvatarThumbnailURL: media.URL(record.AvatarThumbnailKey),
+		Privacy: &PrivacyResponse{
+			ShowEmail:         record.ShowEmail,
+			ShowEmailVerified: record.ShowEmailVerified,
+			ShowSalesHistory:  record.ShowSalesHistory,
+		},
+		CreatedAt: record.CreatedAt,
+		UpdatedAt: record.UpdatedAt,
+	}
+
+	if record.EmailVerifiedAt.Valid {
+		res.EmailVerifiedAt = &record.EmailVerifiedAt.Time
+	}
+
+	if record.DeletionRequestedAt.Valid {
+		scheduledFor := record.DeletionRequestedAt.Time.Add(DeletionGracePeriod)
+		res.DeletionScheduledFor = &scheduledFor
+	}
+
+	return res
+}
+
+func toPublicProfileResponse(record *pgstore.User, stats *pgstore.GetSellerStatsRow) PublicProfileResponse {
+	res := PublicProfileResponse{
+		ID:                 record.ID,
+		Username:           record.Username,
+		Bio:                record.Bio,
+		AvatarURL:          media.URL(record.AvatarKey),
+		AvatarThumbnailURL: media.URL(record.AvatarThumbnailKey),
+		MemberSince:        record.CreatedAt,
+		SellerRating:       sellerRating(stats),
+		ActiveListings:     stats.ActiveListings,
+	}
+
+	if record.ShowEmail {
+		res.Email = record.Email
+	}
+
+	if record.ShowEmailVerified {
+		verified := record.EmailVerifiedAt.Valid
+		res.EmailVerified = &verified
+	}
+
+	if record.ShowSalesHistory {
+		res.CompletedSales = &stats.CompletedSales
+	}
+
+	return res
+}
+
+func sellerRating(stats *pgstore.GetSellerStatsRow) *float64 {
+	if stats.CompletedSales == 0 {
+		return nil
+	}
+
+	undisputed := max(stats.CompletedSales-stats.DisputedSales, 0)
+	rating := math.Round(float64(undisputed)/float64(stats.CompletedSales)*50) / 10
+
+	return &rating
 }
@@ -0,0 +1,173 @@
+package users
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/EduardoMark/gobid/internal/auth/token"
+	"github.com/EduardoMark/gobid/internal/policy"
+	"github.com/EduardoMark/gobid/internal/policy/policytest"
+	"github.com/EduardoMark/gobid/internal/rbac"
+	"github.com/EduardoMark/gobid/internal/store/pgstore"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type unreachableService struct {
+	Service
+}
+
+type fakeService struct {
+	Service
+	users map[uuid.UUID]*pgstore.User
+}
+
+func (s fakeService) GetOneUser(ctx context.Context, id uuid.UUID) (*pgstore.User, error) {
+	record, ok := s.users[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return record, nil
+}
+
+func (s fakeService) GetSellerStats(ctx context.Context, id uuid.UUID) (*pgstore.GetSellerStatsRow, error) {
+	return &pgstore.GetSellerStatsRow{ActiveListings: 2, CompletedSales: 4, DisputedSales: 1}, nil
+}
+
+func (s fakeService) UpdateUser(ctx context.Context, id uuid.UUID, username, email, bio string) (*pgstore.UpdateUserRow, error) {
+	return &pgstore.UpdateUserRow{ID: id, Username: username, Email: email, Bio: bio}, nil
+}
+
+func (s fakeService) RequestDeletion(ctx context.Context, id uuid.UUID) (time.Time, error) {
+	return time.Now().Add(DeletionGracePeriod), nil
+}
+
+func (s fakeService) CancelDeletion(ctx context.Context, id uuid.UUID) error {
+	return nil
+}
+
+func newFakeService(ids ...uuid.UUID) fakeService {
+	s := fakeService{users: map[uuid.UUID]*pgstore.User{}}
+	for _, id := range ids {
+		s.users[id] = &pgstore.User{
+			ID:                    id,
+			Username:              "alice",
+			Email:                 "alice@example.com",
+			PasswordHash:          "$argon2id$secret",
+			Bio:                   "collects vintage cameras",
+			EmailVerifiedAt:       pgtype.Timestamptz{Valid: true},
+			SuspensionReason:      "chargeback review",
+			PasswordResetRequired: true,
+		}
+	}
+	return s
+}
+
+func TestUserRoutesEnforcePolicy(t *testing.T) {
+	h := NewUserHandler(unreachableService{}, policytest.JwtService(), policytest.Sessions(), policytest.Deny())
+
+	r := chi.NewRouter()
+	h.RegisterUserRoutes(r)
+
+	policytest.CheckRoutes(t, r, "GET /users/{id}")
+}
+
+func TestUserRoutesAllowOwners(t *testing.T) {
+	other := uuid.New()
+	update := `{"username":"alice","email":"alice@example.com","bio":"collects vintage cameras"}`
+
+	tests := []struct {
+		name   string
+		jwt    token.JwtService
+		policy policy.Policy
+		target uuid.UUID
+		want   int
+	}{
+		{
+			name:   "permitting policy",
+			jwt:    policytest.JwtService(),
+			policy: policytest.Allow(),
+			target: other,
+		},
+		{
+			name:   "owner",
+			jwt:    policytest.JwtService(),
+			policy: policy.NewOwnershipPolicy(),
+			target: policytest.Subject,
+		},
+		{
+			name:   "admin",
+			jwt:    policytest.JwtService(rbac.PermUsersManage),
+			policy: policy.NewOwnershipPolicy(),
+			target: other,
+		},
+		{
+			name:   "non-owner",
+			jwt:    policytest.JwtService(),
+			policy: policy.NewOwnershipPolicy(),
+			target: other,
+			want:   http.StatusForbidden,
+		},
+	}
+
+	routes := []struct {
+		method string
+		suffix string
+		body   string
+		want   int
+	}{
+		{http.MethodPut, "", update, http.StatusOK},
+		{http.MethodDelete, "", "", http.StatusAccepted},
+		{http.MethodPost, "/cancel-deletion", "", http.StatusNoContent},
+	}
+
+	for _, tt := range tests {
+		for _, route := range routes {
+			t.Run(tt.name+" "+route.method+" /users/{id}"+route.suffix, func(t *testing.T) {
+				h := NewUserHandler(newFakeService(policytest.Subject, other), tt.jwt, policytest.Sessions(), tt.policy)
+
+				r := chi.NewRouter()
+				h.RegisterUserRoutes(r)
+
+				want := route.want
+				if tt.want != 0 {
+					want = tt.want
+				}
+
+				rec := policytest.Do(r, route.method, "/users/"+tt.target.String()+route.suffix, "application/json", strings.NewReader(route.body))
+				if rec.Code != want {
+					t.Fatalf("status = %d, want %d: %s", rec.Code, want, rec.Body)
+				}
+			})
+		}
+	}
+}
+
+func TestGetUserHidesPrivateFields(t *testing.T) {
+	id := uuid.New()
+
+	h := NewUserHandler(newFakeService(id), policytest.JwtService(), policytest.Sessions(), policy.NewOwnershipPolicy())
+
+	r := chi.NewRouter()
+	h.RegisterUserRoutes(r)
+
+	rec := policytest.Do(r, http.MethodGet, "/users/"+id.String(), "", nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body)
+	}
+
+	body := rec.Body.String()
+	if !strings.Contains(body, `"username":"alice"`) {
+		t.Fatalf("public profile is missing the username: %s", body)
+	}
+
+	for _, private := range []string{"alice@example.com", "argon2id", "password", "chargeback review", "suspension", "email_verified"} {
+		if strings.Contains(body, private) {
+			t.Errorf("public profile exposes %q: %s", private, body)
+		}
+	}
+}
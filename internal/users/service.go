@@ -4,10 +4,16 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
+	"github.com/EduardoMark/gobid/internal/audit"
+	"github.com/EduardoMark/gobid/internal/lockout"
+	"github.com/EduardoMark/gobid/internal/media"
+	"github.com/EduardoMark/gobid/internal/storage"
 	"github.com/EduardoMark/gobid/internal/store/pgstore"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/sirupsen/logrus"
 )
@@ -15,21 +21,42 @@ import (
 type Service interface {
 	GetOneUser(ctx context.Context, id uuid.UUID) (*pgstore.User, error)
 	UpdateUser(ctx context.Context, id uuid.UUID, username, email, bio string) (*pgstore.UpdateUserRow, error)
-	DeleteUser(ctx context.Context, id uuid.UUID) error
+	RequestDeletion(ctx context.Context, id uuid.UUID) (time.Time, error)
+	CancelDeletion(ctx context.Context, id uuid.UUID) error
+	PurgeDeletedAccounts(ctx context.Context) (int, error)
+	IsEmailVerified(ctx context.Context, id uuid.UUID) (bool, error)
+	GetSellerStats(ctx context.Context, id uuid.UUID) (*pgstore.GetSellerStatsRow, error)
+	UpdatePrivacy(ctx context.Context, id uuid.UUID, settings PrivacySettings) (*pgstore.User, error)
+	SetAvatar(ctx context.Context, id uuid.UUID, img *media.Image) (*pgstore.User, error)
+	RemoveAvatar(ctx context.Context, id uuid.UUID) (*pgstore.User, error)
+}
+
+type PrivacySettings struct {
+	ShowEmail         bool
+	ShowEmailVerified bool
+	ShowSalesHistory  bool
 }
 
 type userService struct {
-	pool *pgxpool.Pool
-	q    *pgstore.Queries
+	pool  *pgxpool.Pool
+	q     *pgstore.Queries
+	blobs storage.BlobStore
+	audit audit.Recorder
 }
 
 var ErrNotFound = errors.New("not found")
 var ErrEmailAlreadyExists = errors.New("email already exists")
+var ErrDeletionAlreadyRequested = errors.New("account deletion already requested")
+var ErrNoDeletionRequested = errors.New("no account deletion requested")
+
+const DeletionGracePeriod = 30 * 24 * time.Hour
 
-func NewUserService(pool *pgxpool.Pool) Service {
+func NewUserService(pool *pgxpool.Pool, blobs storage.BlobStore, recorder audit.Recorder) Service {
 	return &userService{
-		pool: pool,
-		q:    pgstore.New(pool),
+		pool:  pool,
+		q:     pgstore.New(pool),
+		blobs: blobs,
+		audit: recorder,
 	}
 }
 
@@ -44,18 +71,17 @@ func (s *userService) GetOneUser(ctx context.Context, id uuid.UUID) (*pgstore.Us
 		return nil, fmt.Errorf("GetOneUser: %v", err)
 	}
 
+	if record.DeletedAt.Valid {
+		return nil, ErrNotFound
+	}
+
 	return record, nil
 }
 
 func (s *userService) UpdateUser(ctx context.Context, id uuid.UUID, username, email, bio string) (*pgstore.UpdateUserRow, error) {
-	_, err := s.q.GetUserByID(ctx, id)
+	current, err := s.GetOneUser(ctx, id)
 	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, ErrNotFound
-		}
-		logrus.WithField("err", err.Error()).Error("UpdateUser")
-
-		return nil, fmt.Errorf("UpdateUser: %v", err)
+		return nil, err
 	}
 
 	emailInUseByOtherUser, err := s.q.CheckEmailExistsExcludingID(ctx, pgstore.CheckEmailExistsExcludingIDParams{
@@ -77,20 +103,346 @@ func (s *userService) UpdateUser(ctx context.Context, id uuid.UUID, username, em
 		Bio:      bio,
 	}
 
-	record, err := s.q.UpdateUser(ctx, params)
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("UpdateUser: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	record, err := s.q.WithTx(tx).UpdateUser(ctx, params)
 	if err != nil {
 		logrus.WithField("err", err.Error()).Error("UpdateUser")
 		return nil, fmt.Errorf("UpdateUser: %v", err)
 	}
 
+	after := map[string]any{"fields": changedFields(current, record)}
+	if err := s.record(ctx, tx, id, audit.ActionProfileUpdate, nil, after); err != nil {
+		return nil, fmt.Errorf("UpdateUser: %v", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("UpdateUser: %v", err)
+	}
+
 	return record, nil
 }
 
-func (s *userService) DeleteUser(ctx context.Context, id uuid.UUID) error {
-	if err := s.q.DeleteUser(ctx, id); err != nil {
-		logrus.WithField("err", err.Error()).Error("DeleteUser")
-		return fmt.Errorf("DeleteUser: %v", err)
+func (s *userService) RequestDeletion(ctx context.Context, id uuid.UUID) (time.Time, error) {
+	if _, err := s.GetOneUser(ctx, id); err != nil {
+		return time.Time{}, err
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("RequestDeletion: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	requestedAt, err := s.q.WithTx(tx).ScheduleUserDeletion(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return time.Time{}, ErrDeletionAlreadyRequested
+		}
+		logrus.WithField("err", err.Error()).Error("RequestDeletion")
+		return time.Time{}, fmt.Errorf("RequestDeletion: %v", err)
+	}
+
+	scheduledFor := requestedAt.Time.Add(DeletionGracePeriod)
+	after := map[string]any{"deletion_scheduled_for": scheduledFor}
+	if err := s.record(ctx, tx, id, audit.ActionDeletionRequest, nil, after); err != nil {
+		return time.Time{}, fmt.Errorf("RequestDeletion: %v", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return time.Time{}, fmt.Errorf("RequestDeletion: %v", err)
+	}
+
+	return scheduledFor, nil
+}
+
+func (s *userService) CancelDeletion(ctx context.Context, id uuid.UUID) error {
+	if _, err := s.GetOneUser(ctx, id); err != nil {
+		return err
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("CancelDeletion: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	rows, err := s.q.WithTx(tx).CancelUserDeletion(ctx, id)
+	if err != nil {
+		logrus.WithField("err", err.Error()).Error("CancelDeletion")
+		return fmt.Errorf("CancelDeletion: %v", err)
+	}
+
+	if rows == 0 {
+		return ErrNoDeletionRequested
+	}
+
+	if err := s.record(ctx, tx, id, audit.ActionDeletionCancel, nil, nil); err != nil {
+		return fmt.Errorf("CancelDeletion: %v", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("CancelDeletion: %v", err)
 	}
 
 	return nil
 }
+
+func (s *userService) PurgeDeletedAccounts(ctx context.Context) (int, error) {
+	ids, err := s.q.ListDueUserDeletions(ctx, pgtype.Timestamptz{
+		Time:  time.Now().Add(-DeletionGracePeriod),
+		Valid: true,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("PurgeDeletedAccounts: %v", err)
+	}
+
+	purged := 0
+	for _, id := range ids {
+		if err := s.anonymise(ctx, id); err != nil {
+			return purged, fmt.Errorf("PurgeDeletedAccounts: %v", err)
+		}
+		purged++
+	}
+
+	return purged, nil
+}
+
+func (s *userService) anonymise(ctx context.Context, id uuid.UUID) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.q.WithTx(tx)
+
+	record, err := qtx.GetUserByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := qtx.ClearLoginAttempts(ctx, lockout.Account(record.Email, id).Key()); err != nil {
+		return err
+	}
+
+	scrubs := []func(context.Context, uuid.UUID) error{
+		qtx.DeleteUserAddresses,
+		qtx.DeleteUserIdentities,
+		qtx.DeleteUserSessions,
+		qtx.DeleteUserRefreshTokens,
+		qtx.DeleteUserTokens,
+		qtx.DeleteTOTP,
+		qtx.DeleteUserRecoveryCodes,
+		qtx.DeleteUserRoles,
+		qtx.DeleteUserLockouts,
+		qtx.DeleteUserDataExports,
+		qtx.DeleteUserAPIKeys,
+		qtx.ScrubBuyerShippingAddresses,
+		qtx.TakeDownSellerListings,
+	}
+	for _, scrub := range scrubs {
+		if err := scrub(ctx, id); err != nil {
+			return err
+		}
+	}
+
+	if _, err := qtx.AnonymiseUser(ctx, id); err != nil {
+		return err
+	}
+
+	if err := s.record(ctx, tx, id, audit.ActionAnonymise, nil, nil); err != nil {
+		return err
+	}
+
+	leaked, err := qtx.AuditEventsMention(ctx, record.Email)
+	if err != nil {
+		return err
+	}
+
+	if leaked {
+		logrus.WithField("user_id", id.String()).Error("anonymise - email still present in audit_events")
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+
+	media.Remove(ctx, s.blobs, record.AvatarKey, record.AvatarThumbnailKey)
+
+	return nil
+}
+
+func (s *userService) IsEmailVerified(ctx context.Context, id uuid.UUID) (bool, error) {
+	verified, err := s.q.IsEmailVerified(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, nil
+		}
+		logrus.WithField("err", err.Error()).Error("IsEmailVerified")
+		return false, fmt.Errorf("IsEmailVerified: %v", err)
+	}
+
+	return verified, nil
+}
+
+func (s *userService) GetSellerStats(ctx context.Context, id uuid.UUID) (*pgstore.GetSellerStatsRow, error) {
+	stats, err := s.q.GetSellerStats(ctx, id)
+	if err != nil {
+		logrus.WithField("err", err.Error()).Error("GetSellerStats")
+		return nil, fmt.Errorf("GetSellerStats: %v", err)
+	}
+
+	return stats, nil
+}
+
+func (s *userService) UpdatePrivacy(ctx context.Context, id uuid.UUID, settings PrivacySettings) (*pgstore.User, error) {
+	current, err := s.GetOneUser(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("UpdatePrivacy: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	record, err := s.q.WithTx(tx).UpdatePrivacySettings(ctx, pgstore.UpdatePrivacySettingsParams{
+		ID:                id,
+		ShowEmail:         settings.ShowEmail,
+		ShowEmailVerified: settings.ShowEmailVerified,
+		ShowSalesHistory:  settings.ShowSalesHistory,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		logrus.WithField("err", err.Error()).Error("UpdatePrivacy")
+		return nil, fmt.Errorf("UpdatePrivacy: %v", err)
+	}
+
+	if err := s.record(ctx, tx, id, audit.ActionPrivacyUpdate, privacy(current), privacy(record)); err != nil {
+		return nil, fmt.Errorf("UpdatePrivacy: %v", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("UpdatePrivacy: %v", err)
+	}
+
+	return record, nil
+}
+
+func (s *userService) SetAvatar(ctx context.Context, id uuid.UUID, img *media.Image) (*pgstore.User, error) {
+	current, err := s.GetOneUser(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	key, thumbKey, err := media.Save(ctx, s.blobs, "avatars/"+id.String(), img)
+	if err != nil {
+		logrus.WithField("err", err.Error()).Error("SetAvatar")
+		return nil, fmt.Errorf("SetAvatar: %v", err)
+	}
+
+	record, err := s.setAvatar(ctx, current, audit.ActionAvatarUpdate, key, thumbKey)
+	if err != nil {
+		media.Remove(ctx, s.blobs, key, thumbKey)
+
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		logrus.WithField("err", err.Error()).Error("SetAvatar")
+		return nil, fmt.Errorf("SetAvatar: %v", err)
+	}
+
+	media.Remove(ctx, s.blobs, current.AvatarKey, current.AvatarThumbnailKey)
+
+	return record, nil
+}
+
+func (s *userService) RemoveAvatar(ctx context.Context, id uuid.UUID) (*pgstore.User, error) {
+	current, err := s.GetOneUser(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	record, err := s.setAvatar(ctx, current, audit.ActionAvatarRemove, "", "")
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		logrus.WithField("err", err.Error()).Error("RemoveAvatar")
+		return nil, fmt.Errorf("RemoveAvatar: %v", err)
+	}
+
+	media.Remove(ctx, s.blobs, current.AvatarKey, current.AvatarThumbnailKey)
+
+	return record, nil
+}
+
+func (s *userService) setAvatar(ctx context.Context, current *pgstore.User, action, key, thumbKey string) (*pgstore.User, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	record, err := s.q.WithTx(tx).SetUserAvatar(ctx, pgstore.SetUserAvatarParams{
+		ID:                 current.ID,
+		AvatarKey:          key,
+		AvatarThumbnailKey: thumbKey,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	before := map[string]any{"avatar_key": current.AvatarKey}
+	after := map[string]any{"avatar_key": record.AvatarKey}
+	if err := s.record(ctx, tx, current.ID, action, before, after); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return record, nil
+}
+
+func (s *userService) record(ctx context.Context, tx pgx.Tx, id uuid.UUID, action string, before, after any) error {
+	return s.audit.WithTx(tx).Record(ctx, audit.Event{
+		Action:     action,
+		TargetType: audit.TargetUser,
+		TargetID:   id,
+		Before:     before,
+		After:      after,
+	})
+}
+
+func changedFields(current *pgstore.User, record *pgstore.UpdateUserRow) []string {
+	fields := []string{}
+	if current.Username != record.Username {
+		fields = append(fields, "username")
+	}
+	if current.Email != record.Email {
+		fields = append(fields, "email")
+	}
+	if current.Bio != record.Bio {
+		fields = append(fields, "bio")
+	}
+
+	return fields
+}
+
+func privacy(record *pgstore.User) map[string]any {
+	return map[string]any{
+		"show_email":          record.ShowEmail,
+		"show_email_verified": record.ShowEmailVerified,
+		"show_sales_history":  record.ShowSalesHistory,
+	}
+}
@@ -0,0 +1,56 @@
+package users
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/EduardoMark/gobid/internal/store/pgstore"
+)
+
+func TestChangedFields(t *testing.T) {
+	current := &pgstore.User{Username: "alice", Email: "alice@example.com", Bio: "hello"}
+
+	tests := []struct {
+		name   string
+		record *pgstore.UpdateUserRow
+		want   []string
+	}{
+		{
+			name:   "nothing changed",
+			record: &pgstore.UpdateUserRow{Username: "alice", Email: "alice@example.com", Bio: "hello"},
+			want:   []string{},
+		},
+		{
+			name:   "email changed",
+			record: &pgstore.UpdateUserRow{Username: "alice", Email: "alice@new.example.com", Bio: "hello"},
+			want:   []string{"email"},
+		},
+		{
+			name:   "everything changed",
+			record: &pgstore.UpdateUserRow{Username: "alicia", Email: "alicia@example.com", Bio: "bye"},
+			want:   []string{"username", "email", "bio"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := changedFields(current, tt.record)
+			if !slices.Equal(got, tt.want) {
+				t.Fatalf("changedFields() = %v, want %v", got, tt.want)
+			}
+
+			payload, err := json.Marshal(map[string]any{"fields": got})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for _, value := range []string{current.Username, current.Email, current.Bio, tt.record.Username, tt.record.Email, tt.record.Bio} {
+				if strings.Contains(string(payload), value) {
+					t.Fatalf("audit payload %s contains profile value %q", payload, value)
+				}
+			}
+		})
+	}
+}